@@ -13,7 +13,7 @@ import (
 	"timterests/internal/server"
 )
 
-func gracefulShutdown(apiServer *http.Server, done chan bool) {
+func gracefulShutdown(apiServer *http.Server, stopJobs context.CancelFunc, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -23,6 +23,9 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 
 	log.Println("shutting down gracefully, press Ctrl+C again to force")
 
+	// Stop the background jobs before the server, so none starts new work
+	stopJobs()
+
 	// The context is used to inform the server it has 5 seconds to finish
 	// the request it is currently handling
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -41,13 +44,17 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 
 func main() {
 	// Initialize the server
-	server := server.NewServer()
+	server, startJobs := server.NewServer()
+
+	// Run the background jobs until shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	startJobs(jobsCtx)
 
 	// Create a done channel to signal when the shutdown is complete
 	done := make(chan bool, 1)
 
 	// Run graceful shutdown in a separate goroutine
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(server, stopJobs, done)
 
 	certFile := os.Getenv("SSL_CERT_FILE")
 	keyFile := os.Getenv("SSL_KEY_FILE")
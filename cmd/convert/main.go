@@ -0,0 +1,117 @@
+// Package main provides a command that converts stored documents between the
+// .yaml/.md pair layout and single .md files with YAML front matter.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+
+	// Import godotenv so the command reads the same .env as the server.
+	_ "github.com/joho/godotenv/autoload"
+)
+
+const (
+	layoutFrontMatter = "frontmatter"
+	layoutPair        = "pair"
+)
+
+func main() {
+	to := flag.String("to", layoutFrontMatter, "layout to convert into: "+layoutFrontMatter+" or "+layoutPair)
+	dryRun := flag.Bool("dry-run", false, "list the documents that would change without writing anything")
+	flag.Parse()
+
+	if *to != layoutFrontMatter && *to != layoutPair {
+		log.Printf("unknown layout %q: want %s or %s", *to, layoutFrontMatter, layoutPair)
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	s, err := storage.NewBackend(ctx)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	types, err := service.LoadDocumentTypes(os.Getenv("DOCUMENT_TYPES_FILE"))
+	if err != nil {
+		log.Fatalf("Failed to load DOCUMENT_TYPES_FILE: %v", err)
+	}
+
+	converted, err := convertAll(ctx, s, types, *to == layoutFrontMatter, *dryRun)
+	if err != nil {
+		log.Fatalf("Conversion stopped: %v", err)
+	}
+
+	if *dryRun {
+		log.Printf("%d document(s) would be converted to %s", converted, *to)
+
+		return
+	}
+
+	log.Printf("converted %d document(s) to %s", converted, *to)
+}
+
+// convertAll converts every document of every type, the schema-declared ones
+// included, and returns how many changed. Documents already in the wanted layout are left alone.
+func convertAll(
+	ctx context.Context,
+	s storage.Backend,
+	types []model.DocumentSchema,
+	toSingleFile, dryRun bool,
+) (int, error) {
+	converted := 0
+
+	prefixes := []string{
+		service.ArticlesPrefix,
+		service.ProjectsPrefix,
+		service.ReadingListPrefix,
+		service.LettersPrefix,
+	}
+
+	for _, schema := range types {
+		prefixes = append(prefixes, schema.Name+"/")
+	}
+
+	for _, prefix := range prefixes {
+		keys, err := storage.DocumentKeys(ctx, s, prefix)
+		if err != nil {
+			return converted, err
+		}
+
+		for _, key := range keys {
+			single, err := storage.IsSingleFile(ctx, s, key)
+			if err != nil {
+				return converted, err
+			}
+
+			if single == toSingleFile {
+				continue
+			}
+
+			if dryRun {
+				log.Printf("would convert %s", key)
+
+				converted++
+
+				continue
+			}
+
+			_, err = storage.ConvertDocument(ctx, s, key, toSingleFile)
+			if err != nil {
+				return converted, err
+			}
+
+			log.Printf("converted %s", key)
+
+			converted++
+		}
+	}
+
+	return converted, nil
+}
@@ -1,124 +1,123 @@
 package web
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	apperrors "timterests/internal/errors"
 	"timterests/internal/storage"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/a-h/templ"
 )
 
 type Experience struct {
-	Company     string `yaml:"company"`
-	Role        string `yaml:"role"`
-	StartDate   string `yaml:"startDate"`
-	EndDate     string `yaml:"endDate"`
-	Description string `yaml:"description"`
-	Location    string `yaml:"location"`
+	Company     string `json:"company" yaml:"company"`
+	Role        string `json:"role" yaml:"role"`
+	StartDate   string `json:"startDate" yaml:"startDate"`
+	EndDate     string `json:"endDate" yaml:"endDate"`
+	Description string `json:"description" yaml:"description"`
+	Location    string `json:"location" yaml:"location"`
 }
 
 type Education struct {
-	Institution string `yaml:"institution"`
-	Degree      string `yaml:"degree"`
-	StartDate   string `yaml:"startDate"`
-	EndDate     string `yaml:"endDate"`
-	Description string `yaml:"description"`
-	Location    string `yaml:"location"`
+	Institution string `json:"institution" yaml:"institution"`
+	Degree      string `json:"degree" yaml:"degree"`
+	StartDate   string `json:"startDate" yaml:"startDate"`
+	EndDate     string `json:"endDate" yaml:"endDate"`
+	Description string `json:"description" yaml:"description"`
+	Location    string `json:"location" yaml:"location"`
 }
 
 type Skill struct {
-	Name        string   `yaml:"name"`
-	Items       []string `yaml:"items"`
-	Description string   `yaml:"description"`
+	Name        string   `json:"name" yaml:"name"`
+	Items       []string `json:"items" yaml:"items"`
+	Description string   `json:"description" yaml:"description"`
 }
 
 type About struct {
-	Title      string       `yaml:"title"`
-	Subtitle   string       `yaml:"subtitle"`
-	Body       string       `yaml:"-"`
-	Name       string       `yaml:"name"`
-	Specialty  string       `yaml:"specialty"`
-	Location   string       `yaml:"location"`
-	GitHub     string       `yaml:"github"`
-	Email      string       `yaml:"email"`
-	Experience []Experience `yaml:"experience"`
-	Education  []Education  `yaml:"education"`
-	Skills     []Skill      `yaml:"skills"`
+	Title      string       `json:"title" yaml:"title"`
+	Subtitle   string       `json:"subtitle" yaml:"subtitle"`
+	Body       string       `json:"-" yaml:"-"`
+	Name       string       `json:"name" yaml:"name"`
+	Specialty  string       `json:"specialty" yaml:"specialty"`
+	Location   string       `json:"location" yaml:"location"`
+	GitHub     string       `json:"github" yaml:"github"`
+	Email      string       `json:"email" yaml:"email"`
+	Experience []Experience `json:"experience" yaml:"experience"`
+	Education  []Education  `json:"education" yaml:"education"`
+	Skills     []Skill      `json:"skills" yaml:"skills"`
+	// Key is where the profile is stored, for reading its raw body.
+	Key string `json:"-" yaml:"-"`
 }
 
-func AboutHandler(w http.ResponseWriter, r *http.Request, s storage.Storage) {
-	var about About
-
-	prefix := "about/"
-
-	aboutFile, err := s.ListObjects(r.Context(), prefix)
+func AboutHandler(w http.ResponseWriter, r *http.Request, s storage.Backend) {
+	about, err := loadAbout(r.Context(), s)
 	if err != nil {
-		HandleError(w, r, apperrors.StorageFailed(err), "AboutHandler", "listObjects")
+		HandleError(w, r, err, "AboutHandler", "loadAbout")
 
 		return
 	}
 
-	if len(aboutFile) == 0 {
-		HandleError(w, r, apperrors.NotFound(nil), "AboutHandler", "findAbout")
+	var component templ.Component
 
-		return
+	switch r.URL.Query().Get("tab") {
+	case "bio":
+		component = BioTab(about)
+	case "education":
+		component = EducationTab(about.Education)
+	case "work":
+		component = ExperienceTab(about.Experience)
+	case "skills":
+		component = SkillsTab(about.Skills)
+	default:
+		component = AboutForm(about)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "AboutHandler", "render")
+	}
+}
+
+// loadAbout reads the about profile from the first YAML document under about/,
+// with its rendered body.
+func loadAbout(ctx context.Context, s storage.Backend) (About, error) {
+	var about About
+
+	aboutFile, err := s.ListObjects(ctx, "about/")
+	if err != nil {
+		return about, apperrors.StorageFailed(err)
 	}
 
 	var key string
 
 	for _, obj := range aboutFile {
-		k := aws.ToString(obj.Key)
-		if strings.HasSuffix(k, ".yaml") {
-			key = k
+		if strings.HasSuffix(obj.Key, ".yaml") {
+			key = obj.Key
 
 			break
 		}
 	}
 
 	if key == "" {
-		HandleError(w, r, apperrors.NotFound(nil), "AboutHandler", "getKey")
-
-		return
+		return about, apperrors.NotFound(nil)
 	}
 
-	err = s.GetPreparedFile(r.Context(), key, &about)
+	err = storage.GetPreparedFile(ctx, s, key, &about)
 	if err != nil {
-		HandleError(w, r, apperrors.StorageFailed(err), "AboutHandler", "getPreparedFile")
-
-		return
+		return about, apperrors.StorageFailed(err)
 	}
 
-	body, err := s.GetDocumentBody(r.Context(), key)
+	body, err := storage.GetDocumentBody(ctx, s, key)
 	if err != nil {
-		HandleError(w, r, apperrors.StorageFailed(err), "AboutHandler", "getDocumentBody")
-
-		return
+		return about, apperrors.StorageFailed(err)
 	}
 
+	about.Key = key
 	about.Body = body
 	about.GitHub = strings.TrimSpace(about.GitHub)
 	about.Email = strings.TrimSpace(about.Email)
 
-	var component templ.Component
-
-	switch r.URL.Query().Get("tab") {
-	case "bio":
-		component = BioTab(about)
-	case "education":
-		component = EducationTab(about.Education)
-	case "work":
-		component = ExperienceTab(about.Experience)
-	case "skills":
-		component = SkillsTab(about.Skills)
-	default:
-		component = AboutForm(about)
-	}
-
-	err = renderHTML(w, r, http.StatusOK, component)
-	if err != nil {
-		HandleError(w, r, apperrors.RenderFailed(err), "AboutHandler", "render")
-	}
+	return about, nil
 }
@@ -13,13 +13,13 @@ import (
 )
 
 func TestAboutHandler(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("renders about page", func(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/about", nil)
 		rec := httptest.NewRecorder()
 
-		web.AboutHandler(rec, req, *s)
+		web.AboutHandler(rec, req, s)
 
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
@@ -43,7 +43,7 @@ func TestAboutHandler(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/about?tab=bio", nil)
 		rec := httptest.NewRecorder()
 
-		web.AboutHandler(rec, req, *s)
+		web.AboutHandler(rec, req, s)
 
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
@@ -10,10 +10,10 @@ func ArticleCard(a model.Article) components.Card {
 	return components.Card{
 		Title:     a.Title,
 		Subtitle:  a.Subtitle,
-		Date:      a.Date,
+		Date:      displayDate(a.Date),
 		Preview:   a.Preview,
 		ImagePath: "",
-		Get:       "/article?id=" + a.ID,
+		Get:       DocumentURL("articles", a.ID),
 		Tags:      a.Tags,
 	}
 }
@@ -23,10 +23,10 @@ func ProjectCard(p model.Project) components.Card {
 	return components.Card{
 		Title:     p.Title,
 		Subtitle:  p.Subtitle,
-		Date:      p.Timespan(),
+		Date:      p.Timespan(dateLayout()),
 		Preview:   p.Preview,
 		ImagePath: p.Image,
-		Get:       "/project?id=" + p.ID,
+		Get:       DocumentURL("projects", p.ID),
 		Tags:      p.Tags,
 	}
 }
@@ -36,10 +36,10 @@ func LetterCard(l model.Letter) components.Card {
 	return components.Card{
 		Title:     l.Title,
 		Subtitle:  l.Subtitle,
-		Date:      l.Date,
+		Date:      displayDate(l.Date),
 		Preview:   l.Preview,
 		ImagePath: "",
-		Get:       "/letter?id=" + l.ID,
+		Get:       DocumentURL("letters", l.ID),
 		Tags:      l.Tags,
 	}
 }
@@ -49,10 +49,10 @@ func BookCard(r model.ReadingList) components.Card {
 	return components.Card{
 		Title:     r.Title,
 		Subtitle:  r.Subtitle,
-		Date:      "",
+		Date:      displayDate(r.Finished),
 		Preview:   r.Preview,
 		ImagePath: r.Image,
-		Get:       "/book?id=" + r.ID,
+		Get:       DocumentURL("reading-list", r.ID),
 		Tags:      r.Tags,
 	}
 }
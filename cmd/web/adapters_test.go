@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 	"timterests/cmd/web"
+	"timterests/internal/model"
 	"timterests/internal/service"
 )
 
@@ -11,10 +12,10 @@ import (
 // converted to Card components by the adapter functions.
 func TestArticleAdapters(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("ArticleCard preserves all fields", func(t *testing.T) {
-		ma, err := service.GetArticle(ctx, *s, "articles/test-article.yaml", 1)
+		ma, err := service.GetArticle(ctx, s, "articles/test-article.yaml")
 		if err != nil {
 			t.Fatalf("service.GetArticle failed: %v", err)
 		}
@@ -29,13 +30,13 @@ func TestArticleAdapters(t *testing.T) {
 			t.Errorf("Subtitle mismatch: got %q, want %q", card.Subtitle, ma.Subtitle)
 		}
 
-		if card.Date != ma.Date {
+		if card.Date != string(ma.Date) {
 			t.Errorf("Date mismatch: got %q, want %q", card.Date, ma.Date)
 		}
 	})
 
 	t.Run("ArticleCard from slice preserves length", func(t *testing.T) {
-		mas, err := service.ListArticles(ctx, *s, "all")
+		mas, err := service.ListArticles(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("service.ListArticles failed: %v", err)
 		}
@@ -51,14 +52,14 @@ func TestArticleAdapters(t *testing.T) {
 	})
 
 	t.Run("ArticleCard produces correct URL", func(t *testing.T) {
-		ma, err := service.GetArticle(ctx, *s, "articles/test-article.yaml", 5)
+		ma, err := service.GetArticle(ctx, s, "articles/test-article.yaml")
 		if err != nil {
 			t.Fatalf("service.GetArticle failed: %v", err)
 		}
 
 		card := web.ArticleCard(*ma)
 
-		expectedURL := "/article?id=5"
+		expectedURL := "/articles/test-article"
 		if card.Get != expectedURL {
 			t.Errorf("card URL: got %q, want %q", card.Get, expectedURL)
 		}
@@ -67,7 +68,7 @@ func TestArticleAdapters(t *testing.T) {
 			t.Errorf("article card should have no ImagePath, got %q", card.ImagePath)
 		}
 
-		if card.Date != ma.Date {
+		if card.Date != string(ma.Date) {
 			t.Errorf("card date: got %q, want %q", card.Date, ma.Date)
 		}
 	})
@@ -76,10 +77,10 @@ func TestArticleAdapters(t *testing.T) {
 // TestProjectAdapters verifies project card conversion.
 func TestProjectAdapters(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("ProjectCard preserves all fields", func(t *testing.T) {
-		mp, err := service.GetProject(ctx, *s, "projects/test-project.yaml", 2)
+		mp, err := service.GetProject(ctx, s, "projects/test-project.yaml")
 		if err != nil {
 			t.Fatalf("service.GetProject failed: %v", err)
 		}
@@ -96,7 +97,7 @@ func TestProjectAdapters(t *testing.T) {
 	})
 
 	t.Run("ProjectCard from slice preserves length", func(t *testing.T) {
-		mps, err := service.ListProjects(ctx, *s, "all")
+		mps, err := service.ListProjects(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("service.ListProjects failed: %v", err)
 		}
@@ -112,14 +113,14 @@ func TestProjectAdapters(t *testing.T) {
 	})
 
 	t.Run("ProjectCard produces correct URL and ImagePath", func(t *testing.T) {
-		mp, err := service.GetProject(ctx, *s, "projects/test-project.yaml", 3)
+		mp, err := service.GetProject(ctx, s, "projects/test-project.yaml")
 		if err != nil {
 			t.Fatalf("service.GetProject failed: %v", err)
 		}
 
 		card := web.ProjectCard(*mp)
 
-		expectedURL := "/project?id=3"
+		expectedURL := "/projects/test-project"
 		if card.Get != expectedURL {
 			t.Errorf("card URL: got %q, want %q", card.Get, expectedURL)
 		}
@@ -128,7 +129,7 @@ func TestProjectAdapters(t *testing.T) {
 			t.Errorf("card ImagePath: got %q, want %q", card.ImagePath, mp.Image)
 		}
 
-		expectedDate := mp.Timespan()
+		expectedDate := mp.Timespan(model.DateLayout)
 		if card.Date != expectedDate {
 			t.Errorf("card Date: got %q, want %q", card.Date, expectedDate)
 		}
@@ -138,10 +139,10 @@ func TestProjectAdapters(t *testing.T) {
 // TestLetterAdapters verifies letter card conversion.
 func TestLetterAdapters(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("LetterCard preserves all fields", func(t *testing.T) {
-		ml, err := service.GetLetter(ctx, *s, "letters/test-letter.yaml", 1)
+		ml, err := service.GetLetter(ctx, s, "letters/test-letter.yaml")
 		if err != nil {
 			t.Fatalf("service.GetLetter failed: %v", err)
 		}
@@ -152,13 +153,13 @@ func TestLetterAdapters(t *testing.T) {
 			t.Errorf("Title mismatch: got %q, want %q", card.Title, ml.Title)
 		}
 
-		if card.Date != ml.Date {
+		if card.Date != string(ml.Date) {
 			t.Errorf("Date mismatch: got %q, want %q", card.Date, ml.Date)
 		}
 	})
 
 	t.Run("LetterCard from slice preserves length", func(t *testing.T) {
-		mls, err := service.ListLetters(ctx, *s, "all")
+		mls, err := service.ListLetters(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("service.ListLetters failed: %v", err)
 		}
@@ -174,14 +175,14 @@ func TestLetterAdapters(t *testing.T) {
 	})
 
 	t.Run("LetterCard produces correct URL", func(t *testing.T) {
-		ml, err := service.GetLetter(ctx, *s, "letters/test-letter.yaml", 4)
+		ml, err := service.GetLetter(ctx, s, "letters/test-letter.yaml")
 		if err != nil {
 			t.Fatalf("service.GetLetter failed: %v", err)
 		}
 
 		card := web.LetterCard(*ml)
 
-		expectedURL := "/letter?id=4"
+		expectedURL := "/letters/test-letter"
 		if card.Get != expectedURL {
 			t.Errorf("card URL: got %q, want %q", card.Get, expectedURL)
 		}
@@ -195,10 +196,10 @@ func TestLetterAdapters(t *testing.T) {
 // TestReadingListAdapters verifies reading list card conversion.
 func TestReadingListAdapters(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("BookCard preserves all fields", func(t *testing.T) {
-		mb, err := service.GetBook(ctx, *s, "reading-list/test-book.yaml", 1)
+		mb, err := service.GetBook(ctx, s, "reading-list/test-book.yaml")
 		if err != nil {
 			t.Fatalf("service.GetBook failed: %v", err)
 		}
@@ -215,7 +216,7 @@ func TestReadingListAdapters(t *testing.T) {
 	})
 
 	t.Run("BookCard from slice preserves length", func(t *testing.T) {
-		mbs, err := service.ListBooks(ctx, *s, "all")
+		mbs, err := service.ListBooks(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("service.ListBooks failed: %v", err)
 		}
@@ -231,14 +232,14 @@ func TestReadingListAdapters(t *testing.T) {
 	})
 
 	t.Run("BookCard produces correct URL and ImagePath", func(t *testing.T) {
-		mb, err := service.GetBook(ctx, *s, "reading-list/test-book.yaml", 7)
+		mb, err := service.GetBook(ctx, s, "reading-list/test-book.yaml")
 		if err != nil {
 			t.Fatalf("service.GetBook failed: %v", err)
 		}
 
 		card := web.BookCard(*mb)
 
-		expectedURL := "/book?id=7"
+		expectedURL := "/books/test-book"
 		if card.Get != expectedURL {
 			t.Errorf("card URL: got %q, want %q", card.Get, expectedURL)
 		}
@@ -0,0 +1,139 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+
+	"timterests/internal/auth"
+	apperrors "timterests/internal/errors"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// ClippingsResult carries a Kindle clippings import back to the template.
+type ClippingsResult struct {
+	Plan service.ClippingsPlan
+	// Preview is true when nothing was written.
+	Preview bool
+	Message string
+	Errors  []string
+}
+
+// ImportClippingsPageHandler renders the Kindle clippings import form.
+func ImportClippingsPageHandler(w http.ResponseWriter, r *http.Request, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	err := renderHTML(w, r, http.StatusOK, ImportClippingsPage(ClippingsResult{}))
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "ImportClippingsPageHandler", "render")
+	}
+}
+
+// ImportClippingsHandler takes a Kindle "My Clippings.txt" and either previews
+// the highlights it would add to reading-list books or adds them. As with the
+// book import, the preview is the default.
+func ImportClippingsHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		HandleError(w, r, apperrors.MethodNotAllowed(), "ImportClippingsHandler", "checkMethod")
+
+		return
+	}
+
+	err := r.ParseMultipartForm(maxUploadBytes)
+	if err != nil {
+		HandleError(w, r, apperrors.ParseFormFailed(err), "ImportClippingsHandler", "parseForm")
+
+		return
+	}
+
+	result := importClippings(r, s, r.FormValue("action") != "import")
+
+	component := ImportClippingsPage(result)
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
+
+		component = ImportClippingsForm(result)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "ImportClippingsHandler", "render")
+	}
+}
+
+// importClippings does the work and reports what happened, so the handler
+// stays about HTTP and this stays testable.
+func importClippings(r *http.Request, s storage.Backend, preview bool) ClippingsResult {
+	result := ClippingsResult{Preview: preview}
+
+	content, _, err := readUpload(r, "clippings-file", ".txt")
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+
+		return result
+	}
+
+	clippings, err := service.ParseClippings(bytes.NewReader(content))
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+
+		return result
+	}
+
+	result.Plan, err = service.PlanClippings(r.Context(), s, clippings)
+	if err != nil {
+		log.Printf("clippings: failed to match books: %v", err)
+
+		result.Errors = append(result.Errors, "Failed to match the clippings to the reading list. Please try again.")
+
+		return result
+	}
+
+	toAdd := 0
+
+	for _, match := range result.Plan.Books {
+		toAdd += len(match.Added)
+	}
+
+	if preview {
+		result.Message = fmt.Sprintf("%d new highlights would be added to %d books.", toAdd, len(result.Plan.Books))
+
+		return result
+	}
+
+	added, err := service.ImportClippings(r.Context(), s, result.Plan)
+	if err != nil {
+		log.Printf("clippings: %v", err)
+
+		result.Errors = append(result.Errors, fmt.Sprintf("Added %d of %d highlights before a write failed. Import again to add the rest.", added, toAdd))
+
+		return result
+	}
+
+	result.Message = fmt.Sprintf("Added %d new highlights to %d books.", added, len(result.Plan.Books))
+
+	return result
+}
+
+// droppedNotes counts the notes of plan that match no highlight.
+func droppedNotes(plan service.ClippingsPlan) int {
+	dropped := 0
+	for _, match := range plan.Books {
+		dropped += len(match.Dropped)
+	}
+
+	return dropped
+}
@@ -16,11 +16,12 @@ import (
 // known content directory.
 var errInvalidDocumentKey = errors.New("invalid document key")
 
-// DeleteDocumentHandler removes a document and returns the refreshed table.
+// DeleteDocumentHandler moves a document to the trash and returns the refreshed
+// table. Nothing is lost until the trash item is purged.
 //
 // POST only: a link or GET would let a crawler, prefetch or stray click destroy
 // content.
-func DeleteDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, a *auth.Auth) {
+func DeleteDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
 	if !a.IsAuthenticated(r) {
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 
@@ -47,9 +48,9 @@ func DeleteDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Sto
 		return
 	}
 
-	err = s.DeleteDocument(r.Context(), key)
+	_, err = storage.TrashDocument(r.Context(), s, key)
 	if err != nil {
-		log.Printf("delete: failed to delete %q: %v", key, err)
+		log.Printf("delete: failed to trash %q: %v", key, err)
 		HandleError(w, r, apperrors.StorageFailed(err), "DeleteDocumentHandler", "delete")
 
 		return
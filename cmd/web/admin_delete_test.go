@@ -16,7 +16,7 @@ import (
 
 // seedDocument writes a yaml/md pair into a throwaway storage dir so a delete
 // test never touches the real testdata fixtures.
-func seedDocument(t *testing.T, slug string) *storage.Storage {
+func seedDocument(t *testing.T, slug string) *storage.LocalBackend {
 	t.Helper()
 	t.Setenv("USE_S3", "false")
 
@@ -37,7 +37,7 @@ func seedDocument(t *testing.T, slug string) *storage.Storage {
 		}
 	}
 
-	return &storage.Storage{UseS3: false, BaseDir: base}
+	return storage.NewLocalBackend(base)
 }
 
 func deleteRequest(t *testing.T, key string) *http.Request {
@@ -73,7 +73,7 @@ func TestDeleteDocumentHandler(t *testing.T) {
 		req := deleteRequest(t, "articles/doomed.yaml")
 		addAuthCookie(req)
 
-		web.DeleteDocumentHandler(httptest.NewRecorder(), req, *s, a)
+		web.DeleteDocumentHandler(httptest.NewRecorder(), req, s, a)
 
 		for _, ext := range []string{".yaml", ".md"} {
 			path := filepath.Join(s.BaseDir, "articles", "doomed"+ext)
@@ -95,7 +95,7 @@ func TestDeleteDocumentHandler(t *testing.T) {
 		)
 		addAuthCookie(req)
 
-		web.DeleteDocumentHandler(httptest.NewRecorder(), req, *s, a)
+		web.DeleteDocumentHandler(httptest.NewRecorder(), req, s, a)
 
 		requireExists(t,
 			filepath.Join(s.BaseDir, "articles", "safe.yaml"),
@@ -107,7 +107,7 @@ func TestDeleteDocumentHandler(t *testing.T) {
 		s := seedDocument(t, "protected")
 
 		rec := httptest.NewRecorder()
-		web.DeleteDocumentHandler(rec, deleteRequest(t, "articles/protected.yaml"), *s, a)
+		web.DeleteDocumentHandler(rec, deleteRequest(t, "articles/protected.yaml"), s, a)
 
 		if rec.Code != http.StatusSeeOther {
 			t.Errorf("expected redirect, got %d", rec.Code)
@@ -135,7 +135,7 @@ func TestDeleteDocumentHandler(t *testing.T) {
 			req := deleteRequest(t, key)
 			addAuthCookie(req)
 
-			web.DeleteDocumentHandler(rec, req, *s, a)
+			web.DeleteDocumentHandler(rec, req, s, a)
 
 			if rec.Code == http.StatusOK {
 				t.Errorf("expected key %q to be rejected", key)
@@ -168,9 +168,9 @@ func TestDeleteDocumentToleratesMissingHalf(t *testing.T) {
 		t.Fatalf("failed to write yaml: %v", err)
 	}
 
-	s := &storage.Storage{UseS3: false, BaseDir: base}
+	s := storage.NewLocalBackend(base)
 
-	err = s.DeleteDocument(context.Background(), "articles/orphan.yaml")
+	err = storage.DeleteDocument(context.Background(), s, "articles/orphan.yaml")
 	if err != nil {
 		t.Fatalf("expected a missing body file to be tolerated, got %v", err)
 	}
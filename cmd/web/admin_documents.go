@@ -13,10 +13,10 @@ import (
 	"time"
 
 	"github.com/a-h/templ"
-	"github.com/aws/aws-sdk-go-v2/aws"
 
 	"timterests/internal/auth"
 	apperrors "timterests/internal/errors"
+	"timterests/internal/model"
 	"timterests/internal/storage"
 )
 
@@ -30,13 +30,25 @@ type DocumentInfo struct {
 	Source       string
 	Size         int64
 	LastModified time.Time
-	Index        int
+	// DocKey is the metadata key of the document the file belongs to, which
+	// is what the row's actions work on.
+	DocKey string
+	// State is the publication state, shown on the row holding the document's
+	// metadata: the .yaml of a pair or a single-file .md.
+	State string
 }
 
-// DocTypes returns the content directories the admin dashboard lists. A function
-// rather than a package variable so callers cannot mutate the shared slice.
+// DocTypes returns the content directories the admin dashboard lists, the
+// schema-declared types last. A function rather than a package variable so
+// callers cannot mutate the shared slice.
 func DocTypes() []string {
-	return []string{"articles", "projects", "reading-list", "letters"}
+	types := []string{"articles", "projects", "reading-list", "letters"}
+
+	for _, schema := range CustomTypes() {
+		types = append(types, schema.Name)
+	}
+
+	return types
 }
 
 // AdminDocumentsParams holds the data passed to the admin documents template.
@@ -55,7 +67,7 @@ type AdminDocumentsParams struct {
 }
 
 // AdminDocumentsPageHandler handles the admin documents dashboard at /admin/documents.
-func AdminDocumentsPageHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, a *auth.Auth) {
+func AdminDocumentsPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
 	if !a.IsAuthenticated(r) {
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 
@@ -211,11 +223,9 @@ func sortDocs(docs []DocumentInfo, sortBy, sortDir string) {
 }
 
 // ListAllDocuments collects DocumentInfo from all content type directories.
-func ListAllDocuments(ctx context.Context, s storage.Storage) ([]DocumentInfo, error) {
-	source := "Local"
-	if s.UseS3 {
-		source = "S3"
-	}
+func ListAllDocuments(ctx context.Context, s storage.Backend) ([]DocumentInfo, error) {
+	source := s.Name()
+	now := time.Now()
 
 	var docs []DocumentInfo
 
@@ -225,21 +235,36 @@ func ListAllDocuments(ctx context.Context, s storage.Storage) ([]DocumentInfo, e
 			return nil, fmt.Errorf("listing %s: %w", docType, err)
 		}
 
-		for i, obj := range objects {
-			key := aws.ToString(obj.Key)
+		present := make(map[string]bool, len(objects))
+		for _, obj := range objects {
+			present[obj.Key] = true
+		}
+
+		for _, obj := range objects {
+			key := obj.Key
 
 			if key == "" || strings.HasSuffix(key, "/") {
 				continue
 			}
 
+			docKey := storage.MetadataKey(key)
+
+			// The metadata lives in the .yaml of a pair, so only a .md with no
+			// .yaml beside it can be a single-file document.
+			var state string
+			if key == docKey || !present[docKey] {
+				state = documentStateForKey(ctx, s, docKey, now)
+			}
+
 			docs = append(docs, DocumentInfo{
 				Filename:     filepath.Base(key),
 				Key:          key,
 				DocType:      docType,
 				Source:       source,
-				Size:         aws.ToInt64(obj.Size),
-				LastModified: aws.ToTime(obj.LastModified),
-				Index:        i,
+				Size:         obj.Size,
+				LastModified: obj.LastModified,
+				DocKey:       docKey,
+				State:        state,
 			})
 		}
 	}
@@ -247,6 +272,20 @@ func ListAllDocuments(ctx context.Context, s storage.Storage) ([]DocumentInfo, e
 	return docs, nil
 }
 
+// documentStateForKey reads the publication state of a document's metadata. A
+// document whose metadata cannot be read has no state rather than failing the
+// whole listing.
+func documentStateForKey(ctx context.Context, s storage.Backend, key string, now time.Time) string {
+	var doc model.Document
+
+	err := storage.GetPreparedFile(ctx, s, key, &doc)
+	if err != nil {
+		return ""
+	}
+
+	return doc.State(now)
+}
+
 // buildDocumentsURL constructs a properly encoded URL for the admin documents
 // page, carrying the active filters through sorting and pagination. It takes the
 // params struct so adding a filter does not mean touching every call site.
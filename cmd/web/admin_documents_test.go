@@ -13,13 +13,13 @@ import (
 
 func TestAdminDocumentsPageHandler(t *testing.T) {
 	a, addAuthCookie := testAuthentication(t)
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("redirects to login when unauthenticated", func(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/admin/documents", nil)
 		rec := httptest.NewRecorder()
 
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusSeeOther {
 			t.Errorf("expected status %d, got %d", http.StatusSeeOther, rec.Code)
@@ -36,7 +36,7 @@ func TestAdminDocumentsPageHandler(t *testing.T) {
 
 		addAuthCookie(req)
 
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
@@ -71,7 +71,7 @@ func TestAdminDocumentsPageHandler(t *testing.T) {
 		addAuthCookie(req)
 		req.Header.Set("Hx-Request", "true")
 
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
@@ -105,7 +105,7 @@ func TestAdminDocumentsPageHandler(t *testing.T) {
 
 		addAuthCookie(req)
 
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -124,7 +124,7 @@ func TestAdminDocumentsPageHandler(t *testing.T) {
 		unfilteredRec := httptest.NewRecorder()
 
 		addAuthCookie(unfilteredReq)
-		web.AdminDocumentsPageHandler(unfilteredRec, unfilteredReq, *s, a)
+		web.AdminDocumentsPageHandler(unfilteredRec, unfilteredReq, s, a)
 
 		unfilteredDoc, err := goquery.NewDocumentFromReader(unfilteredRec.Body)
 		if err != nil {
@@ -146,7 +146,7 @@ func TestAdminDocumentsPageHandler(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		addAuthCookie(req)
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -182,7 +182,7 @@ func TestAdminDocumentsPageHandler(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		addAuthCookie(req)
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
@@ -192,7 +192,7 @@ func TestAdminDocumentsPageHandler(t *testing.T) {
 
 func TestAdminDocumentsSortOrder(t *testing.T) {
 	a, addAuthCookie := testAuthentication(t)
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("filename ascending is ordered", func(t *testing.T) {
 		req := httptest.NewRequestWithContext(
@@ -201,7 +201,7 @@ func TestAdminDocumentsSortOrder(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		addAuthCookie(req)
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected status 200, got %d", rec.Code)
@@ -239,7 +239,7 @@ func TestAdminDocumentsSortOrder(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		addAuthCookie(req)
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected status 200, got %d", rec.Code)
@@ -277,7 +277,7 @@ func TestAdminDocumentsSortOrder(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		addAuthCookie(req)
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
@@ -287,7 +287,7 @@ func TestAdminDocumentsSortOrder(t *testing.T) {
 
 func TestAdminDocumentsSortByModified(t *testing.T) {
 	a, addAuthCookie := testAuthentication(t)
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("modified ascending is ordered", func(t *testing.T) {
 		req := httptest.NewRequestWithContext(
@@ -297,7 +297,7 @@ func TestAdminDocumentsSortByModified(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		addAuthCookie(req)
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected status 200, got %d", rec.Code)
@@ -336,7 +336,7 @@ func TestAdminDocumentsSortByModified(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		addAuthCookie(req)
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected status 200, got %d", rec.Code)
@@ -370,7 +370,7 @@ func TestAdminDocumentsSortByModified(t *testing.T) {
 
 func TestAdminDocumentsFilters(t *testing.T) {
 	a, addAuthCookie := testAuthentication(t)
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	// rowsFor returns the Type column of every listed document.
 	rowsFor := func(t *testing.T, query string) []string {
@@ -382,7 +382,7 @@ func TestAdminDocumentsFilters(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		addAuthCookie(req)
-		web.AdminDocumentsPageHandler(rec, req, *s, a)
+		web.AdminDocumentsPageHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected status 200, got %d", rec.Code)
@@ -461,10 +461,10 @@ func TestAdminDocumentsFilters(t *testing.T) {
 }
 
 func TestListAllDocuments(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("returns documents from all content types", func(t *testing.T) {
-		docs, err := web.ListAllDocuments(context.Background(), *s)
+		docs, err := web.ListAllDocuments(context.Background(), s)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -0,0 +1,136 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+
+	"timterests/internal/auth"
+	apperrors "timterests/internal/errors"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// ImportResult carries a reading-history import back to the template.
+type ImportResult struct {
+	// Format is the export the file was recognised as.
+	Format string
+	// Books is the plan: what would be, or was, written and what was skipped.
+	Books []service.BookImport
+	// Preview is true when nothing was written.
+	Preview bool
+	Message string
+	Errors  []string
+}
+
+// ImportBooksPageHandler renders the reading-history import form.
+func ImportBooksPageHandler(w http.ResponseWriter, r *http.Request, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	err := renderHTML(w, r, http.StatusOK, ImportBooksPage(ImportResult{}))
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "ImportBooksPageHandler", "render")
+	}
+}
+
+// ImportBooksHandler takes a Goodreads or StoryGraph CSV export and either
+// previews what it would add to the reading list or adds it. The preview is the
+// default, so a book is only written when the import is asked for outright.
+func ImportBooksHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		HandleError(w, r, apperrors.MethodNotAllowed(), "ImportBooksHandler", "checkMethod")
+
+		return
+	}
+
+	err := r.ParseMultipartForm(maxUploadBytes)
+	if err != nil {
+		HandleError(w, r, apperrors.ParseFormFailed(err), "ImportBooksHandler", "parseForm")
+
+		return
+	}
+
+	result := importBooks(r, s, r.FormValue("action") != "import")
+
+	component := ImportBooksPage(result)
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
+
+		component = ImportBooksForm(result)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "ImportBooksHandler", "render")
+	}
+}
+
+// importBooks does the work and reports what happened, so the handler stays
+// about HTTP and this stays testable.
+func importBooks(r *http.Request, s storage.Backend, preview bool) ImportResult {
+	result := ImportResult{Preview: preview}
+
+	content, _, err := readUpload(r, "csv-file", ".csv")
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+
+		return result
+	}
+
+	books, format, err := service.ParseBookExport(bytes.NewReader(content))
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+
+		return result
+	}
+
+	result.Format = format
+
+	result.Books, err = service.PlanBookImport(r.Context(), s, books)
+	if err != nil {
+		log.Printf("import: failed to check the reading list: %v", err)
+
+		result.Errors = append(result.Errors, "Failed to check the reading list for duplicates. Please try again.")
+
+		return result
+	}
+
+	toImport := 0
+
+	for _, book := range result.Books {
+		if book.Skip == "" {
+			toImport++
+		}
+	}
+
+	if preview {
+		result.Message = fmt.Sprintf("%d of %d books from the %s export would be imported.", toImport, len(result.Books), format)
+
+		return result
+	}
+
+	imported, err := service.ImportBooks(r.Context(), s, result.Books)
+	if err != nil {
+		log.Printf("import: %v", err)
+
+		result.Errors = append(result.Errors, fmt.Sprintf("Imported %d of %d books before a write failed. Import again to add the rest.", imported, toImport))
+
+		return result
+	}
+
+	result.Message = fmt.Sprintf("Imported %d of %d books from the %s export as drafts.", imported, len(result.Books), format)
+
+	return result
+}
@@ -0,0 +1,133 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"timterests/cmd/web"
+	"timterests/internal/service"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const importCSV = `Book Id,Title,Author,ISBN,ISBN13,My Rating,Number of Pages,Year Published,Original Publication Year,Date Read,Bookshelves,Exclusive Shelf,My Review,Read Count
+1,Dune,Frank Herbert,"=""0441013597""","=""9780441013593""",5,688,2005,1965,2024/08/14,sci-fi,read,,1
+2,Test Book Again,Test Author,"=""""","=""9780134685991""",3,100,2024,2024,2024/01/01,,read,,1
+`
+
+// importRequest builds a multipart POST carrying a CSV export and the chosen
+// action.
+func importRequest(t *testing.T, action, csv string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	err := writer.WriteField("action", action)
+	if err != nil {
+		t.Fatalf("failed to write field: %v", err)
+	}
+
+	part, err := writer.CreateFormFile("csv-file", "goodreads_library_export.csv")
+	if err != nil {
+		t.Fatalf("failed to create part: %v", err)
+	}
+
+	_, err = part.Write([]byte(csv))
+	if err != nil {
+		t.Fatalf("failed to write part: %v", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("failed to close writer: %v", err)
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/admin/import", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
+
+func TestImportBooksHandler(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	t.Run("previews without writing", func(t *testing.T) {
+		s := testSetup(t)
+
+		req := importRequest(t, "preview", importCSV)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+		web.ImportBooksHandler(rec, req, s, a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		rows := doc.Find("table.import-preview tbody tr")
+		if rows.Length() != 2 {
+			t.Fatalf("expected 2 rows in the preview, got %d", rows.Length())
+		}
+
+		if !strings.Contains(rows.Eq(0).Find(".import-outcome").Text(), "Will import as dune") {
+			t.Errorf("expected Dune to be imported, got %q", rows.Eq(0).Find(".import-outcome").Text())
+		}
+
+		if !rows.Eq(1).HasClass("import-skipped") {
+			t.Error("expected the book already on the list to be skipped")
+		}
+
+		_, err = service.GetBookBySlug(context.Background(), s, "dune")
+		if err == nil {
+			t.Error("expected a preview to write nothing")
+		}
+	})
+
+	t.Run("imports the books that are not duplicates", func(t *testing.T) {
+		s := testSetup(t)
+
+		req := importRequest(t, "import", importCSV)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+		web.ImportBooksHandler(rec, req, s, a)
+
+		if !strings.Contains(rec.Body.String(), "Imported 1 of 2 books") {
+			t.Errorf("expected one book to be reported imported, got %s", rec.Body.String())
+		}
+
+		_, err := service.GetBookBySlug(context.Background(), s, "dune")
+		if err != nil {
+			t.Errorf("expected dune to be written: %v", err)
+		}
+	})
+
+	t.Run("reports a file that is not an export", func(t *testing.T) {
+		req := importRequest(t, "preview", "name,age\nTim,40\n")
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+		web.ImportBooksHandler(rec, req, testSetup(t), a)
+
+		if !strings.Contains(rec.Body.String(), "neither a Goodreads nor a StoryGraph export") {
+			t.Error("expected the unrecognised file to be reported")
+		}
+	})
+
+	t.Run("redirects to login when unauthenticated", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		web.ImportBooksHandler(rec, importRequest(t, "import", importCSV), testSetup(t), a)
+
+		if rec.Code != http.StatusSeeOther {
+			t.Errorf("expected a redirect, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"timterests/internal/auth"
+	apperrors "timterests/internal/errors"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// RevisionsParams holds the data passed to the revision list template.
+type RevisionsParams struct {
+	Key       string
+	Revisions []storage.Revision
+}
+
+// RevisionDiffParams holds a side-by-side comparison of one revision against
+// the current document.
+type RevisionDiffParams struct {
+	Key      string
+	Revision string
+	Meta     []service.DiffRow
+	Body     []service.DiffRow
+}
+
+// RevisionsPageHandler lists the stored revisions of one document at
+// /admin/revisions?key=articles/my-post.yaml.
+func RevisionsPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	if !validDocumentKey(key) {
+		HandleError(w, r, apperrors.BadRequest(errInvalidDocumentKey), "RevisionsPageHandler", "validateKey")
+
+		return
+	}
+
+	revisions, err := storage.ListRevisions(r.Context(), s, key)
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "RevisionsPageHandler", "listRevisions")
+
+		return
+	}
+
+	err = renderHTML(w, r, http.StatusOK, RevisionsPage(RevisionsParams{Key: key, Revisions: revisions}))
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "RevisionsPageHandler", "render")
+	}
+}
+
+// RevisionDiffHandler shows a revision's metadata and body next to the current
+// ones at /admin/revisions/diff?key=...&rev=....
+func RevisionDiffHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	if !validDocumentKey(key) {
+		HandleError(w, r, apperrors.BadRequest(errInvalidDocumentKey), "RevisionDiffHandler", "validateKey")
+
+		return
+	}
+
+	id := r.URL.Query().Get("rev")
+
+	oldMeta, oldBody, err := storage.GetRevision(r.Context(), s, key, id)
+	if err != nil {
+		HandleError(w, r, revisionError(err), "RevisionDiffHandler", "getRevision")
+
+		return
+	}
+
+	// The document may since have been deleted, in which case everything in the
+	// revision shows as removed.
+	curMeta, curBody, err := storage.ReadDocument(r.Context(), s, key)
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "RevisionDiffHandler", "readDocument")
+
+		return
+	}
+
+	params := RevisionDiffParams{
+		Key:      key,
+		Revision: id,
+		Meta:     service.DiffLines(string(oldMeta), string(curMeta)),
+		Body:     service.DiffLines(string(oldBody), string(curBody)),
+	}
+
+	err = renderHTML(w, r, http.StatusOK, RevisionDiffPage(params))
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "RevisionDiffHandler", "render")
+	}
+}
+
+// RestoreRevisionHandler puts a document back to a stored revision and returns
+// to its revision list, where the replaced version now appears at the top.
+//
+// POST only, for the same reason as deletes.
+func RestoreRevisionHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		HandleError(w, r, apperrors.MethodNotAllowed(), "RestoreRevisionHandler", "checkMethod")
+
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		HandleError(w, r, apperrors.ParseFormFailed(err), "RestoreRevisionHandler", "parseForm")
+
+		return
+	}
+
+	key := r.FormValue("key")
+	if !validDocumentKey(key) {
+		HandleError(w, r, apperrors.BadRequest(errInvalidDocumentKey), "RestoreRevisionHandler", "validateKey")
+
+		return
+	}
+
+	err = storage.RestoreRevision(r.Context(), s, key, r.FormValue("rev"))
+	if err != nil {
+		HandleError(w, r, revisionError(err), "RestoreRevisionHandler", "restore")
+
+		return
+	}
+
+	http.Redirect(w, r, revisionsURL(key), http.StatusSeeOther)
+}
+
+// revisionError maps an unknown revision to a 404 and anything else to a
+// storage failure.
+func revisionError(err error) *apperrors.AppError {
+	if errors.Is(err, storage.ErrRevisionNotFound) {
+		return apperrors.NotFound(err)
+	}
+
+	return apperrors.StorageFailed(err)
+}
+
+func revisionsURL(key string) string {
+	return "/admin/revisions?key=" + url.QueryEscape(key)
+}
+
+func revisionDiffURL(key, id string) string {
+	return "/admin/revisions/diff?key=" + url.QueryEscape(key) + "&rev=" + url.QueryEscape(id)
+}
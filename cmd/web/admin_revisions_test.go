@@ -0,0 +1,164 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"timterests/cmd/web"
+	"timterests/internal/storage"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// seedRevisions saves a document twice so it has one earlier revision.
+func seedRevisions(t *testing.T) (storage.Backend, string) {
+	t.Helper()
+
+	s := storage.NewMemoryBackend()
+	key := "articles/post.yaml"
+
+	for _, title := range []string{"First Draft", "Second Draft"} {
+		err := storage.WriteDocument(context.Background(), s, key, []byte("title: "+title+"\n"), []byte(title))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+	}
+
+	revisions, err := storage.ListRevisions(context.Background(), s, key)
+	if err != nil || len(revisions) != 1 {
+		t.Fatalf("expected one revision, got %d (%v)", len(revisions), err)
+	}
+
+	return s, revisions[0].ID
+}
+
+func TestRevisionHandlers(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	t.Run("redirects to login when unauthenticated", func(t *testing.T) {
+		s, _ := seedRevisions(t)
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/admin/revisions?key=articles/post.yaml", nil)
+		rec := httptest.NewRecorder()
+
+		web.RevisionsPageHandler(rec, req, s, a)
+
+		if rec.Code != http.StatusSeeOther {
+			t.Errorf("expected status 303, got %d", rec.Code)
+		}
+	})
+
+	t.Run("lists revisions with compare links", func(t *testing.T) {
+		s, id := seedRevisions(t)
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/admin/revisions?key=articles/post.yaml", nil)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.RevisionsPageHandler(rec, req, s, a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+
+		href, _ := doc.Find(`a:contains("Compare")`).Attr("href")
+		if !strings.Contains(href, "rev="+url.QueryEscape(id)) {
+			t.Errorf("expected a compare link for %s, got %q", id, href)
+		}
+	})
+
+	t.Run("diff shows the old and new lines side by side", func(t *testing.T) {
+		s, id := seedRevisions(t)
+
+		req := httptest.NewRequestWithContext(
+			context.Background(), http.MethodGet,
+			"/admin/revisions/diff?key=articles/post.yaml&rev="+url.QueryEscape(id), nil,
+		)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.RevisionDiffHandler(rec, req, s, a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+
+		changed := doc.Find("tr.diff-changed").First()
+		if changed.Find(".diff-old").Text() != "title: First Draft" || changed.Find(".diff-new").Text() != "title: Second Draft" {
+			t.Errorf("expected the title change side by side, got %q", changed.Text())
+		}
+	})
+
+	t.Run("diff of an unknown revision is not found", func(t *testing.T) {
+		s, _ := seedRevisions(t)
+
+		req := httptest.NewRequestWithContext(
+			context.Background(), http.MethodGet,
+			"/admin/revisions/diff?key=articles/post.yaml&rev=20200101T000000.000000000Z", nil,
+		)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.RevisionDiffHandler(rec, req, s, a)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("restore puts the revision back", func(t *testing.T) {
+		s, id := seedRevisions(t)
+
+		form := url.Values{}
+		form.Set("key", "articles/post.yaml")
+		form.Set("rev", id)
+
+		req := httptest.NewRequestWithContext(
+			context.Background(), http.MethodPost, "/admin/revisions/restore",
+			strings.NewReader(form.Encode()),
+		)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.RestoreRevisionHandler(rec, req, s, a)
+
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("expected status 303, got %d", rec.Code)
+		}
+
+		_, body, err := storage.ReadDocument(context.Background(), s, "articles/post.yaml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(body) != "First Draft" {
+			t.Errorf("expected the restored body, got %q", body)
+		}
+	})
+
+	t.Run("restore rejects GET", func(t *testing.T) {
+		s, _ := seedRevisions(t)
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/admin/revisions/restore", nil)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.RestoreRevisionHandler(rec, req, s, a)
+
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected status 405, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"timterests/internal/auth"
+	apperrors "timterests/internal/errors"
+	"timterests/internal/model"
+	"timterests/internal/storage"
+)
+
+// SetDocumentStatusHandler publishes or unpublishes a document and returns the
+// refreshed table. Only the status field is rewritten; a publishAt in the future
+// still holds a published document back until then.
+func SetDocumentStatusHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		HandleError(w, r, apperrors.MethodNotAllowed(), "SetDocumentStatusHandler", "checkMethod")
+
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		HandleError(w, r, apperrors.ParseFormFailed(err), "SetDocumentStatusHandler", "parseForm")
+
+		return
+	}
+
+	key := r.FormValue("key")
+	if !validDocumentKey(key) {
+		HandleError(w, r, apperrors.BadRequest(errInvalidDocumentKey), "SetDocumentStatusHandler", "validateKey")
+
+		return
+	}
+
+	status := r.FormValue("status")
+	if status != model.StatusDraft && status != model.StatusPublished {
+		err = fmt.Errorf("status must be %q or %q", model.StatusDraft, model.StatusPublished)
+		HandleError(w, r, apperrors.BadRequest(err), "SetDocumentStatusHandler", "validateStatus")
+
+		return
+	}
+
+	err = storage.SetDocumentFields(r.Context(), s, key, map[string]any{"status": status})
+	if err != nil {
+		log.Printf("status: failed to set %q to %s: %v", key, status, err)
+		HandleError(w, r, apperrors.StorageFailed(err), "SetDocumentStatusHandler", "setStatus")
+
+		return
+	}
+
+	AdminDocumentsPageHandler(w, r, s, a)
+}
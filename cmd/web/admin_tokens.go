@@ -0,0 +1,144 @@
+package web
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"timterests/internal/auth"
+	apperrors "timterests/internal/errors"
+	"timterests/internal/storage"
+
+	"github.com/a-h/templ"
+)
+
+// TokensParams holds the data passed to the access tokens template.
+type TokensParams struct {
+	Tokens []auth.Token
+	// Secret is the secret of a token just created. It is shown this once and
+	// never stored.
+	Secret  string
+	Message string
+	Error   string
+}
+
+// TokensPageHandler lists personal access tokens at /admin/tokens.
+func TokensPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	renderTokens(w, r, s, TokensParams{})
+}
+
+// CreateTokenHandler mints a token from the name and scopes in the form and
+// returns the refreshed token table with its secret.
+func CreateTokenHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !tokensAction(w, r, a, "CreateTokenHandler") {
+		return
+	}
+
+	secret, token, err := auth.CreateToken(r.Context(), s, r.FormValue("name"), r.Form["scopes"], time.Now())
+	if err != nil {
+		// A bad name or scope is shown in the form rather than as an error page.
+		log.Printf("tokens: failed to create %q: %v", r.FormValue("name"), err)
+		renderTokens(w, r, s, TokensParams{Error: err.Error()})
+
+		return
+	}
+
+	renderTokens(w, r, s, TokensParams{
+		Secret:  secret,
+		Message: "Created " + token.Name + ". Copy the token now; it will not be shown again.",
+	})
+}
+
+// RevokeTokenHandler deletes a token, so its secret stops working, and returns
+// the refreshed token table.
+func RevokeTokenHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !tokensAction(w, r, a, "RevokeTokenHandler") {
+		return
+	}
+
+	err := auth.RevokeToken(r.Context(), s, r.FormValue("id"))
+	if err != nil {
+		log.Printf("tokens: failed to revoke %q: %v", r.FormValue("id"), err)
+		HandleError(w, r, tokenError(err), "RevokeTokenHandler", "revoke")
+
+		return
+	}
+
+	renderTokens(w, r, s, TokensParams{Message: "Token revoked."})
+}
+
+// tokensAction does the checks shared by the token POST endpoints.
+func tokensAction(w http.ResponseWriter, r *http.Request, a *auth.Auth, handler string) bool {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return false
+	}
+
+	if r.Method != http.MethodPost {
+		HandleError(w, r, apperrors.MethodNotAllowed(), handler, "checkMethod")
+
+		return false
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		HandleError(w, r, apperrors.ParseFormFailed(err), handler, "parseForm")
+
+		return false
+	}
+
+	return true
+}
+
+func renderTokens(w http.ResponseWriter, r *http.Request, s storage.Backend, params TokensParams) {
+	tokens, err := auth.ListTokens(r.Context(), s)
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "TokensPageHandler", "listTokens")
+
+		return
+	}
+
+	params.Tokens = tokens
+
+	var component templ.Component
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
+
+		component = TokensTable(params)
+	} else {
+		component = TokensPage(params)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "TokensPageHandler", "render")
+	}
+}
+
+// tokenError maps an unknown token to a 404 and anything else to a storage
+// failure.
+func tokenError(err error) *apperrors.AppError {
+	if errors.Is(err, auth.ErrTokenNotFound) {
+		return apperrors.NotFound(err)
+	}
+
+	return apperrors.StorageFailed(err)
+}
+
+// lastUsedText describes when a token was last used.
+func lastUsedText(t auth.Token) string {
+	if t.LastUsed.IsZero() {
+		return "Never"
+	}
+
+	return displayTime(t.LastUsed, "2006-01-02 15:04")
+}
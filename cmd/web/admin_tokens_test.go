@@ -0,0 +1,137 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+	"timterests/internal/storage"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func tokensRequest(t *testing.T, path string, form url.Values) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(
+		context.Background(), http.MethodPost, path,
+		strings.NewReader(form.Encode()),
+	)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Hx-Request", "true")
+
+	return req
+}
+
+func TestTokenHandlers(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	t.Run("the page requires authentication", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/admin/tokens", nil)
+		rec := httptest.NewRecorder()
+
+		web.TokensPageHandler(rec, req, storage.NewMemoryBackend(), a)
+
+		if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/login" {
+			t.Errorf("expected a redirect to /login, got %d %q", rec.Code, rec.Header().Get("Location"))
+		}
+	})
+
+	t.Run("create shows the secret once and stores the token", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		req := tokensRequest(t, "/admin/tokens", url.Values{"name": {"Deploy"}, "scopes": {"read", "write"}})
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.CreateTokenHandler(rec, req, s, a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+
+		secret := doc.Find("#token-secret").Text()
+
+		token, err := auth.VerifyToken(context.Background(), s, secret, time.Now())
+		if err != nil {
+			t.Fatalf("expected the shown secret to work, got %v", err)
+		}
+
+		if token.Name != "Deploy" || !token.HasScope(auth.ScopeWrite) || token.HasScope(auth.ScopeDelete) {
+			t.Errorf("unexpected token: %+v", token)
+		}
+
+		req = httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/admin/tokens", nil)
+		addAuthCookie(req)
+
+		rec = httptest.NewRecorder()
+
+		web.TokensPageHandler(rec, req, s, a)
+
+		if strings.Contains(rec.Body.String(), secret) {
+			t.Error("expected the secret not to be shown again")
+		}
+
+		if !strings.Contains(rec.Body.String(), token.Hint) {
+			t.Error("expected the token to be listed by its hint")
+		}
+	})
+
+	t.Run("a token without scopes is refused in the form", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		req := tokensRequest(t, "/admin/tokens", url.Values{"name": {"Nothing"}})
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.CreateTokenHandler(rec, req, s, a)
+
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "at least one scope") {
+			t.Errorf("expected the error in the form, got %d", rec.Code)
+		}
+
+		tokens, _ := auth.ListTokens(context.Background(), s)
+		if len(tokens) != 0 {
+			t.Errorf("expected no tokens, got %d", len(tokens))
+		}
+	})
+
+	t.Run("revoke removes the token", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		_, token, err := auth.CreateToken(context.Background(), s, "Old", []string{auth.ScopeRead}, time.Now())
+		if err != nil {
+			t.Fatalf("failed to create token: %v", err)
+		}
+
+		req := tokensRequest(t, "/admin/tokens/revoke", url.Values{"id": {token.ID}})
+		addAuthCookie(req)
+
+		web.RevokeTokenHandler(httptest.NewRecorder(), req, s, a)
+
+		tokens, _ := auth.ListTokens(context.Background(), s)
+		if len(tokens) != 0 {
+			t.Errorf("expected the token to be revoked, got %+v", tokens)
+		}
+
+		req = tokensRequest(t, "/admin/tokens/revoke", url.Values{"id": {token.ID}})
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.RevokeTokenHandler(rec, req, s, a)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected an unknown token to 404, got %d", rec.Code)
+		}
+	})
+}
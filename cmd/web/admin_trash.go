@@ -0,0 +1,144 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"timterests/internal/auth"
+	apperrors "timterests/internal/errors"
+	"timterests/internal/storage"
+
+	"github.com/a-h/templ"
+)
+
+// TrashParams holds the data passed to the trash template.
+type TrashParams struct {
+	Items   []storage.TrashItem
+	Message string
+	Error   string
+}
+
+// TrashPageHandler lists deleted documents at /admin/trash.
+func TrashPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	renderTrash(w, r, s, TrashParams{})
+}
+
+// RestoreTrashHandler puts a trashed document back and returns the refreshed
+// trash table. A document created under the same name since is never
+// overwritten; the item stays in the trash and the reason is shown instead.
+func RestoreTrashHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	id, ok := trashAction(w, r, a, "RestoreTrashHandler")
+	if !ok {
+		return
+	}
+
+	item, err := storage.RestoreTrash(r.Context(), s, id)
+
+	switch {
+	case errors.Is(err, storage.ErrRestoreConflict):
+		renderTrash(w, r, s, TrashParams{Error: err.Error()})
+	case err != nil:
+		log.Printf("trash: failed to restore %q: %v", id, err)
+		HandleError(w, r, trashError(err), "RestoreTrashHandler", "restore")
+	default:
+		renderTrash(w, r, s, TrashParams{Message: "Restored " + item.Key() + "."})
+	}
+}
+
+// PurgeTrashHandler removes a trashed document for good and returns the
+// refreshed trash table.
+func PurgeTrashHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	id, ok := trashAction(w, r, a, "PurgeTrashHandler")
+	if !ok {
+		return
+	}
+
+	err := storage.PurgeTrash(r.Context(), s, id)
+	if err != nil {
+		log.Printf("trash: failed to purge %q: %v", id, err)
+		HandleError(w, r, trashError(err), "PurgeTrashHandler", "purge")
+
+		return
+	}
+
+	renderTrash(w, r, s, TrashParams{})
+}
+
+// trashAction does the checks shared by the trash's POST endpoints and returns
+// the item ID from the form.
+func trashAction(w http.ResponseWriter, r *http.Request, a *auth.Auth, handler string) (string, bool) {
+	if !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return "", false
+	}
+
+	if r.Method != http.MethodPost {
+		HandleError(w, r, apperrors.MethodNotAllowed(), handler, "checkMethod")
+
+		return "", false
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		HandleError(w, r, apperrors.ParseFormFailed(err), handler, "parseForm")
+
+		return "", false
+	}
+
+	return r.FormValue("id"), true
+}
+
+func renderTrash(w http.ResponseWriter, r *http.Request, s storage.Backend, params TrashParams) {
+	items, err := storage.ListTrash(r.Context(), s)
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "TrashPageHandler", "listTrash")
+
+		return
+	}
+
+	params.Items = items
+
+	var component templ.Component
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
+
+		component = TrashTable(params)
+	} else {
+		component = TrashPage(params)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "TrashPageHandler", "render")
+	}
+}
+
+// trashError maps an unknown trash item to a 404 and anything else to a
+// storage failure.
+func trashError(err error) *apperrors.AppError {
+	if errors.Is(err, storage.ErrTrashItemNotFound) {
+		return apperrors.NotFound(err)
+	}
+
+	return apperrors.StorageFailed(err)
+}
+
+// trashRetentionNote describes when trash is purged automatically.
+func trashRetentionNote() string {
+	retention := storage.TrashRetention()
+	if retention <= 0 {
+		return "Automatic purging is off, so items stay until they are purged here."
+	}
+
+	return fmt.Sprintf("Items are purged automatically %d days after they were deleted.", int(retention.Hours()/24))
+}
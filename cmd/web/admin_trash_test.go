@@ -0,0 +1,152 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+	"timterests/internal/storage"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func trashRequest(t *testing.T, path, id string) *http.Request {
+	t.Helper()
+
+	form := url.Values{}
+	form.Set("id", id)
+
+	req := httptest.NewRequestWithContext(
+		context.Background(), http.MethodPost, path,
+		strings.NewReader(form.Encode()),
+	)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Hx-Request", "true")
+
+	return req
+}
+
+// trashedDocument deletes a seeded document through the dashboard and returns
+// the backend and the resulting trash item.
+func trashedDocument(t *testing.T, a *auth.Auth, addAuthCookie func(*http.Request), slug string) (*storage.LocalBackend, storage.TrashItem) {
+	t.Helper()
+
+	s := seedDocument(t, slug)
+
+	req := deleteRequest(t, "articles/"+slug+".yaml")
+	addAuthCookie(req)
+
+	web.DeleteDocumentHandler(httptest.NewRecorder(), req, s, a)
+
+	items, err := storage.ListTrash(context.Background(), s)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expected one trash item, got %d (%v)", len(items), err)
+	}
+
+	return s, items[0]
+}
+
+func TestTrashHandlers(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	t.Run("deleted documents are listed in the trash", func(t *testing.T) {
+		s, item := trashedDocument(t, a, addAuthCookie, "binned")
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/admin/trash", nil)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.TrashPageHandler(rec, req, s, a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+
+		if doc.Find(`input[name="id"][value="`+item.ID+`"]`).Length() == 0 {
+			t.Errorf("expected %s to be listed in the trash", item.ID)
+		}
+	})
+
+	t.Run("restore puts the files back", func(t *testing.T) {
+		s, item := trashedDocument(t, a, addAuthCookie, "rescued")
+
+		req := trashRequest(t, "/admin/trash/restore", item.ID)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.RestoreTrashHandler(rec, req, s, a)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+
+		for _, ext := range []string{".yaml", ".md"} {
+			requireExists(t,
+				filepath.Join(s.BaseDir, "articles", "rescued"+ext),
+				"expected rescued"+ext+" to be restored",
+			)
+		}
+	})
+
+	t.Run("purge removes the item for good", func(t *testing.T) {
+		s, item := trashedDocument(t, a, addAuthCookie, "purged")
+
+		req := trashRequest(t, "/admin/trash/purge", item.ID)
+		addAuthCookie(req)
+
+		web.PurgeTrashHandler(httptest.NewRecorder(), req, s, a)
+
+		items, err := storage.ListTrash(context.Background(), s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(items) != 0 {
+			t.Errorf("expected an empty trash, got %d items", len(items))
+		}
+	})
+
+	t.Run("restore and purge reject GET", func(t *testing.T) {
+		s, _ := trashedDocument(t, a, addAuthCookie, "kept")
+
+		for _, handler := range []func(http.ResponseWriter, *http.Request, storage.Backend, *auth.Auth){
+			web.RestoreTrashHandler,
+			web.PurgeTrashHandler,
+		} {
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/admin/trash", nil)
+			addAuthCookie(req)
+
+			rec := httptest.NewRecorder()
+
+			handler(rec, req, s, a)
+
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Errorf("expected status 405, got %d", rec.Code)
+			}
+		}
+	})
+
+	t.Run("an unknown item is not found", func(t *testing.T) {
+		s, _ := trashedDocument(t, a, addAuthCookie, "other")
+
+		req := trashRequest(t, "/admin/trash/purge", "../articles/other")
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.PurgeTrashHandler(rec, req, s, a)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+}
@@ -43,14 +43,18 @@ type UploadResult struct {
 	DocType  string
 	Message  string
 	Errors   []string
+	// Stripped lists what the sanitizer will remove from the uploaded body
+	// when it is shown.
+	Stripped []string
 }
 
-// UploadDocumentHandler accepts a .yaml/.md pair, validates the metadata against
-// the chosen type, and writes both files.
+// UploadDocumentHandler accepts a .yaml/.md pair, or a single .md carrying its
+// metadata as front matter, validates the metadata against the chosen type, and
+// writes the document in the layout it arrived in.
 //
 // Validation runs before anything is written, so a document that would fail
 // never lands half-uploaded.
-func UploadDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, a *auth.Auth) {
+func UploadDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
 	if !a.IsAuthenticated(r) {
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 
@@ -87,9 +91,13 @@ func UploadDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Sto
 
 // uploadDocument does the work and reports what happened, so the handler stays
 // about HTTP and this stays testable.
-func uploadDocument(r *http.Request, s storage.Storage, docType string) UploadResult {
+func uploadDocument(r *http.Request, s storage.Backend, docType string) UploadResult {
 	result := UploadResult{DocTypes: DocTypes(), DocType: docType}
 
+	if !hasUpload(r, "yaml-file") {
+		return uploadSingleFile(r, s, docType, result)
+	}
+
 	yamlBytes, yamlName, err := readUpload(r, "yaml-file", ".yaml")
 	if err != nil {
 		result.Errors = append(result.Errors, err.Error())
@@ -130,10 +138,69 @@ func uploadDocument(r *http.Request, s storage.Storage, docType string) UploadRe
 	}
 
 	result.Message = fmt.Sprintf("Uploaded %s to %s.", slug, docType)
+	result.Stripped = strippedFromBody(mdBytes)
+
+	return result
+}
+
+// uploadSingleFile handles a lone .md whose metadata is its front matter.
+func uploadSingleFile(r *http.Request, s storage.Backend, docType string, result UploadResult) UploadResult {
+	mdBytes, mdName, err := readUpload(r, "md-file", ".md")
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+
+		return result
+	}
+
+	meta, body, ok := storage.SplitFrontMatter(mdBytes)
+	if !ok {
+		result.Errors = append(result.Errors, fmt.Sprintf(
+			"%q has no YAML front matter between --- lines, so a .yaml file is required.", mdName,
+		))
+
+		return result
+	}
+
+	missing := validateDocumentYAML(meta, docType)
+	if missing != nil {
+		result.Errors = append(result.Errors, missing.Error())
+
+		return result
+	}
+
+	slug := strings.TrimSuffix(mdName, ".md")
+
+	err = storage.WriteSingleFile(r.Context(), s, docType+"/"+slug+".yaml", mdBytes)
+	if err != nil {
+		log.Printf("upload: failed to write %s/%s: %v", docType, slug, err)
+
+		result.Errors = append(result.Errors, "Failed to save the document. Please try again.")
+
+		return result
+	}
+
+	result.Message = fmt.Sprintf("Uploaded %s to %s.", slug, docType)
+	result.Stripped = strippedFromBody(body)
 
 	return result
 }
 
+// strippedFromBody reports what will not survive sanitizing in an uploaded
+// body. The upload has already succeeded, so a failure here is only logged.
+func strippedFromBody(body []byte) []string {
+	stripped, err := storage.StrippedFromMarkdown(body)
+	if err != nil {
+		log.Printf("upload: failed to check the body: %v", err)
+	}
+
+	return stripped
+}
+
+// hasUpload reports whether the form carries a file in field.
+func hasUpload(r *http.Request, field string) bool {
+	return r.MultipartForm != nil && len(r.MultipartForm.File[field]) > 0
+}
+
 // readUpload pulls one file from the form and checks its extension.
 func readUpload(r *http.Request, field, wantExt string) ([]byte, string, error) {
 	file, header, err := r.FormFile(field)
@@ -197,7 +264,28 @@ func validateDocumentYAML(content []byte, docType string) error {
 
 	err = model.ValidateRequired(doc)
 	if err != nil {
-		return fmt.Errorf("%w", err)
+		return fmt.Errorf("the metadata is incomplete: %w", err)
+	}
+
+	err = model.ValidateDates(doc)
+	if err != nil {
+		return fmt.Errorf("the metadata has a bad date: %w", err)
+	}
+
+	typed, ok := doc.(interface{ ValidateFields() error })
+	if ok {
+		err = typed.ValidateFields()
+		if err != nil {
+			return fmt.Errorf("the metadata has a bad field: %w", err)
+		}
+	}
+
+	publishable, ok := doc.(interface{ ValidatePublication() error })
+	if ok {
+		err = publishable.ValidatePublication()
+		if err != nil {
+			return fmt.Errorf("the publication settings are invalid: %w", err)
+		}
 	}
 
 	return nil
@@ -215,30 +303,22 @@ func emptyDocument(docType string) any {
 	case "letters":
 		return &model.Letter{}
 	default:
-		return nil
+		schema, ok := customType(docType)
+		if !ok {
+			return nil
+		}
+
+		return &model.CustomDocument{Schema: schema}
 	}
 }
 
 func writeUploadedPair(
 	r *http.Request,
-	s storage.Storage,
+	s storage.Backend,
 	docType, slug string,
 	yamlBytes, mdBytes []byte,
 ) error {
-	for _, file := range []struct {
-		key     string
-		content []byte
-	}{
-		{docType + "/" + slug + ".yaml", yamlBytes},
-		{docType + "/" + slug + ".md", mdBytes},
-	} {
-		err := s.WriteFile(r.Context(), file.key, file.content)
-		if err != nil {
-			return fmt.Errorf("writing %s: %w", file.key, err)
-		}
-	}
-
-	return nil
+	return storage.WriteDocument(r.Context(), s, docType+"/"+slug+".yaml", yamlBytes, mdBytes)
 }
 
 func renderUpload(w http.ResponseWriter, r *http.Request, result UploadResult) {
@@ -55,11 +55,11 @@ func uploadRequest(t *testing.T, docType string, parts map[string]string) *http.
 	return req
 }
 
-func uploadStorage(t *testing.T) *storage.Storage {
+func uploadStorage(t *testing.T) *storage.LocalBackend {
 	t.Helper()
 	t.Setenv("USE_S3", "false")
 
-	return &storage.Storage{UseS3: false, BaseDir: t.TempDir()}
+	return storage.NewLocalBackend(t.TempDir())
 }
 
 func TestUploadDocumentHandler(t *testing.T) {
@@ -78,7 +78,7 @@ func TestUploadDocumentHandler(t *testing.T) {
 		addAuthCookie(req)
 
 		rec := httptest.NewRecorder()
-		web.UploadDocumentHandler(rec, req, *s, a)
+		web.UploadDocumentHandler(rec, req, s, a)
 
 		for _, name := range []string{"post.yaml", "post.md"} {
 			path := filepath.Join(s.BaseDir, "articles", name)
@@ -102,7 +102,7 @@ func TestUploadDocumentHandler(t *testing.T) {
 		addAuthCookie(req)
 
 		rec := httptest.NewRecorder()
-		web.UploadDocumentHandler(rec, req, *s, a)
+		web.UploadDocumentHandler(rec, req, s, a)
 
 		if !strings.Contains(rec.Body.String(), "date") {
 			t.Error("expected the response to name the missing field")
@@ -114,6 +114,28 @@ func TestUploadDocumentHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("rejects a date that does not parse", func(t *testing.T) {
+		s := uploadStorage(t)
+
+		req := uploadRequest(t, "articles", map[string]string{
+			"yaml-file": "post.yaml|title: A Post\ndate: 13/13/2026\n",
+			"md-file":   validMD,
+		})
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+		web.UploadDocumentHandler(rec, req, s, a)
+
+		if !strings.Contains(rec.Body.String(), "13/13/2026") {
+			t.Error("expected the response to name the bad date")
+		}
+
+		_, err := os.Stat(filepath.Join(s.BaseDir, "articles", "post.yaml"))
+		if !os.IsNotExist(err) {
+			t.Error("nothing should be written when validation fails")
+		}
+	})
+
 	t.Run("rejects mismatched filenames", func(t *testing.T) {
 		s := uploadStorage(t)
 
@@ -124,7 +146,7 @@ func TestUploadDocumentHandler(t *testing.T) {
 		addAuthCookie(req)
 
 		rec := httptest.NewRecorder()
-		web.UploadDocumentHandler(rec, req, *s, a)
+		web.UploadDocumentHandler(rec, req, s, a)
 
 		if !strings.Contains(rec.Body.String(), "share a name") {
 			t.Error("expected a filename mismatch to be reported")
@@ -141,7 +163,7 @@ func TestUploadDocumentHandler(t *testing.T) {
 		addAuthCookie(req)
 
 		rec := httptest.NewRecorder()
-		web.UploadDocumentHandler(rec, req, *s, a)
+		web.UploadDocumentHandler(rec, req, s, a)
 
 		if !strings.Contains(rec.Body.String(), ".yaml") {
 			t.Error("expected the extension to be rejected")
@@ -158,7 +180,7 @@ func TestUploadDocumentHandler(t *testing.T) {
 		addAuthCookie(req)
 
 		rec := httptest.NewRecorder()
-		web.UploadDocumentHandler(rec, req, *s, a)
+		web.UploadDocumentHandler(rec, req, s, a)
 
 		if !strings.Contains(rec.Body.String(), "Choose a document type") {
 			t.Error("expected an unknown type to be rejected")
@@ -176,7 +198,7 @@ func TestUploadDocumentHandler(t *testing.T) {
 		addAuthCookie(req)
 
 		rec := httptest.NewRecorder()
-		web.UploadDocumentHandler(rec, req, *s, a)
+		web.UploadDocumentHandler(rec, req, s, a)
 
 		_, err := os.Stat(filepath.Join(s.BaseDir, "articles", "evil.yaml"))
 		if err != nil {
@@ -198,7 +220,7 @@ func TestUploadDocumentHandler(t *testing.T) {
 			"md-file":   validMD,
 		})
 
-		web.UploadDocumentHandler(rec, req, *s, a)
+		web.UploadDocumentHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusSeeOther {
 			t.Errorf("expected a redirect, got %d", rec.Code)
@@ -219,7 +241,7 @@ func TestUploadDocumentHandler(t *testing.T) {
 		addAuthCookie(req)
 
 		rec := httptest.NewRecorder()
-		web.UploadDocumentHandler(rec, req, *s, a)
+		web.UploadDocumentHandler(rec, req, s, a)
 
 		written, err := os.ReadFile(filepath.Join(s.BaseDir, "articles", "post.md"))
 		if err != nil {
@@ -230,6 +252,82 @@ func TestUploadDocumentHandler(t *testing.T) {
 			t.Errorf("body truncated: wrote %d bytes, expected %d", len(written), len(body))
 		}
 	})
+
+	t.Run("writes a single file when the metadata is front matter", func(t *testing.T) {
+		s := uploadStorage(t)
+
+		req := uploadRequest(t, "articles", map[string]string{
+			"md-file": "post.md|---\ntitle: A Post\ndate: 2026-01-01\n---\n# A Post\n",
+		})
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+		web.UploadDocumentHandler(rec, req, s, a)
+
+		_, err := os.Stat(filepath.Join(s.BaseDir, "articles", "post.md"))
+		if err != nil {
+			t.Error("expected post.md to be written")
+		}
+
+		_, err = os.Stat(filepath.Join(s.BaseDir, "articles", "post.yaml"))
+		if !os.IsNotExist(err) {
+			t.Error("no .yaml should be written for a single-file upload")
+		}
+	})
+
+	t.Run("checks front matter against the required fields", func(t *testing.T) {
+		s := uploadStorage(t)
+
+		req := uploadRequest(t, "articles", map[string]string{
+			"md-file": "post.md|---\ntitle: No Date\n---\n# A Post\n",
+		})
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+		web.UploadDocumentHandler(rec, req, s, a)
+
+		if !strings.Contains(rec.Body.String(), "date") {
+			t.Error("expected the response to name the missing field")
+		}
+
+		_, err := os.Stat(filepath.Join(s.BaseDir, "articles", "post.md"))
+		if !os.IsNotExist(err) {
+			t.Error("nothing should be written when validation fails")
+		}
+	})
+
+	t.Run("warns about content the sanitizer will strip", func(t *testing.T) {
+		s := uploadStorage(t)
+
+		req := uploadRequest(t, "articles", map[string]string{
+			"yaml-file": validYAML,
+			"md-file":   "post.md|# A Post\n\n<script>alert(1)</script>\n",
+		})
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+		web.UploadDocumentHandler(rec, req, s, a)
+
+		if !strings.Contains(rec.Body.String(), "&lt;script&gt;") {
+			t.Errorf("expected the stripped script to be listed, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("requires a .yaml when the .md has no front matter", func(t *testing.T) {
+		s := uploadStorage(t)
+
+		req := uploadRequest(t, "articles", map[string]string{
+			"md-file": validMD,
+		})
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+		web.UploadDocumentHandler(rec, req, s, a)
+
+		if !strings.Contains(rec.Body.String(), "front matter") {
+			t.Error("expected the missing front matter to be reported")
+		}
+	})
 }
 
 func TestUploadPageHandler(t *testing.T) {
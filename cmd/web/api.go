@@ -0,0 +1,764 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "timterests/internal/errors"
+
+	"timterests/internal/auth"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// APIPrefix is where version 1 of the JSON API is served.
+const APIPrefix = "/api/v1"
+
+// A page of a list holds apiPerPage documents unless the client asks for a
+// different number, up to apiMaxPerPage.
+const (
+	apiPerPage    = 20
+	apiMaxPerPage = 100
+)
+
+// Body formats a client can ask for with ?body=.
+const (
+	apiBodyNone     = "none"
+	apiBodyHTML     = "html"
+	apiBodyMarkdown = "markdown"
+)
+
+// APIList is the response to a list request: one page of documents and where
+// it sits in the whole list.
+type APIList[T any] struct {
+	Data       []T           `json:"data"`
+	Pagination APIPagination `json:"pagination"`
+}
+
+// APIPagination locates a page in a list. Next and Prev are the paths of the
+// neighbouring pages, left out at either end.
+type APIPagination struct {
+	Page       int    `json:"page"`
+	PerPage    int    `json:"perPage"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"totalPages"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+}
+
+// APIItem is the response to a request for a single document.
+type APIItem[T any] struct {
+	Data T `json:"data"`
+}
+
+// APIDocument holds the fields every document served by the API has.
+type APIDocument struct {
+	Type      string   `json:"type"`
+	Slug      string   `json:"slug"`
+	URL       string   `json:"url"`
+	Title     string   `json:"title"`
+	Subtitle  string   `json:"subtitle,omitempty"`
+	Preview   string   `json:"preview,omitempty"`
+	Tags      []string `json:"tags"`
+	Status    string   `json:"status"`
+	PublishAt string   `json:"publishAt,omitempty"`
+	// Body is the document's text, rendered to HTML or as the Markdown it was
+	// written in, as BodyFormat says. Lists leave it out unless asked for it.
+	Body       string `json:"body,omitempty"`
+	BodyFormat string `json:"bodyFormat,omitempty"`
+
+	key string
+}
+
+func (d *APIDocument) document() *APIDocument {
+	return d
+}
+
+// APIArticle is an article as the API returns it.
+type APIArticle struct {
+	APIDocument
+
+	Date        model.Date `json:"date"`
+	Series      string     `json:"series,omitempty"`
+	SeriesOrder int        `json:"seriesOrder,omitempty"`
+}
+
+// APIProject is a project as the API returns it.
+type APIProject struct {
+	APIDocument
+
+	Repository string     `json:"repository,omitempty"`
+	Image      string     `json:"image,omitempty"`
+	StartDate  model.Date `json:"startDate,omitempty"`
+	EndDate    model.Date `json:"endDate,omitempty"`
+}
+
+// APIBook is a reading-list book as the API returns it.
+type APIBook struct {
+	APIDocument
+
+	Author        string         `json:"author"`
+	Published     model.Date     `json:"published,omitempty"`
+	ISBN          string         `json:"isbn,omitempty"`
+	Website       string         `json:"website,omitempty"`
+	Image         string         `json:"image,omitempty"`
+	Pages         int            `json:"pages,omitempty"`
+	ReadingStatus string         `json:"readingStatus"`
+	Started       model.Date     `json:"started,omitempty"`
+	Finished      model.Date     `json:"finished,omitempty"`
+	Progress      int            `json:"progress"`
+	Rating        int            `json:"rating,omitempty"`
+	Rereads       int            `json:"rereads,omitempty"`
+	Highlights    []APIHighlight `json:"highlights,omitempty"`
+}
+
+// APIHighlight is a passage marked in a book.
+type APIHighlight struct {
+	Text     string     `json:"text"`
+	Location string     `json:"location,omitempty"`
+	Added    model.Date `json:"added,omitempty"`
+	Note     string     `json:"note,omitempty"`
+}
+
+// APILetter is a letter as the API returns it.
+type APILetter struct {
+	APIDocument
+
+	Date     model.Date `json:"date"`
+	Occasion string     `json:"occasion,omitempty"`
+}
+
+// APICustom is a document of a schema-declared type as the API returns it,
+// with the schema's fields by name.
+type APICustom struct {
+	APIDocument
+
+	Fields map[string]any `json:"fields"`
+}
+
+// APIAbout is the about profile as the API returns it.
+type APIAbout struct {
+	About
+
+	Body       string `json:"body,omitempty"`
+	BodyFormat string `json:"bodyFormat,omitempty"`
+}
+
+func apiDocument(docType string, doc model.Document) APIDocument {
+	return APIDocument{
+		Type:      docType,
+		Slug:      doc.ID,
+		URL:       absoluteURL(DocumentURL(docType, doc.ID)),
+		Title:     doc.Title,
+		Subtitle:  doc.Subtitle,
+		Preview:   doc.Preview,
+		Tags:      append([]string{}, doc.Tags...),
+		Status:    doc.State(time.Now()),
+		PublishAt: doc.PublishAt,
+		key:       doc.S3Key,
+	}
+}
+
+func apiArticle(a model.Article) APIArticle {
+	return APIArticle{
+		APIDocument: apiDocument("articles", a.Document),
+		Date:        a.Date.Normalize(),
+		Series:      a.Series,
+		SeriesOrder: a.SeriesOrder,
+	}
+}
+
+func apiProject(p model.Project) APIProject {
+	return APIProject{
+		APIDocument: apiDocument("projects", p.Document),
+		Repository:  p.Repository,
+		Image:       absoluteURL(p.Image),
+		StartDate:   p.StartDate.Normalize(),
+		EndDate:     p.EndDate.Normalize(),
+	}
+}
+
+func apiBook(b model.ReadingList) APIBook {
+	book := APIBook{
+		APIDocument:   apiDocument("reading-list", b.Document),
+		Author:        b.Author,
+		Published:     b.Published.Normalize(),
+		ISBN:          b.ISBN,
+		Website:       b.Website,
+		Image:         absoluteURL(b.Image),
+		Pages:         b.Pages,
+		ReadingStatus: b.State(),
+		Started:       b.Started.Normalize(),
+		Finished:      b.Finished.Normalize(),
+		Progress:      b.PercentRead(),
+		Rating:        b.Rating,
+		Rereads:       b.Rereads,
+	}
+
+	for _, h := range b.Highlights {
+		book.Highlights = append(book.Highlights, APIHighlight{
+			Text:     h.Text,
+			Location: h.Location,
+			Added:    h.Added.Normalize(),
+			Note:     h.Note,
+		})
+	}
+
+	return book
+}
+
+func apiLetter(l model.Letter) APILetter {
+	return APILetter{
+		APIDocument: apiDocument("letters", l.Document),
+		Date:        l.Date.Normalize(),
+		Occasion:    l.Occasion,
+	}
+}
+
+func apiCustom(doc model.CustomDocument) APICustom {
+	fields := make(map[string]any, len(doc.Schema.Fields))
+
+	for _, f := range doc.Schema.Fields {
+		value, ok := doc.Fields[f.Name]
+		if ok {
+			fields[f.Name] = value
+		}
+	}
+
+	return APICustom{
+		APIDocument: apiDocument(doc.Schema.Name, doc.Document),
+		Fields:      fields,
+	}
+}
+
+// absoluteURL makes a site path absolute, so API clients elsewhere can follow it.
+// External URLs and empty values are left as they are.
+func absoluteURL(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return path
+	}
+
+	return strings.TrimSuffix(Site().URL, "/") + path
+}
+
+// apiQuery is the paging and body format a request asks for.
+type apiQuery struct {
+	page    int
+	perPage int
+	body    string
+}
+
+// parseAPIQuery reads ?page=, ?perPage= and ?body= from the request, with body
+// falling back to defaultBody.
+func parseAPIQuery(r *http.Request, defaultBody string) (apiQuery, error) {
+	q := apiQuery{page: 1, perPage: apiPerPage, body: defaultBody}
+	values := r.URL.Query()
+
+	if v := values.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return q, errors.New("page must be a whole number from 1")
+		}
+
+		q.page = page
+	}
+
+	if v := values.Get("perPage"); v != "" {
+		perPage, err := strconv.Atoi(v)
+		if err != nil || perPage < 1 || perPage > apiMaxPerPage {
+			return q, errors.New("perPage must be a whole number from 1 to " + strconv.Itoa(apiMaxPerPage))
+		}
+
+		q.perPage = perPage
+	}
+
+	switch v := values.Get("body"); v {
+	case "":
+	case apiBodyNone, apiBodyHTML, apiBodyMarkdown:
+		q.body = v
+	default:
+		return q, errors.New("body must be none, html or markdown")
+	}
+
+	return q, nil
+}
+
+// loadAPIBody fills in the document's body in the format asked for.
+func loadAPIBody(ctx context.Context, s storage.Backend, key, format string) (string, error) {
+	switch format {
+	case apiBodyHTML:
+		return storage.GetDocumentBody(ctx, s, key)
+	case apiBodyMarkdown:
+		return storage.GetDocumentBodyRaw(ctx, s, key)
+	default:
+		return "", nil
+	}
+}
+
+// writeAPIList responds with the page of docs the request asks for, loading
+// bodies only for the documents on it.
+func writeAPIList[T any, PT interface {
+	*T
+	document() *APIDocument
+}](w http.ResponseWriter, r *http.Request, s storage.Backend, docs []T, handler string) {
+	q, err := parseAPIQuery(r, apiBodyNone)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.BadRequest(err), handler, "parseQuery")
+
+		return
+	}
+
+	total := len(docs)
+	start := min((q.page-1)*q.perPage, total)
+	end := min(start+q.perPage, total)
+
+	page := make([]T, 0, end-start)
+	page = append(page, docs[start:end]...)
+
+	for i := range page {
+		doc := PT(&page[i]).document()
+
+		doc.Body, err = loadAPIBody(r.Context(), s, doc.key, q.body)
+		if err != nil {
+			HandleAPIError(w, r, apperrors.StorageFailed(err), handler, "loadBody")
+
+			return
+		}
+
+		if q.body != apiBodyNone {
+			doc.BodyFormat = q.body
+		}
+	}
+
+	list := APIList[T]{
+		Data: page,
+		Pagination: APIPagination{
+			Page:       q.page,
+			PerPage:    q.perPage,
+			Total:      total,
+			TotalPages: (total + q.perPage - 1) / q.perPage,
+		},
+	}
+
+	if q.page < list.Pagination.TotalPages {
+		list.Pagination.Next = apiPageURL(r, q.page+1)
+	}
+
+	if q.page > 1 {
+		list.Pagination.Prev = apiPageURL(r, min(q.page-1, max(list.Pagination.TotalPages, 1)))
+	}
+
+	err = renderJSON(w, http.StatusOK, list)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.RenderFailed(err), handler, "render")
+	}
+}
+
+// writeAPIDocument responds with a single document and its body, as HTML
+// unless the request asks otherwise.
+func writeAPIDocument[T any, PT interface {
+	*T
+	document() *APIDocument
+}](w http.ResponseWriter, r *http.Request, s storage.Backend, doc T, handler string) {
+	q, err := parseAPIQuery(r, apiBodyHTML)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.BadRequest(err), handler, "parseQuery")
+
+		return
+	}
+
+	base := PT(&doc).document()
+
+	base.Body, err = loadAPIBody(r.Context(), s, base.key, q.body)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.NotFound(err), handler, "loadBody")
+
+		return
+	}
+
+	if q.body != apiBodyNone {
+		base.BodyFormat = q.body
+	}
+
+	err = renderJSON(w, http.StatusOK, APIItem[T]{Data: doc})
+	if err != nil {
+		HandleAPIError(w, r, apperrors.RenderFailed(err), handler, "render")
+	}
+}
+
+// apiPageURL returns the path of another page of the list the request is for,
+// keeping its filters.
+func apiPageURL(r *http.Request, page int) string {
+	values := r.URL.Query()
+	values.Set("page", strconv.Itoa(page))
+
+	return r.URL.Path + "?" + values.Encode()
+}
+
+// apiAccess reports whether the request may do what scope allows. A bearer
+// token is held to its scopes, and one that is unknown or lacks the scope is an
+// error rather than a quietly public response. Without a token, the author's
+// session counts for reading only, so another site cannot write through their
+// browser.
+func apiAccess(r *http.Request, s storage.Backend, a *auth.Auth, scope string) (bool, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return scope == auth.ScopeRead && a.IsAuthenticated(r), nil
+	}
+
+	secret, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return false, apperrors.Unauthorized(errors.New("authorization must be a bearer token"))
+	}
+
+	token, err := auth.VerifyToken(r.Context(), s, strings.TrimSpace(secret), time.Now())
+	if errors.Is(err, auth.ErrInvalidToken) {
+		return false, apperrors.Unauthorized(err)
+	}
+
+	if err != nil {
+		return false, apperrors.StorageFailed(err)
+	}
+
+	if !token.HasScope(scope) {
+		return false, apperrors.Forbidden()
+	}
+
+	return true, nil
+}
+
+// APIArticlesHandler lists articles, optionally only those tagged ?tag=.
+// Drafts and scheduled articles are only listed for the author.
+func APIArticlesHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	articles, err := service.ListArticles(r.Context(), s, r.URL.Query().Get("tag"))
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), "APIArticlesHandler", "listArticles")
+
+		return
+	}
+
+	full, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APIArticlesHandler", "checkAuth")
+
+		return
+	}
+
+	if !full {
+		articles = service.Published(articles, time.Now())
+	}
+
+	docs := make([]APIArticle, len(articles))
+	for i := range articles {
+		docs[i] = apiArticle(articles[i])
+	}
+
+	writeAPIList(w, r, s, docs, "APIArticlesHandler")
+}
+
+// APIArticleHandler returns the article addressed by slug.
+func APIArticleHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, slug string, a *auth.Auth) {
+	article, err := service.GetArticleBySlug(r.Context(), s, slug)
+	if err != nil {
+		HandleAPIError(w, r, lookupError(err), "APIArticleHandler", "getArticle")
+
+		return
+	}
+
+	full, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APIArticleHandler", "checkAuth")
+
+		return
+	}
+
+	if !article.IsPublished(time.Now()) && !full {
+		HandleAPIError(w, r, apperrors.NotFound(nil), "APIArticleHandler", "checkPublished")
+
+		return
+	}
+
+	writeAPIDocument(w, r, s, apiArticle(*article), "APIArticleHandler")
+}
+
+// APIProjectsHandler lists projects, optionally only those tagged ?tag=.
+func APIProjectsHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	projects, err := service.ListProjects(r.Context(), s, r.URL.Query().Get("tag"))
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), "APIProjectsHandler", "listProjects")
+
+		return
+	}
+
+	full, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APIProjectsHandler", "checkAuth")
+
+		return
+	}
+
+	if !full {
+		projects = service.Published(projects, time.Now())
+	}
+
+	docs := make([]APIProject, len(projects))
+	for i := range projects {
+		docs[i] = apiProject(projects[i])
+	}
+
+	writeAPIList(w, r, s, docs, "APIProjectsHandler")
+}
+
+// APIProjectHandler returns the project addressed by slug.
+func APIProjectHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, slug string, a *auth.Auth) {
+	project, err := service.GetProjectBySlug(r.Context(), s, slug)
+	if err != nil {
+		HandleAPIError(w, r, lookupError(err), "APIProjectHandler", "getProject")
+
+		return
+	}
+
+	full, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APIProjectHandler", "checkAuth")
+
+		return
+	}
+
+	if !project.IsPublished(time.Now()) && !full {
+		HandleAPIError(w, r, apperrors.NotFound(nil), "APIProjectHandler", "checkPublished")
+
+		return
+	}
+
+	writeAPIDocument(w, r, s, apiProject(*project), "APIProjectHandler")
+}
+
+// APIBooksHandler lists the reading list, optionally only books tagged ?tag=
+// or in the reading state ?readingStatus=, ordered by ?sort= as the reading
+// list page is; see service.SortBooks.
+func APIBooksHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	query := r.URL.Query()
+
+	books, err := service.ListBooks(r.Context(), s, query.Get("tag"))
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), "APIBooksHandler", "listBooks")
+
+		return
+	}
+
+	full, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APIBooksHandler", "checkAuth")
+
+		return
+	}
+
+	if !full {
+		books = service.Published(books, time.Now())
+	}
+
+	books = service.FilterBooksByState(books, query.Get("readingStatus"))
+	service.SortBooks(books, query.Get("sort"))
+
+	docs := make([]APIBook, len(books))
+	for i := range books {
+		docs[i] = apiBook(books[i])
+	}
+
+	writeAPIList(w, r, s, docs, "APIBooksHandler")
+}
+
+// APIBookHandler returns the book addressed by slug.
+func APIBookHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, slug string, a *auth.Auth) {
+	book, err := service.GetBookBySlug(r.Context(), s, slug)
+	if err != nil {
+		HandleAPIError(w, r, lookupError(err), "APIBookHandler", "getBook")
+
+		return
+	}
+
+	full, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APIBookHandler", "checkAuth")
+
+		return
+	}
+
+	if !book.IsPublished(time.Now()) && !full {
+		HandleAPIError(w, r, apperrors.NotFound(nil), "APIBookHandler", "checkPublished")
+
+		return
+	}
+
+	writeAPIDocument(w, r, s, apiBook(*book), "APIBookHandler")
+}
+
+// APILettersHandler lists letters, optionally only those tagged ?tag=.
+// Letters are private, so only the author may list them.
+func APILettersHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
+	ok, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APILettersHandler", "checkAuth")
+
+		return
+	}
+
+	if !ok {
+		HandleAPIError(w, r, apperrors.Unauthorized(nil), "APILettersHandler", "checkAuth")
+
+		return
+	}
+
+	letters, err := service.ListLetters(r.Context(), s, r.URL.Query().Get("tag"))
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), "APILettersHandler", "listLetters")
+
+		return
+	}
+
+	docs := make([]APILetter, len(letters))
+	for i := range letters {
+		docs[i] = apiLetter(letters[i])
+	}
+
+	writeAPIList(w, r, s, docs, "APILettersHandler")
+}
+
+// APILetterHandler returns the letter addressed by slug, to the author only.
+func APILetterHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, slug string, a *auth.Auth) {
+	ok, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APILetterHandler", "checkAuth")
+
+		return
+	}
+
+	if !ok {
+		HandleAPIError(w, r, apperrors.Unauthorized(nil), "APILetterHandler", "checkAuth")
+
+		return
+	}
+
+	letter, err := service.GetLetterBySlug(r.Context(), s, slug)
+	if err != nil {
+		HandleAPIError(w, r, lookupError(err), "APILetterHandler", "getLetter")
+
+		return
+	}
+
+	writeAPIDocument(w, r, s, apiLetter(*letter), "APILetterHandler")
+}
+
+// APICustomListHandler lists the documents of a custom type, optionally only
+// those tagged ?tag=.
+func APICustomListHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, schema *model.DocumentSchema, a *auth.Auth) {
+	docs, err := service.ListCustom(r.Context(), s, schema, r.URL.Query().Get("tag"))
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), "APICustomListHandler", "listCustom")
+
+		return
+	}
+
+	full, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APICustomListHandler", "checkAuth")
+
+		return
+	}
+
+	if !full {
+		docs = service.Published(docs, time.Now())
+	}
+
+	items := make([]APICustom, len(docs))
+	for i := range docs {
+		items[i] = apiCustom(docs[i])
+	}
+
+	writeAPIList(w, r, s, items, "APICustomListHandler")
+}
+
+// APICustomHandler returns the document of a custom type addressed by slug.
+func APICustomHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	s storage.Backend,
+	schema *model.DocumentSchema,
+	slug string,
+	a *auth.Auth,
+) {
+	doc, err := service.GetCustomBySlug(r.Context(), s, schema, slug)
+	if err != nil {
+		HandleAPIError(w, r, lookupError(err), "APICustomHandler", "getDocument")
+
+		return
+	}
+
+	full, err := apiAccess(r, s, a, auth.ScopeRead)
+	if err != nil {
+		HandleAPIError(w, r, err, "APICustomHandler", "checkAuth")
+
+		return
+	}
+
+	if !doc.IsPublished(time.Now()) && !full {
+		HandleAPIError(w, r, apperrors.NotFound(nil), "APICustomHandler", "checkPublished")
+
+		return
+	}
+
+	writeAPIDocument(w, r, s, apiCustom(*doc), "APICustomHandler")
+}
+
+// APIAboutHandler returns the about profile with its body, as HTML unless the
+// request asks otherwise.
+func APIAboutHandler(w http.ResponseWriter, r *http.Request, s storage.Backend) {
+	q, err := parseAPIQuery(r, apiBodyHTML)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.BadRequest(err), "APIAboutHandler", "parseQuery")
+
+		return
+	}
+
+	about, err := loadAbout(r.Context(), s)
+	if err != nil {
+		HandleAPIError(w, r, err, "APIAboutHandler", "loadAbout")
+
+		return
+	}
+
+	profile := APIAbout{About: about}
+
+	switch q.body {
+	case apiBodyHTML:
+		profile.Body = about.Body
+	case apiBodyMarkdown:
+		profile.Body, err = storage.GetDocumentBodyRaw(r.Context(), s, about.Key)
+		if err != nil {
+			HandleAPIError(w, r, apperrors.StorageFailed(err), "APIAboutHandler", "getBody")
+
+			return
+		}
+	}
+
+	if q.body != apiBodyNone {
+		profile.BodyFormat = q.body
+	}
+
+	err = renderJSON(w, http.StatusOK, APIItem[APIAbout]{Data: profile})
+	if err != nil {
+		HandleAPIError(w, r, apperrors.RenderFailed(err), "APIAboutHandler", "render")
+	}
+}
+
+// APINotFoundHandler answers API paths that do not exist with a JSON error
+// rather than the site's error page.
+func APINotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	HandleAPIError(w, r, apperrors.NotFound(nil), "APINotFoundHandler", "route")
+}
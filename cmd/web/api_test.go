@@ -0,0 +1,260 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+	"timterests/internal/storage"
+)
+
+// apiGet calls handler for target and decodes the JSON it returns into out.
+func apiGet(t *testing.T, target string, handler http.HandlerFunc, out any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+
+	handler(rec, req)
+
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("expected a JSON response, got %q", rec.Header().Get("Content-Type"))
+	}
+
+	err := json.Unmarshal(rec.Body.Bytes(), out)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", rec.Body.String(), err)
+	}
+
+	return rec
+}
+
+func TestAPIArticles(t *testing.T) {
+	s := testSetup(t)
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+	err := storage.WriteDocument(context.Background(), s, "articles/draft-article.yaml",
+		[]byte("title: Draft Article\ndate: \"2026-02-01\"\nstatus: draft\n"), []byte("Draft body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	list := func(w http.ResponseWriter, r *http.Request) { web.APIArticlesHandler(w, r, s, a) }
+
+	t.Run("lists published articles without bodies", func(t *testing.T) {
+		var got web.APIList[web.APIArticle]
+
+		rec := apiGet(t, "/api/v1/articles", list, &got)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+
+		if len(got.Data) != 1 || got.Data[0].Slug != "test-article" {
+			t.Fatalf("expected only the published article, got %+v", got.Data)
+		}
+
+		article := got.Data[0]
+		if article.Date != "2026-01-01" || article.Type != "articles" || !strings.HasSuffix(article.URL, "/articles/test-article") {
+			t.Errorf("unexpected article: %+v", article)
+		}
+
+		if article.Body != "" {
+			t.Error("expected no body in a list unless asked for")
+		}
+
+		if got.Pagination.Total != 1 || got.Pagination.Page != 1 || got.Pagination.TotalPages != 1 {
+			t.Errorf("unexpected pagination: %+v", got.Pagination)
+		}
+	})
+
+	t.Run("filters by tag", func(t *testing.T) {
+		var got web.APIList[web.APIArticle]
+
+		apiGet(t, "/api/v1/articles?tag=no-such-tag", list, &got)
+
+		if got.Data == nil || len(got.Data) != 0 {
+			t.Errorf("expected an empty list, got %+v", got.Data)
+		}
+	})
+
+	t.Run("includes bodies when asked", func(t *testing.T) {
+		var got web.APIList[web.APIArticle]
+
+		apiGet(t, "/api/v1/articles?body=markdown", list, &got)
+
+		if len(got.Data) != 1 || !strings.Contains(got.Data[0].Body, "# Test Article") || got.Data[0].BodyFormat != "markdown" {
+			t.Errorf("expected the Markdown body, got %+v", got.Data)
+		}
+	})
+
+	t.Run("rejects a bad page", func(t *testing.T) {
+		var got web.APIError
+
+		rec := apiGet(t, "/api/v1/articles?page=0", list, &got)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", rec.Code)
+		}
+
+		if got.Error.Code != "BAD_REQUEST" || !strings.Contains(got.Error.Detail, "page") {
+			t.Errorf("unexpected error: %+v", got.Error)
+		}
+	})
+}
+
+func TestAPIArticle(t *testing.T) {
+	s := testSetup(t)
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+	get := func(slug string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) { web.APIArticleHandler(w, r, s, slug, a) }
+	}
+
+	t.Run("returns the rendered body", func(t *testing.T) {
+		var got web.APIItem[web.APIArticle]
+
+		rec := apiGet(t, "/api/v1/articles/test-article", get("test-article"), &got)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+
+		if got.Data.Title != "Test Article" || got.Data.BodyFormat != "html" || !strings.Contains(got.Data.Body, "<h1") {
+			t.Errorf("unexpected article: %+v", got.Data)
+		}
+	})
+
+	t.Run("returns the raw body", func(t *testing.T) {
+		var got web.APIItem[web.APIArticle]
+
+		apiGet(t, "/api/v1/articles/test-article?body=markdown", get("test-article"), &got)
+
+		if !strings.HasPrefix(got.Data.Body, "# Test Article") {
+			t.Errorf("expected the Markdown body, got %q", got.Data.Body)
+		}
+	})
+
+	t.Run("answers a missing article with a JSON 404", func(t *testing.T) {
+		var got web.APIError
+
+		rec := apiGet(t, "/api/v1/articles/does-not-exist", get("does-not-exist"), &got)
+		if rec.Code != http.StatusNotFound || got.Error.Code != "NOT_FOUND" {
+			t.Errorf("expected a 404, got %d %+v", rec.Code, got)
+		}
+	})
+}
+
+func TestAPIProjectsPagination(t *testing.T) {
+	s := testSetup(t)
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+	list := func(w http.ResponseWriter, r *http.Request) { web.APIProjectsHandler(w, r, s, a) }
+
+	var got web.APIList[web.APIProject]
+
+	apiGet(t, "/api/v1/projects?perPage=1&page=2", list, &got)
+
+	if len(got.Data) != 1 {
+		t.Fatalf("expected one project, got %d", len(got.Data))
+	}
+
+	p := got.Pagination
+	if p.Total != 3 || p.TotalPages != 3 || p.Page != 2 {
+		t.Errorf("unexpected pagination: %+v", p)
+	}
+
+	if !strings.Contains(p.Next, "page=3") || !strings.Contains(p.Prev, "page=1") || !strings.Contains(p.Next, "perPage=1") {
+		t.Errorf("expected links to the neighbouring pages, got %q and %q", p.Next, p.Prev)
+	}
+}
+
+func TestAPIBooks(t *testing.T) {
+	s := testSetup(t)
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+	list := func(w http.ResponseWriter, r *http.Request) { web.APIBooksHandler(w, r, s, a) }
+
+	var got web.APIList[web.APIBook]
+
+	apiGet(t, "/api/v1/books", list, &got)
+
+	if len(got.Data) != 2 {
+		t.Fatalf("expected both books, got %d", len(got.Data))
+	}
+
+	for _, book := range got.Data {
+		if book.Author == "" || book.ReadingStatus != "finished" || book.Progress != 100 {
+			t.Errorf("unexpected book: %+v", book)
+		}
+	}
+
+	apiGet(t, "/api/v1/books?readingStatus=reading", list, &got)
+
+	if len(got.Data) != 0 {
+		t.Errorf("expected no books being read, got %d", len(got.Data))
+	}
+}
+
+func TestAPILetters(t *testing.T) {
+	s := testSetup(t)
+	a, addAuthCookie := testAuthentication(t)
+
+	t.Run("requires authentication", func(t *testing.T) {
+		var got web.APIError
+
+		rec := apiGet(t, "/api/v1/letters", func(w http.ResponseWriter, r *http.Request) {
+			web.APILettersHandler(w, r, s, a)
+		}, &got)
+		if rec.Code != http.StatusUnauthorized || got.Error.Code != "UNAUTHORIZED" {
+			t.Errorf("expected a 401, got %d %+v", rec.Code, got)
+		}
+
+		rec = apiGet(t, "/api/v1/letters/test-letter", func(w http.ResponseWriter, r *http.Request) {
+			web.APILetterHandler(w, r, s, "test-letter", a)
+		}, &got)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected a 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("lists letters for the author", func(t *testing.T) {
+		var got web.APIList[web.APILetter]
+
+		apiGet(t, "/api/v1/letters", func(w http.ResponseWriter, r *http.Request) {
+			addAuthCookie(r)
+			web.APILettersHandler(w, r, s, a)
+		}, &got)
+
+		if len(got.Data) != 1 || got.Data[0].Slug != "test-letter" || got.Data[0].Date != "2023-01-01" {
+			t.Errorf("expected the test letter, got %+v", got.Data)
+		}
+	})
+}
+
+func TestAPIAbout(t *testing.T) {
+	s := testSetup(t)
+
+	var got web.APIItem[web.APIAbout]
+
+	rec := apiGet(t, "/api/v1/about", func(w http.ResponseWriter, r *http.Request) {
+		web.APIAboutHandler(w, r, s)
+	}, &got)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	if got.Data.Name != "Test User" || got.Data.BodyFormat != "html" || got.Data.Body == "" {
+		t.Errorf("unexpected profile: %+v", got.Data)
+	}
+}
+
+func TestAPINotFound(t *testing.T) {
+	var got web.APIError
+
+	rec := apiGet(t, "/api/v1/nothing-here", web.APINotFoundHandler, &got)
+	if rec.Code != http.StatusNotFound || got.Error.Code != "NOT_FOUND" {
+		t.Errorf("expected a JSON 404, got %d %+v", rec.Code, got)
+	}
+}
@@ -0,0 +1,439 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	apperrors "timterests/internal/errors"
+
+	"timterests/internal/auth"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// apiDocType maps the type segment of an API path to its content directory.
+// Books are served as /books but stored under reading-list.
+func apiDocType(segment string) (string, bool) {
+	if segment == "books" {
+		return "reading-list", true
+	}
+
+	if segment == "reading-list" || !slices.Contains(DocTypes(), segment) {
+		return "", false
+	}
+
+	return segment, true
+}
+
+// apiTypeSegment is the inverse of apiDocType.
+func apiTypeSegment(docType string) string {
+	if docType == "reading-list" {
+		return "books"
+	}
+
+	return docType
+}
+
+// APICreateDocumentHandler creates a document of the type named by the path
+// from a JSON object of its fields, with the Markdown under "body". The slug is
+// worked out from the title as the writer does unless "slug" is given, and an
+// existing document is never overwritten. Needs a token with the write scope.
+func APICreateDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, segment string, a *auth.Auth) {
+	docType, ok := apiWriteAccess(w, r, s, segment, auth.ScopeWrite, a, "APICreateDocumentHandler")
+	if !ok {
+		return
+	}
+
+	formData, err := decodeAPIDocument(r, docType)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.BadRequest(err), "APICreateDocumentHandler", "decodeBody")
+
+		return
+	}
+
+	err = prepareAPIDocument(formData, docType)
+	if err != nil {
+		HandleAPIError(w, r, err, "APICreateDocumentHandler", "validateDocument")
+
+		return
+	}
+
+	slug, err := apiSlug(formData, docType)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.BadRequest(err), "APICreateDocumentHandler", "generateSlug")
+
+		return
+	}
+
+	key := docType + "/" + slug + ".yaml"
+
+	meta, body, err := storage.ReadDocument(r.Context(), s, key)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), "APICreateDocumentHandler", "checkExisting")
+
+		return
+	}
+
+	if meta != nil || body != nil {
+		HandleAPIError(w, r, apperrors.Conflict(fmt.Errorf("%s/%s already exists", segment, slug)),
+			"APICreateDocumentHandler", "checkExisting")
+
+		return
+	}
+
+	err = storeAPIDocument(r.Context(), s, key, docType, formData)
+	if err != nil {
+		HandleAPIError(w, r, err, "APICreateDocumentHandler", "writeDocument")
+
+		return
+	}
+
+	w.Header().Set("Location", APIPrefix+"/"+apiTypeSegment(docType)+"/"+slug)
+	renderStoredDocument(w, r, s, docType, slug, http.StatusCreated, "APICreateDocumentHandler")
+}
+
+// APIUpdateDocumentHandler replaces the document addressed by the path with a
+// JSON object of its fields, keeping the fields the writer keeps, such as a
+// book's highlights. The previous version is kept in the document's history.
+// Needs a token with the write scope.
+func APIUpdateDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, segment, slug string, a *auth.Auth) {
+	docType, ok := apiWriteAccess(w, r, s, segment, auth.ScopeWrite, a, "APIUpdateDocumentHandler")
+	if !ok {
+		return
+	}
+
+	key, ok := existingDocumentKey(w, r, s, docType, slug, "APIUpdateDocumentHandler")
+	if !ok {
+		return
+	}
+
+	formData, err := decodeAPIDocument(r, docType)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.BadRequest(err), "APIUpdateDocumentHandler", "decodeBody")
+
+		return
+	}
+
+	delete(formData, "slug")
+
+	err = prepareAPIDocument(formData, docType)
+	if err != nil {
+		HandleAPIError(w, r, err, "APIUpdateDocumentHandler", "validateDocument")
+
+		return
+	}
+
+	err = carryOverFields(r.Context(), s, key, formData, carriedFields[docType])
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), "APIUpdateDocumentHandler", "carryOverFields")
+
+		return
+	}
+
+	err = storeAPIDocument(r.Context(), s, key, docType, formData)
+	if err != nil {
+		HandleAPIError(w, r, err, "APIUpdateDocumentHandler", "writeDocument")
+
+		return
+	}
+
+	renderStoredDocument(w, r, s, docType, slug, http.StatusOK, "APIUpdateDocumentHandler")
+}
+
+// APIDeleteDocumentHandler moves the document addressed by the path to the
+// trash, where it can be restored from the admin page. Needs a token with the
+// delete scope.
+func APIDeleteDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, segment, slug string, a *auth.Auth) {
+	docType, ok := apiWriteAccess(w, r, s, segment, auth.ScopeDelete, a, "APIDeleteDocumentHandler")
+	if !ok {
+		return
+	}
+
+	key, ok := existingDocumentKey(w, r, s, docType, slug, "APIDeleteDocumentHandler")
+	if !ok {
+		return
+	}
+
+	_, err := storage.TrashDocument(r.Context(), s, key)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), "APIDeleteDocumentHandler", "trashDocument")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiWriteAccess does the checks shared by the write endpoints and returns the
+// content directory the path addresses. Writing takes a token; the author's
+// session is not enough.
+func apiWriteAccess(
+	w http.ResponseWriter,
+	r *http.Request,
+	s storage.Backend,
+	segment, scope string,
+	a *auth.Auth,
+	handler string,
+) (string, bool) {
+	ok, err := apiAccess(r, s, a, scope)
+	if err != nil {
+		HandleAPIError(w, r, err, handler, "checkAuth")
+
+		return "", false
+	}
+
+	if !ok {
+		HandleAPIError(w, r, apperrors.Unauthorized(errors.New("a personal access token is required")), handler, "checkAuth")
+
+		return "", false
+	}
+
+	docType, ok := apiDocType(segment)
+	if !ok {
+		HandleAPIError(w, r, apperrors.NotFound(nil), handler, "checkType")
+
+		return "", false
+	}
+
+	return docType, true
+}
+
+// existingDocumentKey returns the key of the document of docType at slug,
+// answering 404 itself when there is none.
+func existingDocumentKey(w http.ResponseWriter, r *http.Request, s storage.Backend, docType, slug, handler string) (string, bool) {
+	if storage.SanitizeFilename(slug) != slug {
+		HandleAPIError(w, r, apperrors.NotFound(nil), handler, "checkSlug")
+
+		return "", false
+	}
+
+	key := docType + "/" + slug + ".yaml"
+
+	meta, body, err := storage.ReadDocument(r.Context(), s, key)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), handler, "readDocument")
+
+		return "", false
+	}
+
+	if meta == nil && body == nil {
+		HandleAPIError(w, r, apperrors.NotFound(nil), handler, "readDocument")
+
+		return "", false
+	}
+
+	return key, true
+}
+
+// decodeAPIDocument reads the JSON object in the request body into the form
+// data the writer works with. Numbers are kept as whole numbers where they are
+// whole, so they decode into the model's int fields.
+func decodeAPIDocument(r *http.Request, docType string) (map[string]any, error) {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxUploadBytes))
+	decoder.UseNumber()
+
+	var formData map[string]any
+
+	err := decoder.Decode(&formData)
+	if err != nil {
+		return nil, fmt.Errorf("the body must be a JSON object: %w", err)
+	}
+
+	if formData == nil {
+		return nil, errors.New("the body must be a JSON object")
+	}
+
+	for key, value := range formData {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+
+		whole, err := number.Int64()
+		if err == nil {
+			formData[key] = int(whole)
+
+			continue
+		}
+
+		formData[key], err = number.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s for %s", number, key)
+		}
+	}
+
+	// The reading fields are checked as the writer form sends them, as text.
+	if docType == "reading-list" {
+		for _, key := range bookNumberFields {
+			value, ok := formData[key]
+			if ok {
+				formData[key] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	return formData, nil
+}
+
+// apiSlug returns the slug a new document is created at: the "slug" field if
+// given, otherwise the one the writer would choose. The field is not stored.
+func apiSlug(formData map[string]any, docType string) (string, error) {
+	value, ok := formData["slug"]
+	if !ok {
+		return generateSlug(formData, docType)
+	}
+
+	delete(formData, "slug")
+
+	slug, _ := value.(string)
+	if slug == "" || storage.SanitizeFilename(slug) != slug {
+		return "", fmt.Errorf("invalid slug %q: use lowercase letters, numbers and hyphens", slug)
+	}
+
+	return slug, nil
+}
+
+// prepareAPIDocument checks and coerces formData the way the writer does, so
+// dates are in ISO 8601 before a slug is worked out from them.
+func prepareAPIDocument(formData map[string]any, docType string) error {
+	body, ok := formData["body"]
+	if ok {
+		_, ok = body.(string)
+		if !ok {
+			return apperrors.BadRequest(errors.New("body must be a string"))
+		}
+	}
+
+	err := validatePublication(formData)
+	if err != nil {
+		return apperrors.BadRequest(err)
+	}
+
+	err = normalizeDates(formData, docType)
+	if err != nil {
+		return apperrors.BadRequest(err)
+	}
+
+	schema, ok := customType(docType)
+	if ok {
+		coerceCustomFields(formData, schema)
+	}
+
+	if docType == "reading-list" {
+		err = coerceBookFields(formData)
+		if err != nil {
+			return apperrors.BadRequest(err)
+		}
+	}
+
+	return nil
+}
+
+// storeAPIDocument writes prepared formData at key, keeping the previous
+// version in the document's history. A document that would not read back as
+// its type is rejected before anything is written.
+func storeAPIDocument(ctx context.Context, s storage.Backend, key, docType string, formData map[string]any) error {
+	yamlBytes, mdBytes, err := storage.MarshalMarkdownDocument(formData)
+	if err != nil {
+		return apperrors.BadRequest(err)
+	}
+
+	err = validateDocumentYAML(yamlBytes, docType)
+	if err != nil {
+		return apperrors.BadRequest(err)
+	}
+
+	err = storage.WriteDocument(ctx, s, key, yamlBytes, mdBytes)
+	if err != nil {
+		return apperrors.StorageFailed(err)
+	}
+
+	return nil
+}
+
+// renderStoredDocument answers a write with the document as it now reads
+// through the API, Markdown body included.
+func renderStoredDocument(w http.ResponseWriter, r *http.Request, s storage.Backend, docType, slug string, status int, handler string) {
+	doc, err := apiStoredDocument(r.Context(), s, docType, slug)
+	if err != nil {
+		HandleAPIError(w, r, lookupError(err), handler, "readBack")
+
+		return
+	}
+
+	d := doc.document()
+
+	d.Body, err = storage.GetDocumentBodyRaw(r.Context(), s, d.key)
+	if err != nil {
+		HandleAPIError(w, r, apperrors.StorageFailed(err), handler, "readBack")
+
+		return
+	}
+
+	d.BodyFormat = apiBodyMarkdown
+
+	err = renderJSON(w, status, APIItem[any]{Data: doc})
+	if err != nil {
+		HandleAPIError(w, r, apperrors.RenderFailed(err), handler, "render")
+	}
+}
+
+// apiStoredDocument loads the document of docType at slug as its API type.
+func apiStoredDocument(ctx context.Context, s storage.Backend, docType, slug string) (interface{ document() *APIDocument }, error) {
+	switch docType {
+	case "articles":
+		article, err := service.GetArticleBySlug(ctx, s, slug)
+		if err != nil {
+			return nil, err
+		}
+
+		doc := apiArticle(*article)
+
+		return &doc, nil
+	case "projects":
+		project, err := service.GetProjectBySlug(ctx, s, slug)
+		if err != nil {
+			return nil, err
+		}
+
+		doc := apiProject(*project)
+
+		return &doc, nil
+	case "reading-list":
+		book, err := service.GetBookBySlug(ctx, s, slug)
+		if err != nil {
+			return nil, err
+		}
+
+		doc := apiBook(*book)
+
+		return &doc, nil
+	case "letters":
+		letter, err := service.GetLetterBySlug(ctx, s, slug)
+		if err != nil {
+			return nil, err
+		}
+
+		doc := apiLetter(*letter)
+
+		return &doc, nil
+	default:
+		schema, ok := customType(docType)
+		if !ok {
+			return nil, fmt.Errorf("unknown document type %q", docType)
+		}
+
+		custom, err := service.GetCustomBySlug(ctx, s, schema, slug)
+		if err != nil {
+			return nil, err
+		}
+
+		doc := apiCustom(*custom)
+
+		return &doc, nil
+	}
+}
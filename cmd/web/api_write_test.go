@@ -0,0 +1,268 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+	"timterests/internal/storage"
+)
+
+// apiToken mints a token with scopes in s and returns its secret.
+func apiToken(t *testing.T, s storage.Backend, scopes ...string) string {
+	t.Helper()
+
+	secret, _, err := auth.CreateToken(context.Background(), s, "Test script", scopes, time.Now())
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	return secret
+}
+
+// apiWrite sends body to handler with the method and token given and decodes
+// any JSON it returns into out.
+func apiWrite(t *testing.T, method, target, token, body string, handler http.HandlerFunc, out any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+
+	handler(rec, req)
+
+	if out != nil && rec.Body.Len() > 0 {
+		err := json.Unmarshal(rec.Body.Bytes(), out)
+		if err != nil {
+			t.Fatalf("failed to decode %s: %v", rec.Body.String(), err)
+		}
+	}
+
+	return rec
+}
+
+func TestAPICreateDocument(t *testing.T) {
+	s := testSetup(t)
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+	token := apiToken(t, s, auth.ScopeWrite)
+
+	create := func(segment string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) { web.APICreateDocumentHandler(w, r, s, segment, a) }
+	}
+
+	t.Run("creates an article", func(t *testing.T) {
+		var got web.APIItem[web.APIArticle]
+
+		rec := apiWrite(t, http.MethodPost, "/api/v1/articles", token,
+			`{"title": "From The API", "subtitle": "Sub", "date": "March 3, 2026", "tags": ["api"], "body": "Hello."}`,
+			create("articles"), &got)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		if rec.Header().Get("Location") != "/api/v1/articles/from-the-api-03-03-2026" {
+			t.Errorf("unexpected location %q", rec.Header().Get("Location"))
+		}
+
+		if got.Data.Slug != "from-the-api-03-03-2026" || got.Data.Date != "2026-03-03" || !strings.Contains(got.Data.Body, "Hello.") {
+			t.Errorf("unexpected article: %+v", got.Data)
+		}
+
+		revisions, err := storage.ListRevisions(context.Background(), s, "articles/from-the-api-03-03-2026.yaml")
+		if err != nil || len(revisions) != 0 {
+			t.Errorf("expected a new document without history, got %d (%v)", len(revisions), err)
+		}
+	})
+
+	t.Run("creates a book at the given slug", func(t *testing.T) {
+		var got web.APIItem[web.APIBook]
+
+		rec := apiWrite(t, http.MethodPost, "/api/v1/books", token,
+			`{"slug": "api-book", "title": "API Book", "subtitle": "Sub", "author": "Someone", "pages": 320, "readingStatus": "reading", "progress": 40}`,
+			create("books"), &got)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		if got.Data.Slug != "api-book" || got.Data.Pages != 320 || got.Data.Progress != 40 || got.Data.Type != "reading-list" {
+			t.Errorf("unexpected book: %+v", got.Data)
+		}
+	})
+
+	t.Run("rejects a document that fails validation", func(t *testing.T) {
+		var got web.APIError
+
+		rec := apiWrite(t, http.MethodPost, "/api/v1/projects", token,
+			`{"title": "No Subtitle", "startDate": "someday"}`, create("projects"), &got)
+		if rec.Code != http.StatusBadRequest || got.Error.Detail == "" {
+			t.Errorf("expected a 400 with detail, got %d %+v", rec.Code, got)
+		}
+
+		meta, _, _ := storage.ReadDocument(context.Background(), s, "projects/no-subtitle.yaml")
+		if meta != nil {
+			t.Error("expected nothing to be written")
+		}
+	})
+
+	t.Run("rejects an invalid slug", func(t *testing.T) {
+		rec := apiWrite(t, http.MethodPost, "/api/v1/projects", token,
+			`{"slug": "../escape", "title": "Escape", "subtitle": "Sub"}`, create("projects"), nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("never overwrites an existing document", func(t *testing.T) {
+		var got web.APIError
+
+		rec := apiWrite(t, http.MethodPost, "/api/v1/projects", token,
+			`{"slug": "test-project", "title": "Replaced", "subtitle": "Sub"}`, create("projects"), &got)
+		if rec.Code != http.StatusConflict || got.Error.Code != "CONFLICT" {
+			t.Errorf("expected a 409, got %d %+v", rec.Code, got)
+		}
+	})
+
+	t.Run("answers an unknown type with a 404", func(t *testing.T) {
+		rec := apiWrite(t, http.MethodPost, "/api/v1/widgets", token, `{"title": "W"}`, create("widgets"), nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestAPIWriteAuthorization(t *testing.T) {
+	s := testSetup(t)
+	a, addAuthCookie := testAuthentication(t)
+	readOnly := apiToken(t, s, auth.ScopeRead)
+
+	create := func(w http.ResponseWriter, r *http.Request) { web.APICreateDocumentHandler(w, r, s, "projects", a) }
+	body := `{"title": "Unwanted", "subtitle": "Sub"}`
+
+	t.Run("requires a token", func(t *testing.T) {
+		rec := apiWrite(t, http.MethodPost, "/api/v1/projects", "", body, create, nil)
+		if rec.Code != http.StatusUnauthorized || !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Bearer") {
+			t.Errorf("expected a 401 with a bearer challenge, got %d %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+		}
+	})
+
+	t.Run("a session is not enough to write", func(t *testing.T) {
+		rec := apiWrite(t, http.MethodPost, "/api/v1/projects", "", body, func(w http.ResponseWriter, r *http.Request) {
+			addAuthCookie(r)
+			create(w, r)
+		}, nil)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects an unknown token", func(t *testing.T) {
+		rec := apiWrite(t, http.MethodPost, "/api/v1/projects", auth.TokenPrefix+"nope", body, create, nil)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects a token without the scope", func(t *testing.T) {
+		rec := apiWrite(t, http.MethodPost, "/api/v1/projects", readOnly, body, create, nil)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("a read token sees drafts", func(t *testing.T) {
+		err := storage.WriteDocument(context.Background(), s, "articles/draft-article.yaml",
+			[]byte("title: Draft Article\ndate: \"2026-02-01\"\nstatus: draft\n"), []byte("Draft body\n"))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+
+		var got web.APIList[web.APIArticle]
+
+		rec := apiWrite(t, http.MethodGet, "/api/v1/articles", readOnly, "", func(w http.ResponseWriter, r *http.Request) {
+			web.APIArticlesHandler(w, r, s, a)
+		}, &got)
+		if rec.Code != http.StatusOK || len(got.Data) != 2 {
+			t.Errorf("expected both articles, got %d %+v", rec.Code, got.Data)
+		}
+	})
+}
+
+func TestAPIUpdateAndDeleteDocument(t *testing.T) {
+	s := testSetup(t)
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+	writer := apiToken(t, s, auth.ScopeWrite)
+	deleter := apiToken(t, s, auth.ScopeDelete)
+
+	update := func(slug string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			web.APIUpdateDocumentHandler(w, r, s, "projects", slug, a)
+		}
+	}
+
+	remove := func(slug string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			web.APIDeleteDocumentHandler(w, r, s, "projects", slug, a)
+		}
+	}
+
+	t.Run("updates a document and keeps its history", func(t *testing.T) {
+		var got web.APIItem[web.APIProject]
+
+		rec := apiWrite(t, http.MethodPut, "/api/v1/projects/test-project", writer,
+			`{"title": "Renamed Project", "subtitle": "Sub", "body": "New body."}`, update("test-project"), &got)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		if got.Data.Slug != "test-project" || got.Data.Title != "Renamed Project" || !strings.Contains(got.Data.Body, "New body.") {
+			t.Errorf("unexpected project: %+v", got.Data)
+		}
+
+		revisions, err := storage.ListRevisions(context.Background(), s, "projects/test-project.yaml")
+		if err != nil || len(revisions) != 1 {
+			t.Errorf("expected the old version in history, got %d (%v)", len(revisions), err)
+		}
+	})
+
+	t.Run("answers a missing document with a 404", func(t *testing.T) {
+		rec := apiWrite(t, http.MethodPut, "/api/v1/projects/nope", writer,
+			`{"title": "Nope", "subtitle": "Sub"}`, update("nope"), nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("deleting needs the delete scope", func(t *testing.T) {
+		rec := apiWrite(t, http.MethodDelete, "/api/v1/projects/timterests", writer, "", remove("timterests"), nil)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("moves a document to the trash", func(t *testing.T) {
+		rec := apiWrite(t, http.MethodDelete, "/api/v1/projects/timterests", deleter, "", remove("timterests"), nil)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected status 204, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		items, err := storage.ListTrash(context.Background(), s)
+		if err != nil || len(items) != 1 || items[0].Slug != "timterests" {
+			t.Errorf("expected the project in the trash, got %+v (%v)", items, err)
+		}
+
+		rec = apiWrite(t, http.MethodDelete, "/api/v1/projects/timterests", deleter, "", remove("timterests"), nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected a second delete to 404, got %d", rec.Code)
+		}
+	})
+}
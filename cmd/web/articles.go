@@ -3,6 +3,7 @@ package web
 import (
 	"net/http"
 	"reflect"
+	"time"
 
 	apperrors "timterests/internal/errors"
 
@@ -14,7 +15,7 @@ import (
 	"github.com/a-h/templ"
 )
 
-func ArticlesPageHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, currentTag, design string) {
+func ArticlesPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, currentTag, design string) {
 	var (
 		component templ.Component
 		tags      []string
@@ -27,6 +28,8 @@ func ArticlesPageHandler(w http.ResponseWriter, r *http.Request, s storage.Stora
 		return
 	}
 
+	articles = service.Published(articles, time.Now())
+
 	for i := range articles {
 		v := reflect.ValueOf(articles[i])
 		tags = storage.GetTags(v, tags)
@@ -46,53 +49,62 @@ func ArticlesPageHandler(w http.ResponseWriter, r *http.Request, s storage.Stora
 	}
 }
 
-func GetArticleHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, articleID string, a *auth.Auth) {
-	articles, err := service.ListArticles(r.Context(), s, "all")
+func GetArticleHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, slug string, a *auth.Auth) {
+	article, err := service.GetArticleBySlug(r.Context(), s, slug)
 	if err != nil {
-		HandleError(w, r, apperrors.StorageFailed(err), "GetArticleHandler", "listArticles")
+		HandleError(w, r, lookupError(err), "GetArticleHandler", "getArticle")
 
 		return
 	}
 
-	for _, article := range articles {
-		if article.ID == articleID {
-			body, err := s.GetDocumentBody(r.Context(), article.S3Key)
-			if err != nil {
-				HandleError(w, r, apperrors.NotFound(err), "GetArticleHandler", "getBody")
+	authenticated := a.IsAuthenticated(r)
 
-				return
-			}
+	// Drafts and scheduled documents are only reachable by the author.
+	if !article.IsPublished(time.Now()) && !authenticated {
+		HandleError(w, r, apperrors.NotFound(nil), "GetArticleHandler", "checkPublished")
 
-			dc := model.DisplayContent{
-				ID:    article.ID,
-				S3Key: article.S3Key,
-				Body:  body,
-			}
+		return
+	}
+
+	body, headings, err := storage.GetDocumentBodyWithHeadings(r.Context(), s, article.S3Key)
+	if err != nil {
+		HandleError(w, r, apperrors.NotFound(err), "GetArticleHandler", "getBody")
 
-			var component templ.Component
+		return
+	}
+
+	series, err := service.GetSeriesNav(r.Context(), s, article, time.Now())
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "GetArticleHandler", "getSeriesNav")
 
-			authenticated := a.IsAuthenticated(r)
+		return
+	}
 
-			if IsHTMXRequest(r) {
-				SetPartialResponseHeaders(w)
+	dc := model.DisplayContent{
+		ID:        article.ID,
+		S3Key:     article.S3Key,
+		Body:      body,
+		TOC:       article.TableOfContents(headings),
+		Series:    series,
+		Backlinks: backlinks(r.Context(), s, "articles", article.ID),
+	}
 
-				component = ArticleDisplay(dc, authenticated)
-			} else {
-				component = ArticlePage(article, dc, authenticated)
-			}
+	related := relatedCards(r.Context(), s, "articles", article.Document)
 
-			err = renderHTML(w, r, http.StatusOK, component)
-			if err != nil {
-				HandleError(w, r, apperrors.RenderFailed(err), "GetArticleHandler", "render")
+	var component templ.Component
 
-				return
-			}
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
 
-			return
-		}
+		component = ArticleDisplay(dc, related, authenticated)
+	} else {
+		component = ArticlePage(*article, dc, related, authenticated)
 	}
 
-	HandleError(w, r, apperrors.NotFound(nil), "GetArticleHandler", "findArticle")
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "GetArticleHandler", "render")
+	}
 }
 
 func FormatDateForFilename(dateStr string) string {
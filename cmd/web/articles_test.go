@@ -9,12 +9,13 @@ import (
 	"timterests/cmd/web"
 	"timterests/internal/auth"
 	"timterests/internal/service"
+	"timterests/internal/storage"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
 func TestArticleListRendering(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("render article list page", func(t *testing.T) {
 		t.Parallel()
@@ -22,7 +23,7 @@ func TestArticleListRendering(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles", nil)
 		rec := httptest.NewRecorder()
 
-		web.ArticlesPageHandler(rec, req, *s, "all", "list")
+		web.ArticlesPageHandler(rec, req, s, "all", "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -51,7 +52,7 @@ func TestArticleListRendering(t *testing.T) {
 		// Set the HX-Request header to trigger partial rendering
 		req.Header.Set("Hx-Request", "true")
 
-		web.ArticlesPageHandler(rec, req, *s, "all", "list")
+		web.ArticlesPageHandler(rec, req, s, "all", "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -77,7 +78,7 @@ func TestArticleListRendering(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		tag := "tag1"
-		web.ArticlesPageHandler(rec, req, *s, tag, "list")
+		web.ArticlesPageHandler(rec, req, s, tag, "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -105,7 +106,7 @@ func TestArticleListRendering(t *testing.T) {
 
 		// Enter a non-existent tag to get zero results back (filter all articles).
 		tag := "non-existent-tag"
-		web.ArticlesPageHandler(rec, req, *s, tag, "list")
+		web.ArticlesPageHandler(rec, req, s, tag, "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -123,16 +124,16 @@ func TestArticleListRendering(t *testing.T) {
 }
 
 func TestArticleRendering(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	// Create auth instance for tests (won't be authenticated but prevents nil pointer)
 	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
 	t.Run("render article page", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/article?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/test-article", nil)
 		rec := httptest.NewRecorder()
 
-		web.GetArticleHandler(rec, req, *s, "0", a)
+		web.GetArticleHandler(rec, req, s, "test-article", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -170,13 +171,13 @@ func TestArticleRendering(t *testing.T) {
 	})
 
 	t.Run("render article display only", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/article?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/test-article", nil)
 		rec := httptest.NewRecorder()
 
 		// Set HTMX header for partial rendering
 		req.Header.Set("Hx-Request", "true")
 
-		web.GetArticleHandler(rec, req, *s, "0", a)
+		web.GetArticleHandler(rec, req, s, "test-article", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -214,15 +215,71 @@ func TestArticleRendering(t *testing.T) {
 	})
 }
 
+func TestArticleTableOfContents(t *testing.T) {
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+	body := "# Long Read\n\n## One\n\ntext\n\n## Two\n\ntext\n\n### Two A\n\ntext\n"
+
+	render := func(t *testing.T, meta string) *goquery.Document {
+		t.Helper()
+
+		s := testSetup(t)
+
+		err := storage.WriteDocument(context.Background(), s, "articles/long-read.yaml", []byte(meta), []byte(body))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/long-read", nil)
+		rec := httptest.NewRecorder()
+
+		web.GetArticleHandler(rec, req, s, "long-read", a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		return doc
+	}
+
+	t.Run("lists the sections of a long article", func(t *testing.T) {
+		doc := render(t, "title: Long Read\ndate: 2026-01-01\n")
+
+		var hrefs []string
+
+		doc.Find(".toc-sidebar a").Each(func(_ int, link *goquery.Selection) {
+			href, _ := link.Attr("href")
+			hrefs = append(hrefs, href)
+		})
+
+		want := []string{"#one", "#two", "#two-a"}
+		if strings.Join(hrefs, " ") != strings.Join(want, " ") {
+			t.Errorf("expected TOC links %v, got %v", want, hrefs)
+		}
+
+		if doc.Find(`h2#one a.heading-anchor[href="#one"]`).Length() != 1 {
+			t.Error("expected the heading to carry an ID and a permalink anchor")
+		}
+	})
+
+	t.Run("toc false hides it", func(t *testing.T) {
+		doc := render(t, "title: Long Read\ndate: 2026-01-01\ntoc: false\n")
+
+		if doc.Find(".toc-sidebar").Length() != 0 {
+			t.Error("expected no table of contents")
+		}
+	})
+}
+
 func TestGetArticleNotFound(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
-	t.Run("returns 404 for non-existent article ID", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/article?id=non-existent-id", nil)
+	t.Run("returns 404 for non-existent article slug", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/does-not-exist", nil)
 		rec := httptest.NewRecorder()
 
-		web.GetArticleHandler(rec, req, *s, "non-existent-id", a)
+		web.GetArticleHandler(rec, req, s, "does-not-exist", a)
 
 		if rec.Code != http.StatusNotFound {
 			t.Errorf("expected status 404, got %d", rec.Code)
@@ -256,7 +313,7 @@ func TestFormatDateForFilename(t *testing.T) {
 }
 
 func TestArticlesGridDesign(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("render articles with grid design", func(t *testing.T) {
 		t.Parallel()
@@ -266,7 +323,7 @@ func TestArticlesGridDesign(t *testing.T) {
 		)
 		rec := httptest.NewRecorder()
 
-		web.ArticlesPageHandler(rec, req, *s, "all", "grid")
+		web.ArticlesPageHandler(rec, req, s, "all", "grid")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected status 200, got %d", rec.Code)
@@ -290,7 +347,7 @@ func TestArticlesGridDesign(t *testing.T) {
 		)
 		rec := httptest.NewRecorder()
 
-		web.ArticlesPageHandler(rec, req, *s, "all", "links")
+		web.ArticlesPageHandler(rec, req, s, "all", "links")
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected status 200, got %d", rec.Code)
@@ -309,12 +366,12 @@ func TestArticlesGridDesign(t *testing.T) {
 
 func TestArticleCardConversion(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("article to card conversion", func(t *testing.T) {
 		testArticlePath := "articles/test-article.yaml"
 
-		article, err := service.GetArticle(ctx, *s, testArticlePath, 1)
+		article, err := service.GetArticle(ctx, s, testArticlePath)
 		if err != nil {
 			t.Fatalf("failed to get article: %v", err)
 		}
@@ -329,8 +386,196 @@ func TestArticleCardConversion(t *testing.T) {
 			t.Errorf("expected card subtitle %q, got %q", article.Subtitle, card.Subtitle)
 		}
 
-		if card.Get != "/article?id=1" {
-			t.Errorf("expected card get URL '/article?id=1', got %q", card.Get)
+		if card.Get != "/articles/test-article" {
+			t.Errorf("expected card get URL '/articles/test-article', got %q", card.Get)
+		}
+	})
+}
+
+func TestArticleSeries(t *testing.T) {
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+	s := testSetup(t)
+
+	for _, part := range []struct{ slug, meta string }{
+		{"go-tips-1", "title: First Tip\ndate: 2026-01-01\nseries: Go Tips\nseriesOrder: 1\n"},
+		{"go-tips-2", "title: Second Tip\ndate: 2026-01-08\nseries: Go Tips\nseriesOrder: 2\n"},
+		{"go-tips-3", "title: Third Tip\ndate: 2026-01-15\nseries: Go Tips\nseriesOrder: 3\n"},
+	} {
+		err := storage.WriteDocument(context.Background(), s, "articles/"+part.slug+".yaml", []byte(part.meta), []byte("Body"))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+	}
+
+	t.Run("shows the part and links either side", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/go-tips-2", nil)
+		rec := httptest.NewRecorder()
+
+		web.GetArticleHandler(rec, req, s, "go-tips-2", a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		position := strings.Join(strings.Fields(doc.Find(".series-position").Text()), " ")
+		if position != "Part 2 of 3 in Go Tips" {
+			t.Errorf("expected 'Part 2 of 3 in Go Tips', got %q", position)
+		}
+
+		if href := doc.Find(".series-position a").AttrOr("href", ""); href != "/series/go-tips" {
+			t.Errorf("expected a link to /series/go-tips, got %q", href)
+		}
+
+		if href := doc.Find("a.series-prev").AttrOr("href", ""); href != "/articles/go-tips-1" {
+			t.Errorf("expected previous link to /articles/go-tips-1, got %q", href)
+		}
+
+		if href := doc.Find("a.series-next").AttrOr("href", ""); href != "/articles/go-tips-3" {
+			t.Errorf("expected next link to /articles/go-tips-3, got %q", href)
+		}
+	})
+
+	t.Run("the landing page lists the parts in order", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/series/go-tips", nil)
+		rec := httptest.NewRecorder()
+
+		web.SeriesPageHandler(rec, req, s, "go-tips")
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		var hrefs []string
+
+		doc.Find(".series-parts > li .card-container").Each(func(_ int, card *goquery.Selection) {
+			hrefs = append(hrefs, card.AttrOr("hx-get", ""))
+		})
+
+		want := "/articles/go-tips-1 /articles/go-tips-2 /articles/go-tips-3"
+		if strings.Join(hrefs, " ") != want {
+			t.Errorf("expected parts %s, got %v", want, hrefs)
+		}
+	})
+
+	t.Run("an unknown series is not found", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/series/nope", nil)
+		rec := httptest.NewRecorder()
+
+		web.SeriesPageHandler(rec, req, s, "nope")
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestArticleRelatedDocuments(t *testing.T) {
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+	s := testSetup(t)
+
+	meta := "title: Picked\ndate: 2026-01-01\nrelated:\n  - projects/timterests\n  - reading-list/test-book\n"
+
+	err := storage.WriteDocument(context.Background(), s, "articles/picked.yaml", []byte(meta), []byte("Body"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/picked", nil)
+	rec := httptest.NewRecorder()
+
+	web.GetArticleHandler(rec, req, s, "picked", a)
+
+	doc, err := goquery.NewDocumentFromReader(rec.Body)
+	if err != nil {
+		t.Fatalf("failed to read template: %v", err)
+	}
+
+	var links []string
+
+	doc.Find(".related-documents .mini-card-container").Each(func(_ int, card *goquery.Selection) {
+		links = append(links, card.AttrOr("hx-get", ""))
+	})
+
+	want := "/projects/timterests /books/test-book"
+	if strings.Join(links, " ") != want {
+		t.Errorf("expected related cards %s, got %v", want, links)
+	}
+}
+
+func TestArticleWikiLinks(t *testing.T) {
+	s := testSetup(t)
+	a, addAuthCookie := testAuthentication(t)
+
+	seed := map[string][2]string{
+		"articles/linker.yaml": {"title: Linker\ndate: 2026-01-01\n", "See [[test-article]] and [[articles/gone|Gone]]."},
+		"articles/other.yaml":  {"title: Other\ndate: 2026-01-02\n", "Also [[articles/test-article|the test]]."},
+	}
+
+	for key, doc := range seed {
+		err := storage.WriteDocument(context.Background(), s, key, []byte(doc[0]), []byte(doc[1]))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+	}
+
+	render := func(t *testing.T, slug string, admin bool) *goquery.Document {
+		t.Helper()
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/"+slug, nil)
+		if admin {
+			addAuthCookie(req)
+		}
+
+		rec := httptest.NewRecorder()
+
+		web.GetArticleHandler(rec, req, s, slug, a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		return doc
+	}
+
+	t.Run("resolves links and marks broken ones for admins only", func(t *testing.T) {
+		doc := render(t, "linker", false)
+
+		if href := doc.Find("a.wiki-link").AttrOr("href", ""); href != "/articles/test-article" {
+			t.Errorf("expected a link to /articles/test-article, got %q", href)
+		}
+
+		if doc.Find(".wiki-link-broken").Text() != "Gone" {
+			t.Error("expected the broken link to keep its label")
+		}
+
+		if doc.Find(".admin-view .wiki-link-broken").Length() != 0 {
+			t.Error("did not expect broken links to be highlighted for the public")
+		}
+
+		if render(t, "linker", true).Find(".admin-view .wiki-link-broken").Length() != 1 {
+			t.Error("expected broken links to be highlighted for admins")
+		}
+	})
+
+	t.Run("lists the documents that link here", func(t *testing.T) {
+		doc := render(t, "test-article", false)
+
+		var links []string
+
+		doc.Find(".backlinks a").Each(func(_ int, link *goquery.Selection) {
+			links = append(links, link.Text()+" "+link.AttrOr("href", ""))
+		})
+
+		want := "Linker /articles/linker,Other /articles/other"
+		if strings.Join(links, ",") != want {
+			t.Errorf("expected backlinks %s, got %v", want, links)
 		}
 	})
 }
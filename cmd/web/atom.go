@@ -0,0 +1,114 @@
+package web
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	XMLNS    string      `xml:"xmlns,attr"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Author   atomPerson  `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+// writeAtom encodes f as an Atom 1.0 feed. Atom requires every entry to say
+// when it was updated, so an entry with no date takes the feed's.
+func writeAtom(w http.ResponseWriter, f feed) {
+	updated := atomTime(f.Updated)
+
+	entries := make([]atomEntry, 0, len(f.Entries))
+
+	for _, e := range f.Entries {
+		entry := atomEntry{
+			Title:   e.Title,
+			ID:      e.URL,
+			Links:   []atomLink{{Rel: "alternate", Type: "text/html", Href: e.URL}},
+			Updated: updated,
+		}
+
+		if !e.Published.IsZero() {
+			entry.Published = atomTime(e.Published)
+			entry.Updated = entry.Published
+		}
+
+		if e.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Body: e.Summary}
+		}
+
+		if e.Content != "" {
+			entry.Content = &atomText{Type: "html", Body: e.Content}
+		}
+
+		for _, tag := range e.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+
+		entries = append(entries, entry)
+	}
+
+	atom := atomFeed{
+		XMLNS:    atomNamespace,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       f.FeedURLs[FeedAtom],
+		Updated:  updated,
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: f.FeedURLs[FeedAtom]},
+			{Rel: "alternate", Type: "text/html", Href: f.HomeURL},
+		},
+		Author:  atomPerson{Name: Site().AuthorName},
+		Entries: entries,
+	}
+
+	writeXMLFeed(w, "application/atom+xml; charset=utf-8", atom)
+}
+
+// atomTime formats t as RFC 3339, as Atom dates are. A feed with nothing dated
+// in it is dated at the epoch rather than now, so it does not look changed on
+// every fetch.
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -35,7 +35,7 @@ func TestLargeCard(t *testing.T) {
 			Date:      "2026-01-15",
 			Preview:   "Some preview text.",
 			ImagePath: "/images/test.png",
-			Get:       "/article?id=1",
+			Get:       "/articles/test-article",
 			Tags:      []string{"go", "testing"},
 		}
 
@@ -47,7 +47,7 @@ func TestLargeCard(t *testing.T) {
 			"2026-01-15",
 			"Some preview text.",
 			"/images/test.png",
-			"/article?id=1",
+			"/articles/test-article",
 			"go",
 			"testing",
 			"card-container",
@@ -0,0 +1,275 @@
+package web
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "timterests/internal/errors"
+
+	"timterests/cmd/web/components"
+	"timterests/internal/auth"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+
+	"github.com/a-h/templ"
+)
+
+// customTypes are the schema-declared document types the site serves, set once
+// the server has built its routes.
+var customTypes struct {
+	sync.RWMutex
+
+	schemas []model.DocumentSchema
+}
+
+// SetCustomTypes makes schemas the custom document types the site serves, and
+// lets wiki links point at them.
+func SetCustomTypes(schemas []model.DocumentSchema) {
+	customTypes.Lock()
+	defer customTypes.Unlock()
+
+	customTypes.schemas = slices.Clone(schemas)
+
+	names := make([]string, 0, len(schemas))
+	for _, schema := range schemas {
+		names = append(names, schema.Name)
+	}
+
+	storage.SetWikiLinkTypes(names)
+}
+
+// CustomTypes returns the custom document types the site serves.
+func CustomTypes() []model.DocumentSchema {
+	customTypes.RLock()
+	defer customTypes.RUnlock()
+
+	return slices.Clone(customTypes.schemas)
+}
+
+// customType returns the schema of the custom type called name.
+func customType(name string) (*model.DocumentSchema, bool) {
+	schemas := CustomTypes()
+
+	for i := range schemas {
+		if schemas[i].Name == name {
+			return &schemas[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// CustomCard converts a custom document to a Card, drawing each part from the
+// field the schema's card mapping names.
+func CustomCard(doc model.CustomDocument) components.Card {
+	mapping := doc.Schema.Card
+
+	field := func(name, fallback string) string {
+		if name == "" {
+			name = fallback
+		}
+
+		if name == "" {
+			return ""
+		}
+
+		return doc.Value(name)
+	}
+
+	return components.Card{
+		Title:     field(mapping.Title, "title"),
+		Subtitle:  field(mapping.Subtitle, "subtitle"),
+		Date:      displayDate(model.Date(field(mapping.Date, ""))),
+		Preview:   field(mapping.Preview, "preview"),
+		ImagePath: field(mapping.Image, ""),
+		Get:       DocumentURL(doc.Schema.Name, doc.ID),
+		Tags:      doc.Tags,
+	}
+}
+
+// CustomListPageHandler renders the list page of a custom type.
+func CustomListPageHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	s storage.Backend,
+	schema *model.DocumentSchema,
+	currentTag, design string,
+) {
+	var (
+		component templ.Component
+		tags      []string
+	)
+
+	docs, err := service.ListCustom(r.Context(), s, schema, currentTag)
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "CustomListPageHandler", "listCustom")
+
+		return
+	}
+
+	docs = service.Published(docs, time.Now())
+
+	for i := range docs {
+		v := reflect.ValueOf(docs[i])
+		tags = storage.GetTags(v, tags)
+	}
+
+	if design == "" {
+		design = schema.List.Design
+	}
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
+
+		component = CustomList(docs, design)
+	} else {
+		component = CustomListPage(schema, docs, tags, design)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "CustomListPageHandler", "render")
+	}
+}
+
+// GetCustomHandler renders one document of a custom type.
+func GetCustomHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	s storage.Backend,
+	schema *model.DocumentSchema,
+	slug string,
+	a *auth.Auth,
+) {
+	doc, err := service.GetCustomBySlug(r.Context(), s, schema, slug)
+	if err != nil {
+		HandleError(w, r, lookupError(err), "GetCustomHandler", "getDocument")
+
+		return
+	}
+
+	authenticated := a.IsAuthenticated(r)
+
+	// Drafts and scheduled documents are only reachable by the author.
+	if !doc.IsPublished(time.Now()) && !authenticated {
+		HandleError(w, r, apperrors.NotFound(nil), "GetCustomHandler", "checkPublished")
+
+		return
+	}
+
+	body, headings, err := storage.GetDocumentBodyWithHeadings(r.Context(), s, doc.S3Key)
+	if err != nil {
+		HandleError(w, r, apperrors.NotFound(err), "GetCustomHandler", "getBody")
+
+		return
+	}
+
+	dc := model.DisplayContent{
+		ID:        doc.ID,
+		S3Key:     doc.S3Key,
+		Body:      body,
+		TOC:       doc.TableOfContents(headings),
+		Backlinks: backlinks(r.Context(), s, schema.Name, doc.ID),
+	}
+
+	var component templ.Component
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
+
+		component = CustomDisplay(*doc, dc, authenticated)
+	} else {
+		component = CustomPage(*doc, dc, authenticated)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "GetCustomHandler", "render")
+	}
+}
+
+// detailFields returns the fields a custom document's page shows above its
+// body, with their labels, leaving out any without a value.
+func detailFields(doc model.CustomDocument) [][2]string {
+	var fields [][2]string
+
+	for _, name := range doc.Schema.Detail.Fields {
+		value := doc.Value(name)
+		if value == "" {
+			continue
+		}
+
+		label := name
+
+		declared, ok := doc.Schema.Field(name)
+		if ok {
+			label = declared.DisplayLabel()
+		}
+
+		if declared.Type == model.FieldDate {
+			value = displayDate(model.Date(value))
+		}
+
+		fields = append(fields, [2]string{label, value})
+	}
+
+	return fields
+}
+
+// coerceCustomFields converts the writer's string form values for the fields of
+// a custom type into the YAML types the schema declares, so what is stored
+// reads back the same as an uploaded document would.
+func coerceCustomFields(formData map[string]any, schema *model.DocumentSchema) {
+	for _, f := range schema.Fields {
+		value, ok := formData[f.Name].(string)
+		if !ok {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			delete(formData, f.Name)
+
+			continue
+		}
+
+		switch f.Type {
+		case model.FieldList:
+			var items []string
+
+			for item := range strings.SplitSeq(value, ",") {
+				item = strings.TrimSpace(item)
+				if item != "" {
+					items = append(items, item)
+				}
+			}
+
+			formData[f.Name] = items
+		case model.FieldNumber:
+			number, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				formData[f.Name] = number
+			}
+		case model.FieldBool:
+			b, err := strconv.ParseBool(value)
+			if err == nil {
+				formData[f.Name] = b
+			}
+		}
+	}
+
+	// A checkbox sends nothing when unticked, so an unticked bool is false
+	// rather than missing.
+	for _, f := range schema.Fields {
+		_, present := formData[f.Name]
+		if f.Type == model.FieldBool && !present {
+			formData[f.Name] = false
+		}
+	}
+}
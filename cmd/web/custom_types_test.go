@@ -0,0 +1,142 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+	"timterests/internal/model"
+	"timterests/internal/storage"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestCustomTypeRendering(t *testing.T) {
+	s := testSetup(t)
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+	schemas, err := model.ParseSchemas([]byte(`
+types:
+  - name: recipes
+    label: Recipes
+    fields:
+      - {name: servings, label: Serves, type: number}
+      - {name: cuisine, type: string}
+    detail:
+      fields: [servings, cuisine]
+    card:
+      subtitle: cuisine
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	schema := &schemas[0]
+
+	seed := map[string]string{
+		"pancakes": "title: Pancakes\nservings: 4\ncuisine: French\ntags: [breakfast]\n",
+		"secret":   "title: Secret\nstatus: draft\n",
+	}
+	for slug, meta := range seed {
+		err = storage.WriteDocument(context.Background(), s, "recipes/"+slug+".yaml", []byte(meta), []byte("Body\n"))
+		if err != nil {
+			t.Fatalf("failed to seed %s: %v", slug, err)
+		}
+	}
+
+	t.Run("lists published documents as cards", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/recipes", nil)
+		rec := httptest.NewRecorder()
+
+		web.CustomListPageHandler(rec, req, s, schema, "all", "large")
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		if got := doc.Find("h1.category-title").Text(); got != "Recipes" {
+			t.Errorf("expected the type's label as the heading, got %q", got)
+		}
+
+		cards := doc.Find(".card-container")
+		if cards.Length() != 1 {
+			t.Fatalf("expected 1 published card, got %d", cards.Length())
+		}
+
+		if got := cards.AttrOr("hx-get", ""); got != "/recipes/pancakes" {
+			t.Errorf("expected the card to link to /recipes/pancakes, got %q", got)
+		}
+
+		if cards.Find(".card-subtitle").Text() != "French" {
+			t.Errorf("expected the mapped subtitle, got %q", cards.Find(".card-subtitle").Text())
+		}
+	})
+
+	t.Run("shows the detail fields", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/recipes/pancakes", nil)
+		rec := httptest.NewRecorder()
+
+		web.GetCustomHandler(rec, req, s, schema, "pancakes", a)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		labels := doc.Find("dl.detail-fields dt")
+		if labels.Length() != 2 || labels.First().Text() != "Serves" {
+			t.Errorf("expected Serves then cuisine, got %q", labels.Text())
+		}
+
+		if got := doc.Find("dl.detail-fields dd").First().Text(); got != "4" {
+			t.Errorf("expected 4 servings, got %q", got)
+		}
+	})
+
+	t.Run("hides drafts from visitors", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/recipes/secret", nil)
+		rec := httptest.NewRecorder()
+
+		web.GetCustomHandler(rec, req, s, schema, "secret", a)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rec.Code)
+		}
+	})
+}
+
+// A related document of a custom type gets its own card, and one of a type the
+// site does not serve gets none rather than being taken for an article.
+func TestDocumentCardOfCustomType(t *testing.T) {
+	s := testSetup(t)
+
+	schemas, err := model.ParseSchemas([]byte("types:\n  - name: recipes\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	web.SetCustomTypes(schemas)
+	t.Cleanup(func() { web.SetCustomTypes(nil) })
+
+	err = storage.WriteDocument(context.Background(), s, "recipes/pancakes.yaml", []byte("title: Pancakes\n"), []byte("Body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	card, err := web.DocumentCard(context.Background(), s, "recipes", "pancakes")
+	if err != nil || card.Get != "/recipes/pancakes" || card.Title != "Pancakes" {
+		t.Errorf("expected the recipe's card, got %+v (%v)", card, err)
+	}
+
+	_, err = web.DocumentCard(context.Background(), s, "nothing-here", "test-article")
+	if err == nil {
+		t.Error("expected no card for a type the site does not serve")
+	}
+}
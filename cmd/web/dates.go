@@ -0,0 +1,45 @@
+package web
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"timterests/internal/model"
+)
+
+// displayLocation is the time zone timestamps are shown in, named by
+// DISPLAY_TIMEZONE, or the server's own zone when it is unset or unknown.
+var displayLocation = sync.OnceValue(func() *time.Location {
+	name := os.Getenv("DISPLAY_TIMEZONE")
+	if name == "" {
+		return time.Local
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("ignoring DISPLAY_TIMEZONE %q: %v", name, err)
+
+		return time.Local
+	}
+
+	return loc
+})
+
+// dateLayout is the Go layout dates are shown with, set by DATE_FORMAT, e.g.
+// "Jan 2, 2006". Dates are always stored as 2006-01-02 whatever it is.
+func dateLayout() string {
+	return envOr("DATE_FORMAT", model.DateLayout)
+}
+
+// displayDate formats a document date for display. One known only to the
+// month or year is shown as such.
+func displayDate(d model.Date) string {
+	return d.Format(dateLayout())
+}
+
+// displayTime formats a timestamp in the display time zone.
+func displayTime(t time.Time, layout string) string {
+	return t.In(displayLocation()).Format(layout)
+}
@@ -0,0 +1,21 @@
+package web_test
+
+import (
+	"testing"
+	"timterests/cmd/web"
+	"timterests/internal/model"
+)
+
+func TestDisplayDateFormat(t *testing.T) {
+	t.Setenv("DATE_FORMAT", "January 2, 2006")
+
+	card := web.ArticleCard(model.Article{Date: "2026-03-05"})
+	if card.Date != "March 5, 2026" {
+		t.Errorf("expected the configured format, got %q", card.Date)
+	}
+
+	project := web.ProjectCard(model.Project{StartDate: "2023-01", EndDate: "2024"})
+	if project.Date != "Jan 2023 — 2024" {
+		t.Errorf("expected partial dates shown as such, got %q", project.Date)
+	}
+}
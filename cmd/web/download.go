@@ -3,10 +3,12 @@ package web
 import (
 	"errors"
 	"html/template"
+	"io"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
 
 	"timterests/internal/auth"
@@ -14,7 +16,7 @@ import (
 	"timterests/internal/storage"
 )
 
-func DownloadDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, key string, a *auth.Auth) {
+func DownloadDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, key string, a *auth.Auth) {
 	if !a.IsAuthenticated(r) {
 		HandleError(w, r, apperrors.Unauthorized(nil), "DownloadDocumentHandler", "auth")
 
@@ -33,28 +35,29 @@ func DownloadDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.S
 		key = base + ".md"
 	}
 
-	localPath, err := storage.LocalPath(s.BaseDir, key)
+	file, err := s.GetFile(r.Context(), key)
 	if err != nil {
-		HandleError(w, r, apperrors.BadRequest(err), "DownloadDocumentHandler", "localPath")
-
-		return
-	}
-
-	if s.UseS3 {
-		err := s.DownloadS3File(r.Context(), key)
-		if err != nil {
-			HandleError(w, r, apperrors.StorageFailed(err), "DownloadDocumentHandler", "downloadS3")
+		if errors.Is(err, fs.ErrNotExist) {
+			HandleError(w, r, apperrors.NotFound(err), "DownloadDocumentHandler", "getFile")
 
 			return
 		}
+
+		HandleError(w, r, apperrors.StorageFailed(err), "DownloadDocumentHandler", "getFile")
+
+		return
 	}
+	defer file.Close()
 
-	fileName := filepath.Base(key)
+	fileName := path.Base(key)
 
 	w.Header().Set("Content-Disposition", "attachment; filename=\""+fileName+"\"")
 	w.Header().Set("Content-Type", "text/markdown")
 
-	http.ServeFile(w, r, localPath)
+	_, err = io.Copy(w, file)
+	if err != nil {
+		log.Printf("DownloadDocumentHandler: failed to write %s: %v", key, err)
+	}
 }
 
 func DownloadNewDocumentHandler(w http.ResponseWriter, r *http.Request, a *auth.Auth) {
@@ -149,7 +149,7 @@ func TestDownloadDocumentHandler(t *testing.T) {
 			t.Fatalf("failed to write md file: %v", err)
 		}
 
-		s := storage.Storage{UseS3: false, BaseDir: dir}
+		s := storage.NewLocalBackend(dir)
 
 		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/download?key=articles/test.yaml", nil)
 		addAuthCookie(req)
@@ -172,7 +172,7 @@ func TestDownloadDocumentHandler(t *testing.T) {
 	})
 
 	t.Run("returns 400 for missing key", func(t *testing.T) {
-		s := storage.Storage{UseS3: false, BaseDir: t.TempDir()}
+		s := storage.NewLocalBackend(t.TempDir())
 
 		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/download", nil)
 		addAuthCookie(req)
@@ -187,7 +187,7 @@ func TestDownloadDocumentHandler(t *testing.T) {
 	})
 
 	t.Run("returns 401 for unauthenticated request", func(t *testing.T) {
-		s := storage.Storage{UseS3: false, BaseDir: t.TempDir()}
+		s := storage.NewLocalBackend(t.TempDir())
 
 		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/download?key=articles/test.yaml", nil)
 		rec := httptest.NewRecorder()
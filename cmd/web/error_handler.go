@@ -21,3 +21,40 @@ func HandleError(w http.ResponseWriter, r *http.Request, err error, handler, act
 		http.Error(w, appErr.Message, appErr.HTTPStatus)
 	}
 }
+
+// APIError is the body of an error response from the JSON API.
+type APIError struct {
+	Error APIErrorBody `json:"error"`
+}
+
+// APIErrorBody describes what went wrong. Detail explains a bad request, such
+// as which parameter was invalid; other errors leave it out so nothing internal
+// leaks.
+type APIErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// HandleAPIError is HandleError for the JSON API: the error is logged the same
+// way, and returned as an APIError rather than an error page.
+func HandleAPIError(w http.ResponseWriter, _ *http.Request, err error, handler, action string) {
+	appErr := apperrors.Classify(err)
+	appErr = appErr.WithHandler(handler, action)
+	apperrors.LogError(appErr)
+
+	body := APIErrorBody{Code: appErr.Code, Message: appErr.Message}
+	if (appErr.HTTPStatus == http.StatusBadRequest || appErr.HTTPStatus == http.StatusConflict) && appErr.Err != nil {
+		body.Detail = appErr.Err.Error()
+	}
+
+	// A 401 says how to authenticate, as RFC 6750 asks.
+	if appErr.HTTPStatus == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	}
+
+	renderErr := renderJSON(w, appErr.HTTPStatus, APIError{Error: body})
+	if renderErr != nil {
+		http.Error(w, appErr.Message, appErr.HTTPStatus)
+	}
+}
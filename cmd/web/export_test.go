@@ -0,0 +1,12 @@
+package web
+
+import (
+	"context"
+
+	"timterests/cmd/web/components"
+	"timterests/internal/storage"
+)
+
+func DocumentCard(ctx context.Context, s storage.Backend, docType, slug string) (components.Card, error) {
+	return documentCard(ctx, s, docType, slug)
+}
@@ -0,0 +1,404 @@
+package web
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// Feed formats, each served under its own file name.
+const (
+	FeedRSS  = "rss"
+	FeedAtom = "atom"
+	FeedJSON = "json"
+)
+
+// Feed content modes. A summary feed carries each document's subtitle or
+// preview; a full one carries the rendered body as well.
+const (
+	feedContentSummary = "summary"
+	feedContentFull    = "full"
+)
+
+// feedMaxEntries caps how many of the newest documents a feed lists, so a full
+// content feed stays a sensible size.
+const feedMaxEntries = 50
+
+// feedTypes are the document types with feeds of their own, and the ones a tag
+// feed draws from. Letters are private and never appear in a feed.
+var feedTypes = []string{"articles", "projects", "reading-list"}
+
+// feedTitles names each type's feed after its page.
+var feedTitles = map[string]string{
+	"articles":     "Articles",
+	"projects":     "Projects",
+	"reading-list": "Reading List",
+}
+
+// relativeLinkPattern matches root-relative src and href attributes, which a
+// feed reader would resolve against its own host rather than the site.
+var relativeLinkPattern = regexp.MustCompile(`(src|href)="/([^/"])`)
+
+// feed is a feed before it is encoded in one of the formats.
+type feed struct {
+	Title       string
+	Description string
+	// HomeURL is the page the feed follows; FeedURLs are the feed itself in
+	// each format, all absolute.
+	HomeURL  string
+	FeedURLs map[string]string
+	Updated  time.Time
+	Entries  []feedEntry
+}
+
+// feedEntry is one document in a feed.
+type feedEntry struct {
+	URL       string
+	Title     string
+	Summary   string
+	Content   string // rendered HTML, empty unless the feed carries full content
+	Published time.Time
+	Tags      []string
+
+	key string // the document's metadata key, to render its body from
+}
+
+// FeedLink is a feed advertised to browsers and feed readers with a
+// <link rel="alternate"> in the page head.
+type FeedLink struct {
+	Type  string
+	Title string
+	Href  string
+}
+
+// FeedHandler serves the feed of docType in format. Articles are the site's main
+// feed, at /rss.xml, /atom.xml and /feed.json; the other types are served from
+// under their pages, such as /projects/atom.xml.
+func FeedHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, docType, format string) {
+	entries, err := feedEntries(r.Context(), s, docType, "")
+	if err != nil {
+		log.Printf("FeedHandler: failed to list %s: %v", docType, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+
+		return
+	}
+
+	site := Site()
+
+	title := site.Name
+	if docType != "articles" {
+		title += " — " + feedTitles[docType]
+	}
+
+	f := newFeed(title, site.Description, feedBase(docType), feedBase(docType), entries)
+
+	err = addFeedContent(r, s, f.Entries)
+	if err != nil {
+		log.Printf("FeedHandler: failed to render %s: %v", docType, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+
+		return
+	}
+
+	writeFeed(w, f, format)
+}
+
+// TagFeedHandler serves the feed of everything tagged tag, from
+// /tags/{tag}/feed.xml, atom.xml and feed.json. A tag nothing carries is not
+// found, so a mistyped feed address is not quietly subscribed to.
+func TagFeedHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, tag, format string) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	var entries []feedEntry
+
+	for _, docType := range feedTypes {
+		typeEntries, err := feedEntries(r.Context(), s, docType, tag)
+		if err != nil {
+			log.Printf("TagFeedHandler: failed to list %s tagged %q: %v", docType, tag, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		entries = append(entries, typeEntries...)
+	}
+
+	if len(entries) == 0 {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	site := Site()
+	f := newFeed(site.Name+" — "+tag, "Everything tagged "+tag+" on "+site.Name+".",
+		"/search?q="+url.QueryEscape(tag), TagFeedBase(tag), entries)
+
+	err := addFeedContent(r, s, f.Entries)
+	if err != nil {
+		log.Printf("TagFeedHandler: failed to render entries tagged %q: %v", tag, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+
+		return
+	}
+
+	writeFeed(w, f, format)
+}
+
+// RSSHandler generates and serves an RSS 2.0 feed of articles.
+func RSSHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+	s storage.Backend,
+) {
+	FeedHandler(w, r, s, "articles", FeedRSS)
+}
+
+// TagFeedBase is the path the feeds of tag are served under.
+func TagFeedBase(tag string) string {
+	return "/tags/" + url.PathEscape(tag)
+}
+
+// FeedPath returns the path of the feed under base in format. The site's own
+// feeds, with an empty base, keep the long-standing /rss.xml.
+func FeedPath(base, format string) string {
+	switch format {
+	case FeedAtom:
+		return base + "/atom.xml"
+	case FeedJSON:
+		return base + "/feed.json"
+	default:
+		if base == "" {
+			return "/rss.xml"
+		}
+
+		return base + "/feed.xml"
+	}
+}
+
+// feedBase is the path the feeds of docType are served under.
+func feedBase(docType string) string {
+	if docType == "articles" {
+		return ""
+	}
+
+	return "/" + docType
+}
+
+// FeedLinks are the feeds a page advertises: the feeds of the page's own type
+// first, where it has them, then the site's article feeds.
+func FeedLinks(activePage string) []FeedLink {
+	name := Site().Name
+
+	links := make([]FeedLink, 0, 6)
+
+	if activePage != "articles" && slices.Contains(feedTypes, activePage) {
+		links = append(links, feedLinksFor(feedBase(activePage), name+" "+feedTitles[activePage])...)
+	}
+
+	return append(links, feedLinksFor("", name)...)
+}
+
+func feedLinksFor(base, title string) []FeedLink {
+	return []FeedLink{
+		{Type: "application/rss+xml", Title: title + " RSS Feed", Href: FeedPath(base, FeedRSS)},
+		{Type: "application/atom+xml", Title: title + " Atom Feed", Href: FeedPath(base, FeedAtom)},
+		{Type: "application/feed+json", Title: title + " JSON Feed", Href: FeedPath(base, FeedJSON)},
+	}
+}
+
+// feedFullContent reports whether the feed for r carries full content: as
+// ?content= asks, or else as FEED_CONTENT is set.
+func feedFullContent(r *http.Request) bool {
+	mode := r.URL.Query().Get("content")
+	if mode != feedContentFull && mode != feedContentSummary {
+		mode = envOr("FEED_CONTENT", feedContentSummary)
+	}
+
+	return mode == feedContentFull
+}
+
+// newFeed assembles a feed from its entries, newest first and capped at
+// feedMaxEntries. Paths are made absolute against SITE_URL.
+func newFeed(title, description, homePath, base string, entries []feedEntry) feed {
+	slices.SortStableFunc(entries, func(a, b feedEntry) int {
+		return b.Published.Compare(a.Published)
+	})
+
+	if len(entries) > feedMaxEntries {
+		entries = entries[:feedMaxEntries]
+	}
+
+	f := feed{
+		Title:       title,
+		Description: description,
+		HomeURL:     strings.TrimSuffix(Site().URL, "/") + homePath,
+		FeedURLs:    make(map[string]string, 3),
+		Entries:     entries,
+	}
+
+	for _, format := range []string{FeedRSS, FeedAtom, FeedJSON} {
+		f.FeedURLs[format] = absoluteURL(FeedPath(base, format))
+	}
+
+	for _, e := range entries {
+		if e.Published.After(f.Updated) {
+			f.Updated = e.Published
+		}
+	}
+
+	return f
+}
+
+// writeFeed encodes f in format.
+func writeFeed(w http.ResponseWriter, f feed, format string) {
+	switch format {
+	case FeedAtom:
+		writeAtom(w, f)
+	case FeedJSON:
+		writeJSONFeed(w, f)
+	default:
+		writeRSS(w, f)
+	}
+}
+
+// feedEntries returns the published documents of docType as feed entries,
+// only those tagged tag unless it is empty. Their content is left to
+// addFeedContent, once the feed is cut to the entries it keeps.
+func feedEntries(ctx context.Context, s storage.Backend, docType, tag string) ([]feedEntry, error) {
+	now := time.Now()
+
+	var (
+		docs  []model.Document
+		dates []model.Date
+	)
+
+	switch docType {
+	case "articles":
+		articles, err := service.ListArticles(ctx, s, "all")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range service.Published(articles, now) {
+			docs = append(docs, a.Document)
+			dates = append(dates, a.Date)
+		}
+	case "projects":
+		projects, err := service.ListProjects(ctx, s, "all")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range service.Published(projects, now) {
+			docs = append(docs, p.Document)
+			dates = append(dates, p.StartDate)
+		}
+	case "reading-list":
+		books, err := service.ListBooks(ctx, s, "all")
+		if err != nil {
+			return nil, err
+		}
+
+		// A book joins the list when it is started; one not yet started has
+		// only its publication or the file's time to go on.
+		for _, b := range service.Published(books, now) {
+			docs = append(docs, b.Document)
+			dates = append(dates, cmp.Or(b.Started, b.Finished))
+		}
+	}
+
+	modified, err := lastModified(ctx, s, docType)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]feedEntry, 0, len(docs))
+
+	for i, doc := range docs {
+		if tag != "" && !slices.Contains(doc.Tags, tag) {
+			continue
+		}
+
+		// A single-file document is listed by its .md alone, so its metadata
+		// key is never among the modified times.
+		written := latest(modified[doc.S3Key], modified[storage.BodyKey(doc.S3Key)])
+
+		entries = append(entries, feedEntry{
+			URL:       absoluteURL(DocumentURL(docType, doc.ID)),
+			Title:     doc.Title,
+			Summary:   cmp.Or(doc.Subtitle, doc.Preview),
+			Published: entryTime(doc, dates[i], written),
+			Tags:      doc.Tags,
+			key:       doc.S3Key,
+		})
+	}
+
+	return entries, nil
+}
+
+// addFeedContent renders the body of each entry when the feed for r carries
+// full content. Only the entries the feed keeps are rendered, not the archive.
+func addFeedContent(r *http.Request, s storage.Backend, entries []feedEntry) error {
+	if !feedFullContent(r) {
+		return nil
+	}
+
+	for i := range entries {
+		body, err := storage.GetDocumentBody(r.Context(), s, entries[i].key)
+		if err != nil {
+			return err
+		}
+
+		entries[i].Content = relativeLinkPattern.ReplaceAllString(body, `$1="`+absoluteURL("/")+`$2`)
+	}
+
+	return nil
+}
+
+// entryTime is when a document appeared: its own date if it has one, else when
+// it was scheduled to publish, else when its file was last written.
+func entryTime(doc model.Document, date model.Date, modified time.Time) time.Time {
+	t := date.Time()
+	if !t.IsZero() {
+		return t
+	}
+
+	if doc.PublishAt != "" {
+		at, err := model.ParsePublishAt(doc.PublishAt)
+		if err == nil {
+			return at.UTC()
+		}
+	}
+
+	return modified.UTC()
+}
+
+// lastModified maps the keys under docType to when each was last written.
+func lastModified(ctx context.Context, s storage.Backend, docType string) (map[string]time.Time, error) {
+	objects, err := s.ListObjects(ctx, docType+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	modified := make(map[string]time.Time, len(objects))
+	for _, obj := range objects {
+		modified[obj.Key] = obj.LastModified
+	}
+
+	return modified, nil
+}
@@ -0,0 +1,369 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"timterests/cmd/web"
+	"timterests/internal/storage"
+)
+
+type atomResult struct {
+	Title   string `xml:"title"`
+	ID      string `xml:"id"`
+	Updated string `xml:"updated"`
+	Links   []struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	Entries []struct {
+		Title   string `xml:"title"`
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Content string `xml:"content"`
+	} `xml:"entry"`
+}
+
+type jsonFeedResult struct {
+	Version string `json:"version"`
+	FeedURL string `json:"feed_url"`
+	Items   []struct {
+		URL         string   `json:"url"`
+		ContentHTML string   `json:"content_html"`
+		ContentText string   `json:"content_text"`
+		Tags        []string `json:"tags"`
+	} `json:"items"`
+}
+
+func getFeed(t *testing.T, target string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+
+	handler(rec, req)
+
+	return rec
+}
+
+func TestAtomFeed(t *testing.T) {
+	s := testSetup(t)
+	t.Setenv("SITE_URL", "https://example.com")
+
+	rec := getFeed(t, "/atom.xml", func(w http.ResponseWriter, r *http.Request) {
+		web.FeedHandler(w, r, s, "articles", web.FeedAtom)
+	})
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/atom+xml") {
+		t.Errorf("expected an Atom content type, got %q", ct)
+	}
+
+	var feed atomResult
+
+	err := xml.Unmarshal(rec.Body.Bytes(), &feed)
+	if err != nil {
+		t.Fatalf("failed to parse Atom: %v", err)
+	}
+
+	if feed.ID != "https://example.com/atom.xml" || feed.Updated != "2026-01-01T00:00:00Z" {
+		t.Errorf("unexpected feed: %+v", feed)
+	}
+
+	if len(feed.Entries) != 1 || feed.Entries[0].ID != "https://example.com/articles/test-article" {
+		t.Fatalf("expected the test article, got %+v", feed.Entries)
+	}
+
+	if feed.Entries[0].Content != "" {
+		t.Error("expected no content in a summary feed")
+	}
+
+	_, err = time.Parse(time.RFC3339, feed.Entries[0].Updated)
+	if err != nil {
+		t.Errorf("entry updated %q is not RFC 3339: %v", feed.Entries[0].Updated, err)
+	}
+}
+
+func TestJSONFeed(t *testing.T) {
+	s := testSetup(t)
+	t.Setenv("SITE_URL", "https://example.com")
+
+	handler := func(w http.ResponseWriter, r *http.Request) { web.FeedHandler(w, r, s, "articles", web.FeedJSON) }
+
+	t.Run("carries summaries by default", func(t *testing.T) {
+		rec := getFeed(t, "/feed.json", handler)
+
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/feed+json") {
+			t.Errorf("expected a JSON Feed content type, got %q", ct)
+		}
+
+		var feed jsonFeedResult
+
+		err := json.Unmarshal(rec.Body.Bytes(), &feed)
+		if err != nil {
+			t.Fatalf("failed to parse JSON Feed: %v", err)
+		}
+
+		if feed.Version != "https://jsonfeed.org/version/1.1" || feed.FeedURL != "https://example.com/feed.json" {
+			t.Errorf("unexpected feed: %+v", feed)
+		}
+
+		if len(feed.Items) != 1 || feed.Items[0].ContentText == "" || feed.Items[0].ContentHTML != "" {
+			t.Errorf("expected one summary item, got %+v", feed.Items)
+		}
+	})
+
+	t.Run("carries full content when asked", func(t *testing.T) {
+		var feed jsonFeedResult
+
+		err := json.Unmarshal(getFeed(t, "/feed.json?content=full", handler).Body.Bytes(), &feed)
+		if err != nil {
+			t.Fatalf("failed to parse JSON Feed: %v", err)
+		}
+
+		if len(feed.Items) != 1 || !strings.Contains(feed.Items[0].ContentHTML, "<h1") {
+			t.Errorf("expected the rendered body, got %+v", feed.Items)
+		}
+	})
+
+	t.Run("FEED_CONTENT sets the default", func(t *testing.T) {
+		t.Setenv("FEED_CONTENT", "full")
+
+		var feed jsonFeedResult
+
+		err := json.Unmarshal(getFeed(t, "/feed.json", handler).Body.Bytes(), &feed)
+		if err != nil {
+			t.Fatalf("failed to parse JSON Feed: %v", err)
+		}
+
+		if len(feed.Items) != 1 || feed.Items[0].ContentHTML == "" {
+			t.Errorf("expected full content, got %+v", feed.Items)
+		}
+
+		var summary jsonFeedResult
+
+		err = json.Unmarshal(getFeed(t, "/feed.json?content=summary", handler).Body.Bytes(), &summary)
+		if err != nil {
+			t.Fatalf("failed to parse JSON Feed: %v", err)
+		}
+
+		if len(summary.Items) != 1 || summary.Items[0].ContentHTML != "" {
+			t.Error("expected ?content=summary to override FEED_CONTENT")
+		}
+	})
+}
+
+func TestRSSFullContent(t *testing.T) {
+	s := testSetup(t)
+
+	rec := getFeed(t, "/rss.xml?content=full", func(w http.ResponseWriter, r *http.Request) {
+		web.RSSHandler(w, r, s)
+	})
+
+	var result struct {
+		Channel struct {
+			Items []struct {
+				Content    string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				Categories []string `xml:"category"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+
+	err := xml.Unmarshal(rec.Body.Bytes(), &result)
+	if err != nil {
+		t.Fatalf("failed to parse RSS: %v", err)
+	}
+
+	if len(result.Channel.Items) != 1 || !strings.Contains(result.Channel.Items[0].Content, "<h1") {
+		t.Fatalf("expected the rendered body in content:encoded, got %+v", result.Channel.Items)
+	}
+
+	if len(result.Channel.Items[0].Categories) != 2 {
+		t.Errorf("expected the article's tags as categories, got %v", result.Channel.Items[0].Categories)
+	}
+}
+
+func TestTypeFeeds(t *testing.T) {
+	s := testSetup(t)
+	t.Setenv("SITE_URL", "https://example.com")
+
+	var projects jsonFeedResult
+
+	err := json.Unmarshal(getFeed(t, "/projects/feed.json", func(w http.ResponseWriter, r *http.Request) {
+		web.FeedHandler(w, r, s, "projects", web.FeedJSON)
+	}).Body.Bytes(), &projects)
+	if err != nil {
+		t.Fatalf("failed to parse JSON Feed: %v", err)
+	}
+
+	if len(projects.Items) != 3 || projects.FeedURL != "https://example.com/projects/feed.json" {
+		t.Errorf("expected the three projects, got %+v", projects)
+	}
+
+	var books atomResult
+
+	err = xml.Unmarshal(getFeed(t, "/reading-list/atom.xml", func(w http.ResponseWriter, r *http.Request) {
+		web.FeedHandler(w, r, s, "reading-list", web.FeedAtom)
+	}).Body.Bytes(), &books)
+	if err != nil {
+		t.Fatalf("failed to parse Atom: %v", err)
+	}
+
+	if len(books.Entries) != 2 || !strings.HasPrefix(books.Entries[0].ID, "https://example.com/books/") {
+		t.Errorf("expected both books, got %+v", books.Entries)
+	}
+}
+
+func TestTagFeed(t *testing.T) {
+	s := testSetup(t)
+
+	err := storage.WriteDocument(context.Background(), s, "articles/draft-article.yaml",
+		[]byte("title: Draft Article\ndate: \"2026-02-01\"\nstatus: draft\ntags:\n  - tag1\n"), []byte("Draft body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	tagFeed := func(tag string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) { web.TagFeedHandler(w, r, s, tag, web.FeedJSON) }
+	}
+
+	t.Run("collects every published type", func(t *testing.T) {
+		var feed jsonFeedResult
+
+		err := json.Unmarshal(getFeed(t, "/tags/Testing/feed.json", tagFeed("Testing")).Body.Bytes(), &feed)
+		if err != nil {
+			t.Fatalf("failed to parse JSON Feed: %v", err)
+		}
+
+		if len(feed.Items) != 2 || !strings.HasSuffix(feed.FeedURL, "/tags/Testing/feed.json") {
+			t.Errorf("expected both books tagged Testing, got %+v", feed)
+		}
+	})
+
+	t.Run("leaves out drafts", func(t *testing.T) {
+		var feed jsonFeedResult
+
+		err := json.Unmarshal(getFeed(t, "/tags/tag1/feed.json", tagFeed("tag1")).Body.Bytes(), &feed)
+		if err != nil {
+			t.Fatalf("failed to parse JSON Feed: %v", err)
+		}
+
+		if len(feed.Items) != 1 || !strings.HasSuffix(feed.Items[0].URL, "/articles/test-article") {
+			t.Errorf("expected only the published article, got %+v", feed.Items)
+		}
+	})
+
+	t.Run("an unused tag is not found", func(t *testing.T) {
+		rec := getFeed(t, "/tags/nothing/feed.json", tagFeed("nothing"))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestFeedLinks(t *testing.T) {
+	home := web.FeedLinks("home")
+	if len(home) != 3 || home[0].Href != "/rss.xml" || home[1].Href != "/atom.xml" || home[2].Href != "/feed.json" {
+		t.Errorf("unexpected site feeds: %+v", home)
+	}
+
+	projects := web.FeedLinks("projects")
+	if len(projects) != 6 || projects[0].Href != "/projects/feed.xml" || projects[3].Href != "/rss.xml" {
+		t.Errorf("expected the projects feeds first, got %+v", projects)
+	}
+
+	if got := web.FeedPath(web.TagFeedBase("Data Structures"), web.FeedRSS); got != "/tags/Data%20Structures/feed.xml" {
+		t.Errorf("unexpected tag feed path %q", got)
+	}
+}
+
+// countingBackend counts the bodies read through it.
+type countingBackend struct {
+	storage.Backend
+
+	bodies int
+}
+
+func (c *countingBackend) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	if strings.HasSuffix(key, ".md") {
+		c.bodies++
+	}
+
+	return c.Backend.GetFile(ctx, key)
+}
+
+// A full content feed renders only the entries it keeps, however long the
+// archive behind it.
+func TestFullContentFeedRendersKeptEntries(t *testing.T) {
+	s := &countingBackend{Backend: testSetup(t)}
+
+	for i := range 60 {
+		date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i).Format(time.DateOnly)
+
+		err := storage.WriteDocument(context.Background(), s, fmt.Sprintf("articles/bulk-%02d.yaml", i),
+			[]byte("title: Bulk "+date+"\ndate: \""+date+"\"\n"), []byte("# Body\n"))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) { web.FeedHandler(w, r, s, "articles", web.FeedJSON) }
+
+	s.bodies = 0
+	getFeed(t, "/feed.json?content=summary", handler)
+	listing := s.bodies
+
+	s.bodies = 0
+
+	var feed jsonFeedResult
+
+	err := json.Unmarshal(getFeed(t, "/feed.json?content=full", handler).Body.Bytes(), &feed)
+	if err != nil {
+		t.Fatalf("failed to parse JSON feed: %v", err)
+	}
+
+	if len(feed.Items) != 50 {
+		t.Fatalf("expected the feed cut to 50 entries, got %d", len(feed.Items))
+	}
+
+	if rendered := s.bodies - listing; rendered != 50 {
+		t.Errorf("expected 50 bodies rendered, got %d", rendered)
+	}
+}
+
+// An undated document stored as a single .md file is dated by when that file
+// was written, not left at the zero time.
+func TestFeedDatesSingleFileByItsBody(t *testing.T) {
+	s := testSetup(t)
+
+	err := s.WriteFile(context.Background(), "articles/undated.md", []byte("---\ntitle: Undated\n---\nBody\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	var feed atomResult
+
+	err = xml.Unmarshal(getFeed(t, "/atom.xml", func(w http.ResponseWriter, r *http.Request) {
+		web.FeedHandler(w, r, s, "articles", web.FeedAtom)
+	}).Body.Bytes(), &feed)
+	if err != nil {
+		t.Fatalf("failed to parse Atom: %v", err)
+	}
+
+	for _, entry := range feed.Entries {
+		if entry.Title == "Undated" && strings.HasPrefix(entry.Updated, "0001") {
+			t.Errorf("expected the entry dated by its file, got %s", entry.Updated)
+		}
+	}
+
+	if len(feed.Entries) == 0 || feed.Entries[0].Title != "Undated" {
+		t.Errorf("expected the newest file first, got %+v", feed.Entries)
+	}
+}
@@ -4,23 +4,23 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 	"timterests/internal/auth"
 	"timterests/internal/storage"
 )
 
-func testSetup(t *testing.T, ctx context.Context) *storage.Storage {
+// testSetup returns an in-memory copy of the shared testdata fixtures, so a
+// handler that writes or deletes can never modify the files on disk.
+func testSetup(t *testing.T) storage.Backend {
 	t.Helper()
-	t.Setenv("USE_S3", "false")
 
-	s, err := storage.NewStorage(ctx)
+	s, err := storage.NewMemoryBackendFromFS(os.DirFS(filepath.Join("..", "..", "storage", "testdata")))
 	if err != nil {
 		t.Fatalf("failed to initialize storage: %v", err)
 	}
 
-	s.BaseDir = filepath.Join(s.BaseDir, "testdata")
-
 	return s
 }
 
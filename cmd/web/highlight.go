@@ -0,0 +1,28 @@
+package web
+
+import (
+	"log"
+	"net/http"
+
+	"timterests/internal/storage"
+)
+
+// HighlightCSSHandler serves the stylesheet for syntax-highlighted code blocks.
+// It is generated from the same chroma styles the Markdown renderer uses, so the
+// classes in the page and the rules here cannot drift apart.
+func HighlightCSSHandler(w http.ResponseWriter, _ *http.Request) {
+	css, err := storage.HighlightCSS()
+	if err != nil {
+		log.Printf("HighlightCSSHandler: failed to build stylesheet: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+
+	_, err = w.Write([]byte(css))
+	if err != nil {
+		log.Printf("HighlightCSSHandler: failed to write response: %v", err)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"timterests/internal/storage"
 )
 
-func HomeHandler(w http.ResponseWriter, r *http.Request, s storage.Storage) {
+func HomeHandler(w http.ResponseWriter, r *http.Request, s storage.Backend) {
 	latestArticle, err := service.GetLatestArticle(r.Context(), s)
 	if err != nil {
 		HandleError(w, r, apperrors.StorageFailed(err), "HomeHandler", "getLatestArticle")
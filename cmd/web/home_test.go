@@ -11,13 +11,13 @@ import (
 )
 
 func TestHomeHandler(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("renders home page with latest article and featured project", func(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
 		rec := httptest.NewRecorder()
 
-		web.HomeHandler(rec, req, *s)
+		web.HomeHandler(rec, req, s)
 
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
@@ -151,7 +151,7 @@ func TestSetVaryHeader(t *testing.T) {
 }
 
 func TestFallbackFullPageBehavior(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	a, addAuthCookie := testAuthentication(t)
 
 	routes := []struct {
@@ -163,42 +163,42 @@ func TestFallbackFullPageBehavior(t *testing.T) {
 			name: "articles list",
 			path: "/articles",
 			handler: func(rec *httptest.ResponseRecorder, req *http.Request) {
-				web.ArticlesPageHandler(rec, req, *s, "all", "list")
+				web.ArticlesPageHandler(rec, req, s, "all", "list")
 			},
 		},
 		{
 			name: "article detail",
-			path: "/article?id=0",
+			path: "/articles/test-article",
 			handler: func(rec *httptest.ResponseRecorder, req *http.Request) {
-				web.GetArticleHandler(rec, req, *s, "0", a)
+				web.GetArticleHandler(rec, req, s, "test-article", a)
 			},
 		},
 		{
 			name: "projects list",
 			path: "/projects",
 			handler: func(rec *httptest.ResponseRecorder, req *http.Request) {
-				web.ProjectsPageHandler(rec, req, *s, "all", "list")
+				web.ProjectsPageHandler(rec, req, s, "all", "list")
 			},
 		},
 		{
 			name: "project detail",
-			path: "/project?id=0",
+			path: "/projects/test-project",
 			handler: func(rec *httptest.ResponseRecorder, req *http.Request) {
-				web.GetProjectHandler(rec, req, *s, "0", a)
+				web.GetProjectHandler(rec, req, s, "test-project", a)
 			},
 		},
 		{
 			name: "reading list",
 			path: "/reading-list",
 			handler: func(rec *httptest.ResponseRecorder, req *http.Request) {
-				web.ReadingListPageHandler(rec, req, *s, "all", "list")
+				web.ReadingListPageHandler(rec, req, s, "all", "", "", "list")
 			},
 		},
 		{
 			name: "book detail",
-			path: "/book?id=0",
+			path: "/books/test-book",
 			handler: func(rec *httptest.ResponseRecorder, req *http.Request) {
-				web.GetReadingListBook(rec, req, *s, "0", a)
+				web.GetReadingListBook(rec, req, s, "test-book", a)
 			},
 		},
 	}
@@ -232,7 +232,7 @@ func TestFallbackFullPageBehavior(t *testing.T) {
 
 // TestArticlesBackButtonBehavior tests that back-button navigation returns a full page.
 func TestArticlesBackButtonBehavior(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("forward nav (HTMX swap) - returns partial, sets cache headers", func(t *testing.T) {
 		t.Parallel()
@@ -242,7 +242,7 @@ func TestArticlesBackButtonBehavior(t *testing.T) {
 
 		rec := httptest.NewRecorder()
 
-		web.ArticlesPageHandler(rec, req, *s, "all", "list")
+		web.ArticlesPageHandler(rec, req, s, "all", "list")
 
 		// Partial response should NOT contain full page structure
 		body := rec.Body.String()
@@ -277,7 +277,7 @@ func TestArticlesBackButtonBehavior(t *testing.T) {
 		// No HX-Request header = back button or direct navigation
 		rec := httptest.NewRecorder()
 
-		web.ArticlesPageHandler(rec, req, *s, "all", "list")
+		web.ArticlesPageHandler(rec, req, s, "all", "list")
 
 		// Full page response must include base layout
 		body := rec.Body.String()
@@ -297,18 +297,18 @@ func TestArticlesBackButtonBehavior(t *testing.T) {
 
 // TestArticleDetailBackButtonBehavior tests the article detail route.
 func TestArticleDetailBackButtonBehavior(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
 	t.Run("forward nav (HTMX swap) to article detail - partial + cache headers", func(t *testing.T) {
 		t.Parallel()
 
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/article?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/test-article", nil)
 		req.Header.Set("Hx-Request", "true")
 
 		rec := httptest.NewRecorder()
 
-		web.GetArticleHandler(rec, req, *s, "0", a)
+		web.GetArticleHandler(rec, req, s, "test-article", a)
 
 		body := rec.Body.String()
 		if strings.Contains(body, "<title>") {
@@ -333,11 +333,11 @@ func TestArticleDetailBackButtonBehavior(t *testing.T) {
 	t.Run("back button to article detail - full page returned", func(t *testing.T) {
 		t.Parallel()
 
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/article?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/test-article", nil)
 		// No HX-Request = back button or bookmark
 		rec := httptest.NewRecorder()
 
-		web.GetArticleHandler(rec, req, *s, "0", a)
+		web.GetArticleHandler(rec, req, s, "test-article", a)
 
 		body := rec.Body.String()
 		if !strings.Contains(body, "<title>") {
@@ -352,7 +352,7 @@ func TestArticleDetailBackButtonBehavior(t *testing.T) {
 
 // TestProjectsBackButtonBehavior tests the projects routes for back-button safety.
 func TestProjectsBackButtonBehavior(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
 	t.Run("projects list - HTMX partial has cache headers", func(t *testing.T) {
@@ -363,7 +363,7 @@ func TestProjectsBackButtonBehavior(t *testing.T) {
 
 		rec := httptest.NewRecorder()
 
-		web.ProjectsPageHandler(rec, req, *s, "all", "list")
+		web.ProjectsPageHandler(rec, req, s, "all", "list")
 
 		cacheControl := rec.Header().Get("Cache-Control")
 		if cacheControl == "" {
@@ -386,7 +386,7 @@ func TestProjectsBackButtonBehavior(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/projects", nil)
 		rec := httptest.NewRecorder()
 
-		web.ProjectsPageHandler(rec, req, *s, "all", "list")
+		web.ProjectsPageHandler(rec, req, s, "all", "list")
 
 		body := rec.Body.String()
 		if !strings.Contains(body, "<title>") {
@@ -397,12 +397,12 @@ func TestProjectsBackButtonBehavior(t *testing.T) {
 	t.Run("project detail - HTMX partial has cache headers", func(t *testing.T) {
 		t.Parallel()
 
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/project?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/projects/test-project", nil)
 		req.Header.Set("Hx-Request", "true")
 
 		rec := httptest.NewRecorder()
 
-		web.GetProjectHandler(rec, req, *s, "0", a)
+		web.GetProjectHandler(rec, req, s, "test-project", a)
 
 		cacheControl := rec.Header().Get("Cache-Control")
 		if cacheControl == "" {
@@ -422,10 +422,10 @@ func TestProjectsBackButtonBehavior(t *testing.T) {
 	t.Run("project detail - back button returns full page", func(t *testing.T) {
 		t.Parallel()
 
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/project?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/projects/test-project", nil)
 		rec := httptest.NewRecorder()
 
-		web.GetProjectHandler(rec, req, *s, "0", a)
+		web.GetProjectHandler(rec, req, s, "test-project", a)
 
 		body := rec.Body.String()
 		if !strings.Contains(body, "<title>") {
@@ -436,7 +436,7 @@ func TestProjectsBackButtonBehavior(t *testing.T) {
 
 // TestReadingListBackButtonBehavior tests reading list routes.
 func TestReadingListBackButtonBehavior(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
 	t.Run("reading list - HTMX partial has cache headers", func(t *testing.T) {
@@ -447,7 +447,7 @@ func TestReadingListBackButtonBehavior(t *testing.T) {
 
 		rec := httptest.NewRecorder()
 
-		web.ReadingListPageHandler(rec, req, *s, "all", "list")
+		web.ReadingListPageHandler(rec, req, s, "all", "", "", "list")
 
 		cacheControl := rec.Header().Get("Cache-Control")
 		if cacheControl == "" {
@@ -470,7 +470,7 @@ func TestReadingListBackButtonBehavior(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/reading-list", nil)
 		rec := httptest.NewRecorder()
 
-		web.ReadingListPageHandler(rec, req, *s, "all", "list")
+		web.ReadingListPageHandler(rec, req, s, "all", "", "", "list")
 
 		body := rec.Body.String()
 		if !strings.Contains(body, "<title>") {
@@ -481,12 +481,12 @@ func TestReadingListBackButtonBehavior(t *testing.T) {
 	t.Run("book detail - HTMX partial has cache headers", func(t *testing.T) {
 		t.Parallel()
 
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/book?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/books/test-book", nil)
 		req.Header.Set("Hx-Request", "true")
 
 		rec := httptest.NewRecorder()
 
-		web.GetReadingListBook(rec, req, *s, "0", a)
+		web.GetReadingListBook(rec, req, s, "test-book", a)
 
 		cacheControl := rec.Header().Get("Cache-Control")
 		if cacheControl == "" {
@@ -506,10 +506,10 @@ func TestReadingListBackButtonBehavior(t *testing.T) {
 	t.Run("book detail - back button returns full page", func(t *testing.T) {
 		t.Parallel()
 
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/book?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/books/test-book", nil)
 		rec := httptest.NewRecorder()
 
-		web.GetReadingListBook(rec, req, *s, "0", a)
+		web.GetReadingListBook(rec, req, s, "test-book", a)
 
 		body := rec.Body.String()
 		if !strings.Contains(body, "<title>") {
@@ -520,7 +520,7 @@ func TestReadingListBackButtonBehavior(t *testing.T) {
 
 // TestLettersBackButtonBehavior tests letters routes (requires authentication).
 func TestLettersBackButtonBehavior(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	a, addAuthCookie := testAuthentication(t)
 
 	t.Run("letters list - HTMX partial has cache headers", func(t *testing.T) {
@@ -530,7 +530,7 @@ func TestLettersBackButtonBehavior(t *testing.T) {
 
 		rec := httptest.NewRecorder()
 
-		web.LettersPageHandler(rec, req, *s, "all", "list", a)
+		web.LettersPageHandler(rec, req, s, "all", "list", a)
 
 		cacheControl := rec.Header().Get("Cache-Control")
 		if cacheControl == "" {
@@ -553,7 +553,7 @@ func TestLettersBackButtonBehavior(t *testing.T) {
 
 		rec := httptest.NewRecorder()
 
-		web.LettersPageHandler(rec, req, *s, "all", "list", a)
+		web.LettersPageHandler(rec, req, s, "all", "list", a)
 
 		body := rec.Body.String()
 		if !strings.Contains(body, "<title>") {
@@ -562,13 +562,13 @@ func TestLettersBackButtonBehavior(t *testing.T) {
 	})
 
 	t.Run("letter detail - HTMX partial has cache headers", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letter?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letters/test-letter", nil)
 		addAuthCookie(req)
 		req.Header.Set("Hx-Request", "true")
 
 		rec := httptest.NewRecorder()
 
-		web.GetLetterHandler(rec, req, *s, "0", a)
+		web.GetLetterHandler(rec, req, s, "test-letter", a)
 
 		cacheControl := rec.Header().Get("Cache-Control")
 		if cacheControl == "" {
@@ -586,12 +586,12 @@ func TestLettersBackButtonBehavior(t *testing.T) {
 	})
 
 	t.Run("letter detail - back button returns full page", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letter?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letters/test-letter", nil)
 		addAuthCookie(req)
 
 		rec := httptest.NewRecorder()
 
-		web.GetLetterHandler(rec, req, *s, "0", a)
+		web.GetLetterHandler(rec, req, s, "test-letter", a)
 
 		body := rec.Body.String()
 		if !strings.Contains(body, "<title>") {
@@ -0,0 +1,92 @@
+package web
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url"`
+	FeedURL     string           `json:"feed_url"`
+	Description string           `json:"description,omitempty"`
+	Language    string           `json:"language"`
+	Authors     []jsonFeedAuthor `json:"authors"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	Summary       string   `json:"summary,omitempty"`
+	ContentHTML   string   `json:"content_html,omitempty"`
+	ContentText   string   `json:"content_text,omitempty"`
+	DatePublished string   `json:"date_published,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// writeJSONFeed encodes f as JSON Feed 1.1. Every item needs content, so a
+// summary feed carries the summary as its text.
+func writeJSONFeed(w http.ResponseWriter, f feed) {
+	items := make([]jsonFeedItem, 0, len(f.Entries))
+
+	for _, e := range f.Entries {
+		item := jsonFeedItem{
+			ID:          e.URL,
+			URL:         e.URL,
+			Title:       e.Title,
+			Summary:     e.Summary,
+			ContentHTML: e.Content,
+			Tags:        e.Tags,
+		}
+
+		if item.ContentHTML == "" {
+			item.ContentText = e.Summary
+			if item.ContentText == "" {
+				item.ContentText = e.Title
+			}
+		}
+
+		if !e.Published.IsZero() {
+			item.DatePublished = e.Published.UTC().Format(time.RFC3339)
+		}
+
+		items = append(items, item)
+	}
+
+	doc := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       f.Title,
+		HomePageURL: f.HomeURL,
+		FeedURL:     f.FeedURLs[FeedJSON],
+		Description: f.Description,
+		Language:    "en",
+		Authors:     []jsonFeedAuthor{{Name: Site().AuthorName}},
+		Items:       items,
+	}
+
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Printf("feeds: failed to encode JSON feed: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/feed+json; charset=utf-8")
+
+	_, err = w.Write(body)
+	if err != nil {
+		log.Printf("feeds: failed to write JSON feed: %v", err)
+	}
+}
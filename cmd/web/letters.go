@@ -17,7 +17,7 @@ import (
 func LettersPageHandler(
 	w http.ResponseWriter,
 	r *http.Request,
-	s storage.Storage,
+	s storage.Backend,
 	currentTag, design string,
 	a *auth.Auth) {
 	var (
@@ -57,7 +57,7 @@ func LettersPageHandler(
 	}
 }
 
-func GetLetterHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, letterID string, a *auth.Auth) {
+func GetLetterHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, slug string, a *auth.Auth) {
 	authenticated := a.IsAuthenticated(r)
 	if !authenticated {
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
@@ -65,48 +65,38 @@ func GetLetterHandler(w http.ResponseWriter, r *http.Request, s storage.Storage,
 		return
 	}
 
-	letters, err := service.ListLetters(r.Context(), s, "all")
+	letter, err := service.GetLetterBySlug(r.Context(), s, slug)
 	if err != nil {
-		HandleError(w, r, apperrors.StorageFailed(err), "GetLetterHandler", "listLetters")
+		HandleError(w, r, lookupError(err), "GetLetterHandler", "getLetter")
 
 		return
 	}
 
 	var component templ.Component
 
-	for _, letter := range letters {
-		if letter.ID == letterID {
-			body, err := s.GetDocumentBody(r.Context(), letter.S3Key)
-			if err != nil {
-				HandleError(w, r, apperrors.NotFound(err), "GetLetterHandler", "getBody")
-
-				return
-			}
-
-			dc := model.DisplayContent{
-				ID:    letter.ID,
-				S3Key: letter.S3Key,
-				Body:  body,
-			}
-
-			if IsHTMXRequest(r) {
-				SetPartialResponseHeaders(w)
+	body, err := storage.GetDocumentBody(r.Context(), s, letter.S3Key)
+	if err != nil {
+		HandleError(w, r, apperrors.NotFound(err), "GetLetterHandler", "getBody")
 
-				component = LetterDisplay(dc, authenticated)
-			} else {
-				component = LetterPage(dc, authenticated)
-			}
+		return
+	}
 
-			err = renderHTML(w, r, http.StatusOK, component)
-			if err != nil {
-				HandleError(w, r, apperrors.RenderFailed(err), "GetLetterHandler", "render")
+	dc := model.DisplayContent{
+		ID:    letter.ID,
+		S3Key: letter.S3Key,
+		Body:  body,
+	}
 
-				return
-			}
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
 
-			return
-		}
+		component = LetterDisplay(dc, authenticated)
+	} else {
+		component = LetterPage(dc, authenticated)
 	}
 
-	HandleError(w, r, apperrors.NotFound(nil), "GetLetterHandler", "findLetter")
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "GetLetterHandler", "render")
+	}
 }
@@ -15,7 +15,7 @@ func TestLetterListRendering(t *testing.T) {
 	// Set up authentication once for all sub-tests
 	a, addAuthCookie := testAuthentication(t)
 
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("render letter list page", func(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letters", nil)
@@ -24,7 +24,7 @@ func TestLetterListRendering(t *testing.T) {
 		// Add authentication cookie to this request
 		addAuthCookie(req)
 
-		web.LettersPageHandler(rec, req, *s, "all", "list", a)
+		web.LettersPageHandler(rec, req, s, "all", "list", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -54,7 +54,7 @@ func TestLetterListRendering(t *testing.T) {
 		// Set the HX-Request header to trigger partial rendering
 		req.Header.Set("Hx-Request", "true")
 
-		web.LettersPageHandler(rec, req, *s, "all", "list", a)
+		web.LettersPageHandler(rec, req, s, "all", "list", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -81,7 +81,7 @@ func TestLetterListRendering(t *testing.T) {
 		addAuthCookie(req)
 
 		tag := "Tag1"
-		web.LettersPageHandler(rec, req, *s, tag, "list", a)
+		web.LettersPageHandler(rec, req, s, tag, "list", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -118,7 +118,7 @@ func TestLetterListRendering(t *testing.T) {
 
 		// Enter a non-existent tag to get zero results back (filter all letters).
 		tag := "non-existent-tag"
-		web.LettersPageHandler(rec, req, *s, tag, "list", a)
+		web.LettersPageHandler(rec, req, s, tag, "list", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -136,19 +136,19 @@ func TestLetterListRendering(t *testing.T) {
 }
 
 func TestLetterRendering(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	// Set up authentication once for all sub-tests
 	a, addAuthCookie := testAuthentication(t)
 
 	t.Run("render letter page", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letter?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letters/test-letter", nil)
 		rec := httptest.NewRecorder()
 
 		// Add authentication cookie
 		addAuthCookie(req)
 
-		web.GetLetterHandler(rec, req, *s, "0", a)
+		web.GetLetterHandler(rec, req, s, "test-letter", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -176,7 +176,7 @@ func TestLetterRendering(t *testing.T) {
 	})
 
 	t.Run("render letter display only", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letter?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letters/test-letter", nil)
 		rec := httptest.NewRecorder()
 
 		// Add authentication cookie
@@ -185,7 +185,7 @@ func TestLetterRendering(t *testing.T) {
 		// Set HTMX header for partial rendering
 		req.Header.Set("Hx-Request", "true")
 
-		web.GetLetterHandler(rec, req, *s, "0", a)
+		web.GetLetterHandler(rec, req, s, "test-letter", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -214,16 +214,16 @@ func TestLetterRendering(t *testing.T) {
 }
 
 func TestGetLetterNotFound(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	a, addAuthCookie := testAuthentication(t)
 
-	t.Run("returns 404 for non-existent letter ID", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letter?id=non-existent-id", nil)
+	t.Run("returns 404 for non-existent letter slug", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letters/does-not-exist", nil)
 		rec := httptest.NewRecorder()
 
 		addAuthCookie(req)
 
-		web.GetLetterHandler(rec, req, *s, "non-existent-id", a)
+		web.GetLetterHandler(rec, req, s, "does-not-exist", a)
 
 		if rec.Code != http.StatusNotFound {
 			t.Errorf("expected status 404, got %d", rec.Code)
@@ -233,12 +233,12 @@ func TestGetLetterNotFound(t *testing.T) {
 
 func TestLetterCardConversion(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("letter to card conversion", func(t *testing.T) {
 		testLetterPath := "letters/test-letter.yaml"
 
-		letter, err := service.GetLetter(ctx, *s, testLetterPath, 1)
+		letter, err := service.GetLetter(ctx, s, testLetterPath)
 		if err != nil {
 			t.Fatalf("failed to get letter: %v", err)
 		}
@@ -253,8 +253,8 @@ func TestLetterCardConversion(t *testing.T) {
 			t.Errorf("expected card subtitle %q, got %q", letter.Subtitle, card.Subtitle)
 		}
 
-		if card.Get != "/letter?id=1" {
-			t.Errorf("expected card get URL '/letter?id=1', got %q", card.Get)
+		if card.Get != "/letters/test-letter" {
+			t.Errorf("expected card get URL '/letters/test-letter', got %q", card.Get)
 		}
 
 		// Letters should have Date but no ImagePath
@@ -266,7 +266,7 @@ func TestLetterCardConversion(t *testing.T) {
 			t.Errorf("expected card image path to be empty for letters, got %q", card.ImagePath)
 		}
 
-		if card.Date != letter.Date {
+		if card.Date != string(letter.Date) {
 			t.Errorf("expected card date %q, got %q", letter.Date, card.Date)
 		}
 	})
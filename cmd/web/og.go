@@ -0,0 +1,254 @@
+package web
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// ogCachePrefix is where drawn cards are kept, one per document, so a card is
+// only drawn again once its document changes.
+const ogCachePrefix = service.CachePrefix + "og/"
+
+// ogCacheControl lets crawlers and link previews hold a card for a day.
+const ogCacheControl = "public, max-age=86400"
+
+// OGImagePath is the path of the social card of the document, such as
+// /og/articles/my-post.png. Books are under /og/books, as their pages are.
+func OGImagePath(docType, slug string) string {
+	return "/og/" + apiTypeSegment(docType) + "/" + slug + ".png"
+}
+
+// OGImageURL is the absolute URL of the social card of the document, for the
+// og:image of its page.
+func OGImageURL(docType, slug string) string {
+	return absoluteURL(OGImagePath(docType, slug))
+}
+
+// isOGCard reports whether imageURL is one of the drawn social cards, whose
+// size is known.
+func isOGCard(imageURL string) bool {
+	return strings.HasPrefix(imageURL, absoluteURL("/og/"))
+}
+
+// OGImageHandler serves the social card of the published document of the type
+// named by segment, drawing it on the first request and whenever the document
+// has changed since. Letters have no card, nor do drafts.
+func OGImageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, segment, slug string) {
+	docType, ok := apiDocType(segment)
+	if !ok || docType == "letters" {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	ctx := r.Context()
+
+	card, key, err := ogCardFor(ctx, s, docType, slug)
+	if err != nil {
+		if lookupError(err).HTTPStatus == http.StatusNotFound {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		log.Printf("OGImageHandler: failed to get %s %q: %v", docType, slug, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+
+		return
+	}
+
+	if card == nil {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	cacheKey := ogCachePrefix + docType + "/" + slug + ".png"
+
+	content, modified, err := cachedOGImage(ctx, s, docType, key, cacheKey)
+	if err != nil {
+		log.Printf("OGImageHandler: failed to read cached card %s: %v", cacheKey, err)
+	}
+
+	if content == nil {
+		content, err = drawOGCard(*card, ogCardImage(ctx, s, card.Image))
+		if err != nil {
+			log.Printf("OGImageHandler: failed to draw card for %s %q: %v", docType, slug, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		modified = time.Now()
+
+		// A card that cannot be cached is still served; it is drawn again
+		// next time.
+		err = s.WriteFile(ctx, cacheKey, content)
+		if err != nil {
+			log.Printf("OGImageHandler: failed to cache card %s: %v", cacheKey, err)
+		}
+	}
+
+	w.Header().Set("Cache-Control", ogCacheControl)
+	http.ServeContent(w, r, cacheKey, modified, bytes.NewReader(content))
+}
+
+// ogCardFor gathers what the card of the document shows, with the storage key
+// of its metadata. A document that is not published has no card.
+func ogCardFor(ctx context.Context, s storage.Backend, docType, slug string) (*ogCard, string, error) {
+	now := time.Now()
+
+	switch docType {
+	case "articles":
+		article, err := service.GetArticleBySlug(ctx, s, slug)
+		if err != nil || !article.IsPublished(now) {
+			return nil, "", err
+		}
+
+		return &ogCard{
+			Kind:     "Article",
+			Title:    article.Title,
+			Subtitle: cmp.Or(article.Subtitle, article.Preview),
+			Date:     displayDate(article.Date),
+			Tags:     article.Tags,
+		}, article.S3Key, nil
+	case "projects":
+		project, err := service.GetProjectBySlug(ctx, s, slug)
+		if err != nil || !project.IsPublished(now) {
+			return nil, "", err
+		}
+
+		return &ogCard{
+			Kind:     "Project",
+			Title:    project.Title,
+			Subtitle: cmp.Or(project.Subtitle, project.Preview),
+			Date:     project.Timespan(dateLayout()),
+			Tags:     project.Tags,
+			Image:    project.Image,
+		}, project.S3Key, nil
+	case "reading-list":
+		book, err := service.GetBookBySlug(ctx, s, slug)
+		if err != nil || !book.IsPublished(now) {
+			return nil, "", err
+		}
+
+		return &ogCard{
+			Kind:     "Book",
+			Title:    book.Title,
+			Subtitle: cmp.Or(book.Subtitle, "by "+book.Author),
+			Date:     displayDate(cmp.Or(book.Finished, book.Started)),
+			Tags:     book.Tags,
+			Image:    book.Image,
+		}, book.S3Key, nil
+	}
+
+	schema, ok := customType(docType)
+	if !ok {
+		return nil, "", nil
+	}
+
+	doc, err := service.GetCustomBySlug(ctx, s, schema, slug)
+	if err != nil || !doc.IsPublished(now) {
+		return nil, "", err
+	}
+
+	return &ogCard{
+		Kind:     schema.DisplayLabel(),
+		Title:    doc.Title,
+		Subtitle: cmp.Or(doc.Value(schema.Card.Subtitle), doc.Preview),
+		Date:     displayDate(model.Date(doc.Value(schema.Card.Date))),
+		Tags:     doc.Tags,
+		Image:    doc.Value(schema.Card.Image),
+	}, doc.S3Key, nil
+}
+
+// cachedOGImage returns the cached card at cacheKey and when it was drawn, or
+// nil if there is none or its document, at key, has been written since.
+func cachedOGImage(ctx context.Context, s storage.Backend, docType, key, cacheKey string) ([]byte, time.Time, error) {
+	cached, err := lastModified(ctx, s, ogCachePrefix+docType)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	drawn, ok := cached[cacheKey]
+	if !ok {
+		return nil, time.Time{}, nil
+	}
+
+	docs, err := lastModified(ctx, s, docType)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if drawn.Before(latest(docs[key], docs[storage.BodyKey(key)])) {
+		return nil, time.Time{}, nil
+	}
+
+	f, err := s.GetFile(ctx, cacheKey)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, time.Time{}, nil
+		}
+
+		return nil, time.Time{}, err
+	}
+
+	defer func() {
+		err := f.Close()
+		if err != nil {
+			log.Printf("failed to close %s: %v", cacheKey, err)
+		}
+	}()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return content, drawn, nil
+}
+
+// ogCardImage loads the image the card of a project or book shows, from the
+// path its page serves it at. The card is drawn without one that is missing
+// or cannot be read.
+func ogCardImage(ctx context.Context, s storage.Backend, path string) []byte {
+	key, ok := strings.CutPrefix(path, "/storage/")
+	if !ok {
+		return nil
+	}
+
+	f, err := s.GetFile(ctx, key)
+	if err != nil {
+		log.Printf("ogCardImage: failed to open %s: %v", key, err)
+
+		return nil
+	}
+
+	defer func() {
+		err := f.Close()
+		if err != nil {
+			log.Printf("failed to close %s: %v", key, err)
+		}
+	}()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		log.Printf("ogCardImage: failed to read %s: %v", key, err)
+
+		return nil
+	}
+
+	return content
+}
@@ -0,0 +1,177 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+	"timterests/internal/storage"
+)
+
+func getOGImage(t *testing.T, s storage.Backend, segment, slug string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/og/"+segment+"/"+slug+".png", nil)
+	rec := httptest.NewRecorder()
+
+	web.OGImageHandler(rec, req, s, segment, slug)
+
+	return rec
+}
+
+func TestOGImageHandler(t *testing.T) {
+	s := testSetup(t)
+
+	rec := getOGImage(t, s, "articles", "test-article")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("expected image/png, got %q", ct)
+	}
+
+	img, err := png.Decode(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to decode card: %v", err)
+	}
+
+	if b := img.Bounds(); b.Dx() != 1200 || b.Dy() != 630 {
+		t.Errorf("expected a 1200x630 card, got %v", b)
+	}
+
+	objects, err := s.ListObjects(context.Background(), "cache/og/articles/")
+	if err != nil || len(objects) != 1 || objects[0].Key != "cache/og/articles/test-article.png" {
+		t.Fatalf("expected the card cached in storage, got %v (%v)", objects, err)
+	}
+
+	t.Run("served from the cache", func(t *testing.T) {
+		err := s.WriteFile(context.Background(), "cache/og/articles/test-article.png", []byte("cached"))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+
+		rec := getOGImage(t, s, "articles", "test-article")
+		if rec.Body.String() != "cached" {
+			t.Errorf("expected the cached card, got %d bytes", rec.Body.Len())
+		}
+	})
+
+	t.Run("drawn again once the document changes", func(t *testing.T) {
+		err := storage.WriteDocument(context.Background(), s, "articles/test-article.yaml",
+			[]byte("title: Renamed Article\ndate: \"2026-01-01\"\n"), []byte("Body\n"))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+
+		rec := getOGImage(t, s, "articles", "test-article")
+
+		_, err = png.Decode(bytes.NewReader(rec.Body.Bytes()))
+		if err != nil {
+			t.Errorf("expected a freshly drawn card, got %q: %v", rec.Body.String(), err)
+		}
+	})
+}
+
+func TestOGImageHandlerDrawsImage(t *testing.T) {
+	s := testSetup(t)
+
+	picture := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	for i := range picture.Pix {
+		picture.Pix[i] = 0xff // white
+	}
+
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, picture)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	// The project's imagePath names the fixture by its path in the repo.
+	err = s.WriteFile(context.Background(), "testdata/images/test.png", buf.Bytes())
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	for _, segment := range []string{"projects", "books"} {
+		slug := map[string]string{"projects": "test-project", "books": "test-book"}[segment]
+
+		rec := getOGImage(t, s, segment, slug)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", segment, rec.Code)
+		}
+
+		card, err := png.Decode(bytes.NewReader(rec.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("%s: failed to decode card: %v", segment, err)
+		}
+
+		// The middle of the image square on the right.
+		if got := color.RGBAModel.Convert(card.At(940, 315)); got != (color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}) {
+			t.Errorf("%s: expected the image on the card, got %v", segment, got)
+		}
+	}
+}
+
+func TestOGImageHandlerNotFound(t *testing.T) {
+	s := testSetup(t)
+
+	err := storage.WriteDocument(context.Background(), s, "articles/draft-article.yaml",
+		[]byte("title: Draft Article\ndate: \"2026-02-01\"\nstatus: draft\n"), []byte("Draft body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	tests := []struct {
+		name, segment, slug string
+	}{
+		{"missing document", "articles", "does-not-exist"},
+		{"draft", "articles", "draft-article"},
+		{"letter", "letters", "test-letter"},
+		{"unknown type", "nothing-here", "test-article"},
+		{"reading-list by its directory", "reading-list", "test-book"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getOGImage(t, s, tt.segment, tt.slug)
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("expected 404, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestOGImageMetaTags(t *testing.T) {
+	s := testSetup(t)
+	t.Setenv("SITE_URL", "https://example.com")
+
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/test-article", nil)
+	rec := httptest.NewRecorder()
+
+	web.GetArticleHandler(rec, req, s, "test-article", a)
+
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`<meta property="og:image" content="https://example.com/og/articles/test-article.png">`,
+		`<meta property="og:image:width" content="1200">`,
+		`<meta name="twitter:card" content="summary_large_image">`,
+		`<meta name="twitter:image" content="https://example.com/og/articles/test-article.png">`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("article page missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	// Project and book images may be GIF, JPEG or WebP as well as PNG.
+	_ "golang.org/x/image/webp"
+	_ "image/gif"
+	_ "image/jpeg"
+)
+
+// The card is the size link previews ask for, and is drawn in the site's dark
+// theme.
+const (
+	ogWidth   = 1200
+	ogHeight  = 630
+	ogPadding = 80
+	// ogImageSize is the square a project or book image is fitted into, on
+	// the right of the card.
+	ogImageSize = 360
+)
+
+var (
+	ogBackground = color.RGBA{R: 0x1e, G: 0x29, B: 0x3b, A: 0xff} // --bg-dark
+	ogPanel      = color.RGBA{R: 0x33, G: 0x41, B: 0x55, A: 0xff} // --bg-dark-accent
+	ogAccent     = color.RGBA{R: 0x10, G: 0xb9, B: 0x81, A: 0xff} // --green
+	ogHighlight  = color.RGBA{R: 0x34, G: 0xd3, B: 0x99, A: 0xff} // --green-accent
+	ogText       = color.RGBA{R: 0xf1, G: 0xf5, B: 0xf9, A: 0xff} // --text-dark
+	ogMuted      = color.RGBA{R: 0x94, G: 0xa3, B: 0xb8, A: 0xff}
+)
+
+// ogFonts are the Go fonts, parsed once. Faces are made from them per card,
+// since a face is not safe to share between requests.
+var ogFonts = sync.OnceValues(func() (map[string]*opentype.Font, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("parsing regular font: %w", err)
+	}
+
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("parsing bold font: %w", err)
+	}
+
+	return map[string]*opentype.Font{"regular": regular, "bold": bold}, nil
+})
+
+// ogCard is what a document's social card shows. Image is the path its page
+// serves the project or book image at, if it has one.
+type ogCard struct {
+	Kind     string
+	Title    string
+	Subtitle string
+	Date     string
+	Tags     []string
+	Image    string
+}
+
+// ogFaceSizes are the font and size of each piece of text on the card.
+var ogFaceSizes = map[string]struct {
+	font string
+	size float64
+}{
+	"kind":     {"bold", 28},
+	"title":    {"bold", 64},
+	"subtitle": {"regular", 34},
+	"footer":   {"regular", 28},
+	"site":     {"bold", 30},
+}
+
+// drawOGCard draws card as a PNG, with the decoded picture on the right when
+// there is one. A picture that does not decode is left out.
+func drawOGCard(card ogCard, picture []byte) ([]byte, error) {
+	faces, err := ogFaces()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		for name, face := range faces {
+			err := face.Close()
+			if err != nil {
+				log.Printf("drawOGCard: failed to close %s face: %v", name, err)
+			}
+		}
+	}()
+
+	img := image.NewRGBA(image.Rect(0, 0, ogWidth, ogHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(ogBackground), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, 16, ogHeight), image.NewUniform(ogAccent), image.Point{}, draw.Src)
+
+	textWidth := ogWidth - 2*ogPadding
+	if drawOGPicture(img, picture) {
+		textWidth -= ogImageSize + 40
+	}
+
+	y := 130
+	drawText(img, faces["kind"], ogHighlight, ogPadding, y, strings.ToUpper(card.Kind))
+
+	y += 90
+	for _, line := range wrapText(faces["title"], card.Title, textWidth, 3) {
+		drawText(img, faces["title"], ogText, ogPadding, y, line)
+		y += 76
+	}
+
+	y += 10
+	for _, line := range wrapText(faces["subtitle"], card.Subtitle, textWidth, 2) {
+		drawText(img, faces["subtitle"], ogMuted, ogPadding, y, line)
+		y += 44
+	}
+
+	drawOGFooter(img, faces, card)
+
+	var buf bytes.Buffer
+
+	err = png.Encode(&buf, img)
+	if err != nil {
+		return nil, fmt.Errorf("encoding card: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ogFaces makes a face for each piece of text on the card.
+func ogFaces() (map[string]font.Face, error) {
+	fonts, err := ogFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	faces := make(map[string]font.Face, len(ogFaceSizes))
+
+	for name, spec := range ogFaceSizes {
+		face, err := opentype.NewFace(fonts[spec.font], &opentype.FaceOptions{
+			Size:    spec.size,
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("making %s face: %w", name, err)
+		}
+
+		faces[name] = face
+	}
+
+	return faces, nil
+}
+
+// drawOGPicture fits picture into the square on the right of the card, and
+// reports whether it could be decoded.
+func drawOGPicture(img draw.Image, picture []byte) bool {
+	if picture == nil {
+		return false
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(picture))
+	if err != nil {
+		log.Printf("drawOGPicture: failed to decode image: %v", err)
+
+		return false
+	}
+
+	box := image.Rect(ogWidth-ogPadding-ogImageSize, 135, ogWidth-ogPadding, 135+ogImageSize)
+	draw.Draw(img, box, image.NewUniform(ogPanel), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(img, fitRect(src.Bounds(), box), src, src.Bounds(), draw.Over, nil)
+
+	return true
+}
+
+// drawOGFooter runs along the bottom of the card: the date and tags on the
+// left, the site's name on the right.
+func drawOGFooter(img draw.Image, faces map[string]font.Face, card ogCard) {
+	site := Site().Name
+	siteWidth := font.MeasureString(faces["site"], site).Ceil()
+	drawText(img, faces["site"], ogHighlight, ogWidth-ogPadding-siteWidth, ogHeight-70, site)
+
+	tags := make([]string, 0, len(card.Tags))
+	for _, tag := range card.Tags {
+		tags = append(tags, "#"+tag)
+	}
+
+	footer := strings.Join(tags, " ")
+	if card.Date != "" && footer != "" {
+		footer = card.Date + " · " + footer
+	} else if card.Date != "" {
+		footer = card.Date
+	}
+
+	lines := wrapText(faces["footer"], footer, ogWidth-2*ogPadding-siteWidth-40, 1)
+	if len(lines) > 0 {
+		drawText(img, faces["footer"], ogMuted, ogPadding, ogHeight-70, lines[0])
+	}
+}
+
+// drawText draws s with its baseline at y.
+func drawText(img draw.Image, face font.Face, c color.Color, x, y int, s string) {
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
+
+// wrapText breaks s into at most maxLines lines no wider than width, word by
+// word. What does not fit is cut short with an ellipsis.
+func wrapText(face font.Face, s string, width, maxLines int) []string {
+	fits := func(line string) bool {
+		return font.MeasureString(face, line).Ceil() <= width
+	}
+
+	var lines []string
+
+	line := ""
+
+	for _, word := range strings.Fields(s) {
+		candidate := strings.TrimSpace(line + " " + word)
+		if fits(candidate) || line == "" {
+			line = candidate
+
+			continue
+		}
+
+		lines = append(lines, line)
+		line = word
+	}
+
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	// The last line shown is cut short when more follow it, and any line
+	// still too wide, such as one long word, is cut to fit.
+	truncated := len(lines) > maxLines
+	if truncated {
+		lines = lines[:maxLines]
+	}
+
+	for i, l := range lines {
+		if fits(l) && (!truncated || i < len(lines)-1) {
+			continue
+		}
+
+		runes := []rune(l)
+		for len(runes) > 0 && !fits(string(runes)+"…") {
+			runes = runes[:len(runes)-1]
+		}
+
+		lines[i] = strings.TrimSpace(string(runes)) + "…"
+	}
+
+	return lines
+}
+
+// fitRect is the largest rectangle with the proportions of src that fits in
+// box, centred in it.
+func fitRect(src, box image.Rectangle) image.Rectangle {
+	w, h := box.Dx(), box.Dy()
+
+	if src.Dx()*h > src.Dy()*w {
+		h = src.Dy() * w / max(src.Dx(), 1)
+	} else {
+		w = src.Dx() * h / max(src.Dy(), 1)
+	}
+
+	x := box.Min.X + (box.Dx()-w)/2
+	y := box.Min.Y + (box.Dy()-h)/2
+
+	return image.Rect(x, y, x+w, y+h)
+}
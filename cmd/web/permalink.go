@@ -0,0 +1,60 @@
+package web
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+
+	apperrors "timterests/internal/errors"
+
+	"timterests/internal/auth"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// DocumentURL returns the permalink path for the document of docType with slug.
+func DocumentURL(docType, slug string) string {
+	return model.DocumentPath(docType, slug)
+}
+
+// SeriesURL returns the path of the landing page for the series with slug.
+func SeriesURL(slug string) string {
+	return "/series/" + slug
+}
+
+// lookupError classifies an error from fetching a document by slug. A slug that
+// names nothing is the visitor's mistake, anything else is ours.
+func lookupError(err error) *apperrors.AppError {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, service.ErrInvalidSlug) {
+		return apperrors.NotFound(err)
+	}
+
+	return apperrors.StorageFailed(err)
+}
+
+// LegacyRedirectHandler sends the old ?id= URLs to the document's permalink with
+// a 301, so links shared before slugs existed keep working.
+func LegacyRedirectHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, docType string, a *auth.Auth) {
+	if docType == "letters" && !a.IsAuthenticated(r) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		HandleError(w, r, apperrors.NotFound(nil), "LegacyRedirectHandler", "readID")
+
+		return
+	}
+
+	slug, err := service.LegacySlug(r.Context(), s, docType+"/", id)
+	if err != nil {
+		HandleError(w, r, apperrors.NotFound(err), "LegacyRedirectHandler", "resolveSlug")
+
+		return
+	}
+
+	http.Redirect(w, r, DocumentURL(docType, slug), http.StatusMovedPermanently)
+}
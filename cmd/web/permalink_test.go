@@ -0,0 +1,124 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+	"timterests/internal/service"
+)
+
+func TestDocumentURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		docType  string
+		expected string
+	}{
+		{"articles", "/articles/my-post"},
+		{"projects", "/projects/my-post"},
+		{"reading-list", "/books/my-post"},
+		{"letters", "/letters/my-post"},
+	}
+
+	for _, tc := range tests {
+		got := web.DocumentURL(tc.docType, "my-post")
+		if got != tc.expected {
+			t.Errorf("DocumentURL(%q) = %q, want %q", tc.docType, got, tc.expected)
+		}
+	}
+}
+
+func TestLegacyRedirectHandler(t *testing.T) {
+	s := testSetup(t)
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+	t.Run("redirects a numeric id permanently to the permalink", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/article?id=0", nil)
+		rec := httptest.NewRecorder()
+
+		web.LegacyRedirectHandler(rec, req, s, "articles", a)
+
+		if rec.Code != http.StatusMovedPermanently {
+			t.Fatalf("expected status 301, got %d", rec.Code)
+		}
+
+		loc := rec.Header().Get("Location")
+		if loc != "/articles/test-article" {
+			t.Errorf("expected redirect to /articles/test-article, got %q", loc)
+		}
+	})
+
+	t.Run("redirects a book to the books path", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/book?id=test-book", nil)
+		rec := httptest.NewRecorder()
+
+		web.LegacyRedirectHandler(rec, req, s, "reading-list", a)
+
+		loc := rec.Header().Get("Location")
+		if loc != "/books/test-book" {
+			t.Errorf("expected redirect to /books/test-book, got %q", loc)
+		}
+	})
+
+	t.Run("returns 404 for an id past the end of the listing", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/article?id=99", nil)
+		rec := httptest.NewRecorder()
+
+		web.LegacyRedirectHandler(rec, req, s, "articles", a)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("sends unauthenticated letter links to login", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/letter?id=0", nil)
+		rec := httptest.NewRecorder()
+
+		web.LegacyRedirectHandler(rec, req, s, "letters", a)
+
+		loc := rec.Header().Get("Location")
+		if loc != "/login" {
+			t.Errorf("expected redirect to /login, got %q", loc)
+		}
+	})
+}
+
+// An article's permalink must not change when another article is added ahead
+// of it in the listing, which is what broke the old positional IDs.
+func TestPermalinkStableAcrossWrites(t *testing.T) {
+	s := testSetup(t)
+	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+	err := s.WriteFile(context.Background(), "articles/aaa-first.yaml", []byte("title: First\ndate: 2020-01-01\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	err = s.WriteFile(context.Background(), "articles/aaa-first.md", []byte("# First\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	article, err := service.GetArticleBySlug(context.Background(), s, "test-article")
+	if err != nil {
+		t.Fatalf("failed to get article: %v", err)
+	}
+
+	card := web.ArticleCard(*article)
+	if card.Get != "/articles/test-article" {
+		t.Errorf("expected card URL /articles/test-article, got %q", card.Get)
+	}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, card.Get, nil)
+	rec := httptest.NewRecorder()
+
+	web.GetArticleHandler(rec, req, s, "test-article", a)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+}
@@ -3,6 +3,7 @@ package web
 import (
 	"net/http"
 	"reflect"
+	"time"
 
 	apperrors "timterests/internal/errors"
 
@@ -14,7 +15,7 @@ import (
 	"github.com/a-h/templ"
 )
 
-func ProjectsPageHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, currentTag, design string) {
+func ProjectsPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, currentTag, design string) {
 	var (
 		component templ.Component
 		tags      []string
@@ -27,6 +28,8 @@ func ProjectsPageHandler(w http.ResponseWriter, r *http.Request, s storage.Stora
 		return
 	}
 
+	projects = service.Published(projects, time.Now())
+
 	for i := range projects {
 		v := reflect.ValueOf(projects[i])
 		tags = storage.GetTags(v, tags)
@@ -46,51 +49,52 @@ func ProjectsPageHandler(w http.ResponseWriter, r *http.Request, s storage.Stora
 	}
 }
 
-func GetProjectHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, projectID string, a *auth.Auth) {
-	projects, err := service.ListProjects(r.Context(), s, "all")
+func GetProjectHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, slug string, a *auth.Auth) {
+	project, err := service.GetProjectBySlug(r.Context(), s, slug)
 	if err != nil {
-		HandleError(w, r, apperrors.StorageFailed(err), "GetProjectHandler", "listProjects")
+		HandleError(w, r, lookupError(err), "GetProjectHandler", "getProject")
 
 		return
 	}
 
-	for _, project := range projects {
-		if project.ID == projectID {
-			body, err := s.GetDocumentBody(r.Context(), project.S3Key)
-			if err != nil {
-				HandleError(w, r, apperrors.NotFound(err), "GetProjectHandler", "getBody")
+	authenticated := a.IsAuthenticated(r)
 
-				return
-			}
+	// Drafts and scheduled documents are only reachable by the author.
+	if !project.IsPublished(time.Now()) && !authenticated {
+		HandleError(w, r, apperrors.NotFound(nil), "GetProjectHandler", "checkPublished")
 
-			dc := model.DisplayContent{
-				ID:    project.ID,
-				S3Key: project.S3Key,
-				Body:  body,
-			}
+		return
+	}
 
-			var component templ.Component
+	body, headings, err := storage.GetDocumentBodyWithHeadings(r.Context(), s, project.S3Key)
+	if err != nil {
+		HandleError(w, r, apperrors.NotFound(err), "GetProjectHandler", "getBody")
 
-			authenticated := a.IsAuthenticated(r)
+		return
+	}
 
-			if IsHTMXRequest(r) {
-				SetPartialResponseHeaders(w)
+	dc := model.DisplayContent{
+		ID:        project.ID,
+		S3Key:     project.S3Key,
+		Body:      body,
+		TOC:       project.TableOfContents(headings),
+		Backlinks: backlinks(r.Context(), s, "projects", project.ID),
+	}
 
-				component = ProjectDisplay(dc, project.Repository, project.Timespan(), authenticated)
-			} else {
-				component = ProjectPage(project, dc, project.Repository, project.Timespan(), authenticated)
-			}
+	related := relatedCards(r.Context(), s, "projects", project.Document)
 
-			err = renderHTML(w, r, http.StatusOK, component)
-			if err != nil {
-				HandleError(w, r, apperrors.RenderFailed(err), "GetProjectHandler", "render")
+	var component templ.Component
 
-				return
-			}
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
 
-			return
-		}
+		component = ProjectDisplay(dc, project.Repository, project.Timespan(dateLayout()), related, authenticated)
+	} else {
+		component = ProjectPage(*project, dc, project.Repository, project.Timespan(dateLayout()), related, authenticated)
 	}
 
-	HandleError(w, r, apperrors.NotFound(nil), "GetProjectHandler", "findProject")
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "GetProjectHandler", "render")
+	}
 }
@@ -16,7 +16,7 @@ import (
 )
 
 func TestProjectListRendering(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("render project list page", func(t *testing.T) {
 		t.Parallel()
@@ -24,7 +24,7 @@ func TestProjectListRendering(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/projects", nil)
 		rec := httptest.NewRecorder()
 
-		web.ProjectsPageHandler(rec, req, *s, "all", "list")
+		web.ProjectsPageHandler(rec, req, s, "all", "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -53,7 +53,7 @@ func TestProjectListRendering(t *testing.T) {
 		// Set the HX-Request header to trigger partial rendering
 		req.Header.Set("Hx-Request", "true")
 
-		web.ProjectsPageHandler(rec, req, *s, "all", "list")
+		web.ProjectsPageHandler(rec, req, s, "all", "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -79,7 +79,7 @@ func TestProjectListRendering(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		tag := "Golang"
-		web.ProjectsPageHandler(rec, req, *s, tag, "list")
+		web.ProjectsPageHandler(rec, req, s, tag, "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -115,7 +115,7 @@ func TestProjectListRendering(t *testing.T) {
 
 		// Enter a non-existent tag to get zero results back (filter all projects).
 		tag := "non-existent-tag"
-		web.ProjectsPageHandler(rec, req, *s, tag, "list")
+		web.ProjectsPageHandler(rec, req, s, tag, "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -133,16 +133,16 @@ func TestProjectListRendering(t *testing.T) {
 }
 
 func TestProjectRendering(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	// Create auth instance for tests (won't be authenticated but prevents nil pointer)
 	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
 	t.Run("render project page", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/project?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/projects/test-project", nil)
 		rec := httptest.NewRecorder()
 
-		web.GetProjectHandler(rec, req, *s, "0", a)
+		web.GetProjectHandler(rec, req, s, "test-project", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -170,13 +170,13 @@ func TestProjectRendering(t *testing.T) {
 	})
 
 	t.Run("render project display only", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/project?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/projects/test-project", nil)
 		rec := httptest.NewRecorder()
 
 		// Set HTMX header for partial rendering
 		req.Header.Set("Hx-Request", "true")
 
-		web.GetProjectHandler(rec, req, *s, "0", a)
+		web.GetProjectHandler(rec, req, s, "test-project", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -205,14 +205,14 @@ func TestProjectRendering(t *testing.T) {
 }
 
 func TestGetProjectNotFound(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
-	t.Run("returns 404 for non-existent project ID", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/project?id=non-existent-id", nil)
+	t.Run("returns 404 for non-existent project slug", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/projects/does-not-exist", nil)
 		rec := httptest.NewRecorder()
 
-		web.GetProjectHandler(rec, req, *s, "non-existent-id", a)
+		web.GetProjectHandler(rec, req, s, "does-not-exist", a)
 
 		if rec.Code != http.StatusNotFound {
 			t.Errorf("expected status 404, got %d", rec.Code)
@@ -222,12 +222,12 @@ func TestGetProjectNotFound(t *testing.T) {
 
 func TestProjectCardConversion(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("project to card conversion", func(t *testing.T) {
 		testProjectPath := "projects/test-project.yaml"
 
-		project, err := service.GetProject(ctx, *s, testProjectPath, 1)
+		project, err := service.GetProject(ctx, s, testProjectPath)
 		if err != nil {
 			t.Fatalf("failed to get project: %v", err)
 		}
@@ -242,8 +242,8 @@ func TestProjectCardConversion(t *testing.T) {
 			t.Errorf("expected card subtitle %q, got %q", project.Subtitle, card.Subtitle)
 		}
 
-		if card.Get != "/project?id=1" {
-			t.Errorf("expected card get URL '/project?id=1', got %q", card.Get)
+		if card.Get != "/projects/test-project" {
+			t.Errorf("expected card get URL '/projects/test-project', got %q", card.Get)
 		}
 
 		if card.ImagePath == "" {
@@ -254,7 +254,7 @@ func TestProjectCardConversion(t *testing.T) {
 			t.Errorf("expected card image path %q, got %q", project.Image, card.ImagePath)
 		}
 
-		expectedDate := project.Timespan()
+		expectedDate := project.Timespan(model.DateLayout)
 		if card.Date != expectedDate {
 			t.Errorf("expected card date %q, got %q", expectedDate, card.Date)
 		}
@@ -269,7 +269,7 @@ func TestProjectRepositoryRendering(t *testing.T) {
 
 		var buf bytes.Buffer
 
-		err := web.ProjectDisplay(dc, repository, "", false).Render(context.Background(), &buf)
+		err := web.ProjectDisplay(dc, repository, "", nil, false).Render(context.Background(), &buf)
 		if err != nil {
 			t.Fatalf("failed to render ProjectDisplay: %v", err)
 		}
@@ -0,0 +1,145 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"timterests/cmd/web"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// seedDraft writes an article marked as a draft alongside the test fixtures.
+func seedDraft(t *testing.T, s storage.Backend, slug string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	err := s.WriteFile(ctx, "articles/"+slug+".yaml", []byte("title: Secret Draft\ndate: 2030-01-01\nstatus: draft\n"))
+	if err != nil {
+		t.Fatalf("failed to seed yaml: %v", err)
+	}
+
+	err = s.WriteFile(ctx, "articles/"+slug+".md", []byte("# Secret Draft\n## Sub\n\nNot yet.\n"))
+	if err != nil {
+		t.Fatalf("failed to seed body: %v", err)
+	}
+}
+
+func TestDraftVisibility(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	s := testSetup(t)
+	seedDraft(t, s, "secret-draft")
+
+	t.Run("drafts are left out of the public list", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles", nil)
+		rec := httptest.NewRecorder()
+
+		web.ArticlesPageHandler(rec, req, s, "all", "list")
+
+		if strings.Contains(rec.Body.String(), "Secret Draft") {
+			t.Error("expected the draft to be hidden from the article list")
+		}
+	})
+
+	t.Run("drafts are not found by anonymous visitors", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/secret-draft", nil)
+		rec := httptest.NewRecorder()
+
+		web.GetArticleHandler(rec, req, s, "secret-draft", a)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("admins can preview drafts", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/articles/secret-draft", nil)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.GetArticleHandler(rec, req, s, "secret-draft", a)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", rec.Code)
+		}
+
+		if !strings.Contains(rec.Body.String(), "Secret Draft") {
+			t.Error("expected the draft to render for an admin")
+		}
+	})
+}
+
+func TestSetDocumentStatusHandler(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	statusRequest := func(key, status string) *http.Request {
+		form := url.Values{}
+		form.Set("key", key)
+		form.Set("status", status)
+
+		req := httptest.NewRequestWithContext(
+			context.Background(), http.MethodPost, "/admin/documents/status",
+			strings.NewReader(form.Encode()),
+		)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		addAuthCookie(req)
+
+		return req
+	}
+
+	t.Run("publishes a draft and keeps its other fields", func(t *testing.T) {
+		s := testSetup(t)
+		seedDraft(t, s, "secret-draft")
+
+		rec := httptest.NewRecorder()
+
+		web.SetDocumentStatusHandler(rec, statusRequest("articles/secret-draft.yaml", "published"), s, a)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+
+		article, err := service.GetArticleBySlug(context.Background(), s, "secret-draft")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if article.Status != model.StatusPublished {
+			t.Errorf("expected status %q, got %q", model.StatusPublished, article.Status)
+		}
+
+		if article.Title != "Secret Draft" || article.Date != "2030-01-01" {
+			t.Errorf("expected the other fields to survive, got %+v", article)
+		}
+	})
+
+	t.Run("rejects an unknown status", func(t *testing.T) {
+		s := testSetup(t)
+		rec := httptest.NewRecorder()
+
+		web.SetDocumentStatusHandler(rec, statusRequest("articles/test-article.yaml", "pending"), s, a)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects a key outside the content directories", func(t *testing.T) {
+		s := testSetup(t)
+		rec := httptest.NewRecorder()
+
+		web.SetDocumentStatusHandler(rec, statusRequest("../secrets.yaml", "draft"), s, a)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package web
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	apperrors "timterests/internal/errors"
+
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+
+	"github.com/a-h/templ"
+)
+
+// QuotesPageHandler renders the highlights collected from the books on the
+// reading list, optionally only those from books with currentTag.
+func QuotesPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, currentTag string) {
+	var (
+		component templ.Component
+		tags      []string
+	)
+
+	now := time.Now()
+
+	quotes, err := service.ListQuotes(r.Context(), s, currentTag, now)
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "QuotesPageHandler", "listQuotes")
+
+		return
+	}
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
+
+		component = QuotesList(quotes)
+	} else {
+		// The tag picker offers the tags of every book with a highlight, not
+		// just those of the current filter.
+		all, err := service.ListQuotes(r.Context(), s, "all", now)
+		if err != nil {
+			HandleError(w, r, apperrors.StorageFailed(err), "QuotesPageHandler", "listTags")
+
+			return
+		}
+
+		for i := range all {
+			tags = storage.GetTags(reflect.ValueOf(all[i].Book), tags)
+		}
+
+		component = QuotesPage(quotes, tags)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "QuotesPageHandler", "render")
+	}
+}
+
+// highlightMeta describes where and when a highlight was made, e.g. "page 12,
+// location 150-152 · 2024-03-04".
+func highlightMeta(h model.Highlight) string {
+	parts := make([]string, 0, 2)
+
+	if h.Location != "" {
+		parts = append(parts, h.Location)
+	}
+
+	if !h.Added.IsZero() {
+		parts = append(parts, displayDate(h.Added))
+	}
+
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,174 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const highlightedBook = `title: Dune
+author: Frank Herbert
+tags: [SciFi]
+highlights:
+  - text: I must not fear.
+    location: location 110-112
+    added: "2024-03-04"
+    note: The litany.
+`
+
+func TestQuotes(t *testing.T) {
+	s := testSetup(t)
+
+	err := storage.WriteDocument(context.Background(), s, "reading-list/dune.yaml", []byte(highlightedBook), []byte("Body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	quotes := func(t *testing.T, tag string, htmx bool) *goquery.Document {
+		t.Helper()
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/quotes", nil)
+		if htmx {
+			req.Header.Set("Hx-Request", "true")
+		}
+
+		rec := httptest.NewRecorder()
+
+		web.QuotesPageHandler(rec, req, s, tag)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		return doc
+	}
+
+	t.Run("collects highlights across books", func(t *testing.T) {
+		doc := quotes(t, "all", false)
+
+		if got := doc.Find("figure.quote blockquote").Text(); got != "I must not fear." {
+			t.Errorf("expected the highlight, got %q", got)
+		}
+
+		if got := doc.Find("figure.quote figcaption a").AttrOr("href", ""); got != "/books/dune" {
+			t.Errorf("expected a link to the book, got %q", got)
+		}
+
+		if doc.Find(`select[name="tag"] option[value="SciFi"]`).Length() == 0 {
+			t.Error("expected the book's tag in the filter")
+		}
+	})
+
+	t.Run("filters by tag", func(t *testing.T) {
+		doc := quotes(t, "Testing", true)
+
+		if doc.Find("figure.quote").Length() != 0 {
+			t.Error("expected no quotes from books without the tag")
+		}
+	})
+
+	t.Run("lists highlights on the book page", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/books/dune", nil)
+		rec := httptest.NewRecorder()
+
+		web.GetReadingListBook(rec, req, s, "dune", auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!"))
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		highlight := doc.Find(".book-highlights li")
+		if highlight.Length() != 1 {
+			t.Fatalf("expected one highlight, got %d", highlight.Length())
+		}
+
+		if got := highlight.Find(".highlight-meta").Text(); got != "location 110-112 · 2024-03-04" {
+			t.Errorf("expected the location and date, got %q", got)
+		}
+	})
+}
+
+func TestImportClippingsHandler(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+	s := testSetup(t)
+
+	clippings := "Test Book: Second Edition (Test Author)\n" +
+		"- Your Highlight on Location 10-12 | Added on Monday, March 4, 2024 10:15:32 PM\n" +
+		"\n" +
+		"A line worth keeping.\n" +
+		"==========\n" +
+		"Test Book: Second Edition (Test Author)\n" +
+		"- Your Note on Location 900 | Added on Monday, March 4, 2024 10:20:00 PM\n" +
+		"\n" +
+		"A stray thought.\n" +
+		"==========\n"
+
+	request := func(action string) *http.Request {
+		var body bytes.Buffer
+
+		writer := multipart.NewWriter(&body)
+
+		err := writer.WriteField("action", action)
+		if err != nil {
+			t.Fatalf("failed to write field: %v", err)
+		}
+
+		part, err := writer.CreateFormFile("clippings-file", "My Clippings.txt")
+		if err != nil {
+			t.Fatalf("failed to create part: %v", err)
+		}
+
+		_, err = part.Write([]byte(clippings))
+		if err != nil {
+			t.Fatalf("failed to write part: %v", err)
+		}
+
+		err = writer.Close()
+		if err != nil {
+			t.Fatalf("failed to close writer: %v", err)
+		}
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/admin/clippings", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		addAuthCookie(req)
+
+		return req
+	}
+
+	rec := httptest.NewRecorder()
+	web.ImportClippingsHandler(rec, request("preview"), s, a)
+
+	if !strings.Contains(rec.Body.String(), "1 new highlights would be added to 1 books") {
+		t.Errorf("expected the preview to count the match, got %s", rec.Body.String())
+	}
+
+	if !strings.Contains(rec.Body.String(), "Test Book: A stray thought.") {
+		t.Errorf("expected the preview to report the note left out, got %s", rec.Body.String())
+	}
+
+	book, err := service.GetBookBySlug(context.Background(), s, "test-book")
+	if err != nil || len(book.Highlights) != 0 {
+		t.Fatalf("expected a preview to write nothing: %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	web.ImportClippingsHandler(rec, request("import"), s, a)
+
+	book, err = service.GetBookBySlug(context.Background(), s, "test-book")
+	if err != nil || len(book.Highlights) != 1 {
+		t.Fatalf("expected the highlight to be added, got %+v: %v", book, err)
+	}
+}
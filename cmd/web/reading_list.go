@@ -3,6 +3,9 @@ package web
 import (
 	"net/http"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	apperrors "timterests/internal/errors"
 
@@ -14,7 +17,9 @@ import (
 	"github.com/a-h/templ"
 )
 
-func ReadingListPageHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, currentTag, design string) {
+// ReadingListPageHandler renders the reading list, filtered by tag and reading
+// state and ordered by sortBy; see service.SortBooks.
+func ReadingListPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, currentTag, state, sortBy, design string) {
 	var (
 		component templ.Component
 		tags      []string
@@ -27,17 +32,24 @@ func ReadingListPageHandler(w http.ResponseWriter, r *http.Request, s storage.St
 		return
 	}
 
+	books = service.Published(books, time.Now())
+
+	// Tags are gathered before the state filter so switching state never
+	// empties the tag picker.
 	for i := range books {
 		v := reflect.ValueOf(books[i])
 		tags = storage.GetTags(v, tags)
 	}
 
+	books = service.FilterBooksByState(books, state)
+	service.SortBooks(books, sortBy)
+
 	if IsHTMXRequest(r) {
 		SetPartialResponseHeaders(w)
 
 		component = ReadingListList(books, design)
 	} else {
-		component = ReadingListPage(books, tags, design)
+		component = ReadingListPage(books, tags, state, sortBy, design)
 	}
 
 	err = renderHTML(w, r, http.StatusOK, component)
@@ -46,51 +58,97 @@ func ReadingListPageHandler(w http.ResponseWriter, r *http.Request, s storage.St
 	}
 }
 
-func GetReadingListBook(w http.ResponseWriter, r *http.Request, s storage.Storage, bookID string, a *auth.Auth) {
-	books, err := service.ListBooks(r.Context(), s, "all")
+func GetReadingListBook(w http.ResponseWriter, r *http.Request, s storage.Backend, slug string, a *auth.Auth) {
+	book, err := service.GetBookBySlug(r.Context(), s, slug)
 	if err != nil {
-		HandleError(w, r, apperrors.StorageFailed(err), "GetReadingListBook", "listBooks")
+		HandleError(w, r, lookupError(err), "GetReadingListBook", "getBook")
 
 		return
 	}
 
-	for _, book := range books {
-		if book.ID == bookID {
-			body, err := s.GetDocumentBody(r.Context(), book.S3Key)
-			if err != nil {
-				HandleError(w, r, apperrors.NotFound(err), "GetReadingListBook", "getBody")
+	authenticated := a.IsAuthenticated(r)
 
-				return
-			}
+	// Drafts and scheduled documents are only reachable by the author.
+	if !book.IsPublished(time.Now()) && !authenticated {
+		HandleError(w, r, apperrors.NotFound(nil), "GetReadingListBook", "checkPublished")
 
-			dc := model.DisplayContent{
-				ID:    book.ID,
-				S3Key: book.S3Key,
-				Body:  body,
-			}
+		return
+	}
+
+	body, err := storage.GetDocumentBody(r.Context(), s, book.S3Key)
+	if err != nil {
+		HandleError(w, r, apperrors.NotFound(err), "GetReadingListBook", "getBody")
+
+		return
+	}
+
+	dc := model.DisplayContent{
+		ID:        book.ID,
+		S3Key:     book.S3Key,
+		Body:      body,
+		Backlinks: backlinks(r.Context(), s, "reading-list", book.ID),
+	}
+
+	related := relatedCards(r.Context(), s, "reading-list", book.Document)
+
+	var component templ.Component
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
 
-			var component templ.Component
+		component = BookDisplay(*book, dc, related, authenticated)
+	} else {
+		component = BookPage(*book, dc, related, authenticated)
+	}
 
-			authenticated := a.IsAuthenticated(r)
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "GetReadingListBook", "render")
+	}
+}
 
-			if IsHTMXRequest(r) {
-				SetPartialResponseHeaders(w)
+// ReadingStatsPageHandler renders the yearly reading stats: the books finished
+// each year, their pages and the tags read most.
+func ReadingStatsPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend) {
+	books, err := service.ListBooks(r.Context(), s, "all")
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "ReadingStatsPageHandler", "listBooks")
 
-				component = BookDisplay(book, dc, authenticated)
-			} else {
-				component = BookPage(book, dc, authenticated)
-			}
+		return
+	}
 
-			err = renderHTML(w, r, http.StatusOK, component)
-			if err != nil {
-				HandleError(w, r, apperrors.RenderFailed(err), "GetReadingListBook", "render")
+	books = service.Published(books, time.Now())
 
-				return
-			}
+	err = renderHTML(w, r, http.StatusOK, ReadingStatsPage(service.ReadingStats(books)))
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "ReadingStatsPageHandler", "render")
+	}
+}
 
-			return
-		}
+// readingStateLabel is how a reading state reads on the page.
+func readingStateLabel(state string) string {
+	switch state {
+	case model.ReadingWantToRead:
+		return "Want to read"
+	case model.ReadingReading:
+		return "Reading"
+	case model.ReadingFinished:
+		return "Finished"
+	case model.ReadingAbandoned:
+		return "Abandoned"
+	default:
+		return state
 	}
+}
+
+// ratingStars draws a rating as filled and empty stars, e.g. "★★★☆☆".
+func ratingStars(rating int) string {
+	rating = max(0, min(rating, model.MaxRating))
+
+	return strings.Repeat("★", rating) + strings.Repeat("☆", model.MaxRating-rating)
+}
 
-	HandleError(w, r, apperrors.NotFound(nil), "GetReadingListBook", "findBook")
+// averageRating formats a year's average rating to one decimal place.
+func averageRating(rating float64) string {
+	return strconv.FormatFloat(rating, 'f', 1, 64)
 }
@@ -8,12 +8,13 @@ import (
 	"timterests/cmd/web"
 	"timterests/internal/auth"
 	"timterests/internal/service"
+	"timterests/internal/storage"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
 func TestReadingListRendering(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	t.Run("render reading list page", func(t *testing.T) {
 		t.Parallel()
@@ -21,7 +22,7 @@ func TestReadingListRendering(t *testing.T) {
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/reading-list", nil)
 		rec := httptest.NewRecorder()
 
-		web.ReadingListPageHandler(rec, req, *s, "all", "list")
+		web.ReadingListPageHandler(rec, req, s, "all", "", "", "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -51,7 +52,7 @@ func TestReadingListRendering(t *testing.T) {
 		// Set the HX-Request header to trigger partial rendering
 		req.Header.Set("Hx-Request", "true")
 
-		web.ReadingListPageHandler(rec, req, *s, "all", "list")
+		web.ReadingListPageHandler(rec, req, s, "all", "", "", "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -78,7 +79,7 @@ func TestReadingListRendering(t *testing.T) {
 		rec := httptest.NewRecorder()
 
 		tag := "Data Structures"
-		web.ReadingListPageHandler(rec, req, *s, tag, "list")
+		web.ReadingListPageHandler(rec, req, s, tag, "", "", "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -114,7 +115,7 @@ func TestReadingListRendering(t *testing.T) {
 
 		// Enter a non-existent tag to get zero results back (filter all books).
 		tag := "non-existent-tag"
-		web.ReadingListPageHandler(rec, req, *s, tag, "list")
+		web.ReadingListPageHandler(rec, req, s, tag, "", "", "list")
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -132,16 +133,16 @@ func TestReadingListRendering(t *testing.T) {
 }
 
 func TestBookRendering(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 
 	// Create auth instance for tests (won't be authenticated but prevents nil pointer)
 	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
 	t.Run("render book page", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/book?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/books/test-book", nil)
 		rec := httptest.NewRecorder()
 
-		web.GetReadingListBook(rec, req, *s, "0", a)
+		web.GetReadingListBook(rec, req, s, "test-book", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -169,13 +170,13 @@ func TestBookRendering(t *testing.T) {
 	})
 
 	t.Run("render book display only", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/book?id=0", nil)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/books/test-book", nil)
 		rec := httptest.NewRecorder()
 
 		// Set HTMX header for partial rendering
 		req.Header.Set("Hx-Request", "true")
 
-		web.GetReadingListBook(rec, req, *s, "0", a)
+		web.GetReadingListBook(rec, req, s, "test-book", a)
 
 		doc, err := goquery.NewDocumentFromReader(rec.Body)
 		if err != nil {
@@ -204,14 +205,14 @@ func TestBookRendering(t *testing.T) {
 }
 
 func TestGetBookNotFound(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
-	t.Run("returns 404 for non-existent book ID", func(t *testing.T) {
-		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/book?id=non-existent-id", nil)
+	t.Run("returns 404 for non-existent book slug", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/books/does-not-exist", nil)
 		rec := httptest.NewRecorder()
 
-		web.GetReadingListBook(rec, req, *s, "non-existent-id", a)
+		web.GetReadingListBook(rec, req, s, "does-not-exist", a)
 
 		if rec.Code != http.StatusNotFound {
 			t.Errorf("expected status 404, got %d", rec.Code)
@@ -221,12 +222,12 @@ func TestGetBookNotFound(t *testing.T) {
 
 func TestBookCardConversion(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("book to card conversion", func(t *testing.T) {
 		testBookPath := "reading-list/test-book.yaml"
 
-		book, err := service.GetBook(ctx, *s, testBookPath, 1)
+		book, err := service.GetBook(ctx, s, testBookPath)
 		if err != nil {
 			t.Fatalf("failed to get book: %v", err)
 		}
@@ -241,8 +242,8 @@ func TestBookCardConversion(t *testing.T) {
 			t.Errorf("expected card subtitle %q, got %q", book.Subtitle, card.Subtitle)
 		}
 
-		if card.Get != "/book?id=1" {
-			t.Errorf("expected card get URL '/book?id=1', got %q", card.Get)
+		if card.Get != "/books/test-book" {
+			t.Errorf("expected card get URL '/books/test-book', got %q", card.Get)
 		}
 
 		// Books should have ImagePath but no Date
@@ -259,3 +260,94 @@ func TestBookCardConversion(t *testing.T) {
 		}
 	})
 }
+
+func TestReadingListProgress(t *testing.T) {
+	s := testSetup(t)
+
+	seed := map[string]string{
+		"in-progress": "title: In Progress\nauthor: A\nreadingStatus: reading\nprogress: 30\nrating: 0\n",
+		"loved":       "title: Loved\nauthor: B\nreadingStatus: finished\nfinished: \"2025-06-01\"\npages: 320\nrating: 5\ntags: [Fiction]\n",
+	}
+	for slug, meta := range seed {
+		err := storage.WriteDocument(context.Background(), s, "reading-list/"+slug+".yaml", []byte(meta), []byte("Body\n"))
+		if err != nil {
+			t.Fatalf("failed to seed %s: %v", slug, err)
+		}
+	}
+
+	listTitles := func(t *testing.T, state, sortBy string) []string {
+		t.Helper()
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/reading-list", nil)
+		req.Header.Set("Hx-Request", "true")
+
+		rec := httptest.NewRecorder()
+
+		web.ReadingListPageHandler(rec, req, s, "all", state, sortBy, "list")
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		var titles []string
+
+		doc.Find(".card-title").Each(func(_ int, sel *goquery.Selection) {
+			titles = append(titles, sel.Text())
+		})
+
+		return titles
+	}
+
+	t.Run("filters by reading status", func(t *testing.T) {
+		titles := listTitles(t, "reading", "")
+		if len(titles) != 1 || titles[0] != "In Progress" {
+			t.Errorf("expected only the book being read, got %v", titles)
+		}
+	})
+
+	t.Run("sorts by rating", func(t *testing.T) {
+		titles := listTitles(t, "all", "rating")
+		if len(titles) == 0 || titles[0] != "Loved" {
+			t.Errorf("expected the best-rated book first, got %v", titles)
+		}
+	})
+
+	t.Run("shows progress on the book page", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/books/in-progress", nil)
+		rec := httptest.NewRecorder()
+
+		web.GetReadingListBook(rec, req, s, "in-progress", auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!"))
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		if got := doc.Find("progress.reading-progress").AttrOr("value", ""); got != "30" {
+			t.Errorf("expected progress 30, got %q", got)
+		}
+	})
+
+	t.Run("summarises each year on the stats page", func(t *testing.T) {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/reading-list/stats", nil)
+		rec := httptest.NewRecorder()
+
+		web.ReadingStatsPageHandler(rec, req, s)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		rows := doc.Find("table.reading-stats tbody tr")
+		if rows.Length() != 1 {
+			t.Fatalf("expected one year, got %d", rows.Length())
+		}
+
+		cells := rows.First().Find("th, td")
+		if cells.Eq(0).Text() != "2025" || cells.Eq(1).Text() != "1" || cells.Eq(2).Text() != "320" {
+			t.Errorf("expected 2025 with 1 book and 320 pages, got %q", cells.Text())
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"timterests/cmd/web/components"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// relatedCards suggests what to read after doc as cards. Suggestions are a
+// nicety, so a failure costs the page its suggestions rather than the page.
+func relatedCards(ctx context.Context, s storage.Backend, docType string, doc model.Document) []components.Card {
+	related, err := service.RelatedDocuments(ctx, s, docType, doc, time.Now())
+	if err != nil {
+		log.Printf("related: failed to find documents related to %s/%s: %v", docType, doc.ID, err)
+
+		return nil
+	}
+
+	cards := make([]components.Card, 0, len(related))
+
+	for _, r := range related {
+		card, err := documentCard(ctx, s, r.DocType, r.Slug)
+		if err != nil {
+			log.Printf("related: failed to load %s/%s: %v", r.DocType, r.Slug, err)
+
+			continue
+		}
+
+		cards = append(cards, card)
+	}
+
+	return cards
+}
+
+// backlinks lists the documents linking to the document of docType with slug.
+// Like suggestions, they are left out rather than failing the page.
+func backlinks(ctx context.Context, s storage.Backend, docType, slug string) []model.DocumentLink {
+	links, err := service.Backlinks(ctx, s, docType, slug, time.Now())
+	if err != nil {
+		log.Printf("backlinks: failed to find links to %s/%s: %v", docType, slug, err)
+
+		return nil
+	}
+
+	return links
+}
+
+// documentCard loads the document of docType with slug as a card. A type the
+// site does not serve has no card.
+func documentCard(ctx context.Context, s storage.Backend, docType, slug string) (components.Card, error) {
+	switch docType {
+	case "articles":
+		article, err := service.GetArticleBySlug(ctx, s, slug)
+		if err != nil {
+			return components.Card{}, err
+		}
+
+		return ArticleCard(*article), nil
+	case "projects":
+		project, err := service.GetProjectBySlug(ctx, s, slug)
+		if err != nil {
+			return components.Card{}, err
+		}
+
+		return ProjectCard(*project), nil
+	case "reading-list":
+		book, err := service.GetBookBySlug(ctx, s, slug)
+		if err != nil {
+			return components.Card{}, err
+		}
+
+		return BookCard(*book), nil
+	}
+
+	schema, ok := customType(docType)
+	if !ok {
+		return components.Card{}, fmt.Errorf("no cards for document type %q", docType)
+	}
+
+	doc, err := service.GetCustomBySlug(ctx, s, schema, slug)
+	if err != nil {
+		return components.Card{}, err
+	}
+
+	return CustomCard(*doc), nil
+}
@@ -2,6 +2,7 @@ package web
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -32,3 +33,23 @@ func renderHTML(w http.ResponseWriter, r *http.Request, status int, component te
 
 	return nil
 }
+
+// renderJSON encodes v as the JSON response with the given status code. Like
+// renderHTML it returns an error only if encoding fails, before anything is
+// written.
+func renderJSON(w http.ResponseWriter, status int, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	_, err = w.Write(body)
+	if err != nil {
+		log.Printf("renderJSON: failed to write response: %v", err)
+	}
+
+	return nil
+}
@@ -4,15 +4,14 @@ import (
 	"encoding/xml"
 	"log"
 	"net/http"
-	"strings"
 	"time"
-
-	"timterests/internal/service"
-	"timterests/internal/storage"
 )
 
+// rssChannel puts its atom:link self reference ahead of link, so a reader
+// matching on the local name alone still ends up with the site link.
 type rssChannel struct {
 	Title       string    `xml:"title"`
+	AtomLink    atomLink  `xml:"atom:link"`
 	Link        string    `xml:"link"`
 	Description string    `xml:"description"`
 	Language    string    `xml:"language"`
@@ -20,76 +19,68 @@ type rssChannel struct {
 }
 
 type rssFeed struct {
-	XMLName xml.Name   `xml:"rss"`
-	Version string     `xml:"version,attr"`
-	Channel rssChannel `xml:"channel"`
+	XMLName      xml.Name   `xml:"rss"`
+	Version      string     `xml:"version,attr"`
+	XMLNSAtom    string     `xml:"xmlns:atom,attr"`
+	XMLNSContent string     `xml:"xmlns:content,attr"`
+	Channel      rssChannel `xml:"channel"`
 }
 
 type rssItem struct {
-	Title       string `xml:"title"`
-	Link        string `xml:"link"`
-	Description string `xml:"description"`
-	PubDate     string `xml:"pubDate,omitempty"`
-	GUID        string `xml:"guid"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"content:encoded,omitempty"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	GUID        string   `xml:"guid"`
+	Categories  []string `xml:"category"`
 }
 
-// RSSHandler generates and serves an RSS 2.0 feed of articles.
-func RSSHandler(
-	w http.ResponseWriter,
-	r *http.Request,
-	s storage.Storage,
-) {
-	site := Site()
-	baseURL := strings.TrimRight(site.URL, "/")
-
-	articles, err := service.ListArticles(r.Context(), s, "all")
-	if err != nil {
-		log.Printf("RSSHandler: failed to list articles: %v", err)
-		http.Error(w, "internal error", http.StatusInternalServerError)
-
-		return
-	}
-
-	items := make([]rssItem, 0, len(articles))
-
-	for _, a := range articles {
-		desc := a.Subtitle
-		if desc == "" {
-			desc = a.Preview
-		}
+// writeRSS encodes f as RSS 2.0, with the full content in content:encoded.
+func writeRSS(w http.ResponseWriter, f feed) {
+	items := make([]rssItem, 0, len(f.Entries))
 
+	for _, e := range f.Entries {
 		var pubDate string
-
-		t, err := time.Parse("2006-01-02", a.Date)
-		if err == nil {
-			pubDate = t.Format(time.RFC1123Z)
+		if !e.Published.IsZero() {
+			pubDate = e.Published.Format(time.RFC1123Z)
 		}
 
 		items = append(items, rssItem{
-			Title:       a.Title,
-			Link:        baseURL + "/article?id=" + a.ID,
-			Description: desc,
+			Title:       e.Title,
+			Link:        e.URL,
+			Description: e.Summary,
+			Content:     e.Content,
 			PubDate:     pubDate,
-			GUID:        baseURL + "/article?id=" + a.ID,
+			GUID:        e.URL,
+			Categories:  e.Tags,
 		})
 	}
 
-	feed := rssFeed{
-		Version: "2.0",
+	rss := rssFeed{
+		Version:      "2.0",
+		XMLNSAtom:    atomNamespace,
+		XMLNSContent: "http://purl.org/rss/1.0/modules/content/",
 		Channel: rssChannel{
-			Title:       site.Name,
-			Link:        baseURL,
-			Description: site.Description,
+			Title:       f.Title,
+			Link:        f.HomeURL,
+			Description: f.Description,
 			Language:    "en",
+			AtomLink:    atomLink{Rel: "self", Type: "application/rss+xml", Href: f.FeedURLs[FeedRSS]},
 			Items:       items,
 		},
 	}
 
-	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	writeXMLFeed(w, "application/rss+xml; charset=utf-8", rss)
+}
+
+// writeXMLFeed writes an XML feed document of contentType.
+func writeXMLFeed(w http.ResponseWriter, contentType string, doc any) {
+	w.Header().Set("Content-Type", contentType)
 
-	_, err = w.Write([]byte(xml.Header))
+	_, err := w.Write([]byte(xml.Header))
 	if err != nil {
-		log.Printf("RSSHandler: failed to write XML header: %v", err)
+		log.Printf("feeds: failed to write XML header: %v", err)
 
 		return
 	}
@@ -97,8 +88,8 @@ func RSSHandler(
 	enc := xml.NewEncoder(w)
 	enc.Indent("", "  ")
 
-	err = enc.Encode(feed)
+	err = enc.Encode(doc)
 	if err != nil {
-		log.Printf("RSSHandler: failed to encode RSS feed: %v", err)
+		log.Printf("feeds: failed to encode feed: %v", err)
 	}
 }
@@ -13,7 +13,7 @@ import (
 )
 
 func TestRSSHandler(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	t.Setenv("SITE_URL", "https://example.com")
 	t.Setenv("SITE_NAME", "TestBlog")
 
@@ -22,7 +22,7 @@ func TestRSSHandler(t *testing.T) {
 	)
 	rec := httptest.NewRecorder()
 
-	web.RSSHandler(rec, req, *s)
+	web.RSSHandler(rec, req, s)
 
 	if rec.Code != http.StatusOK {
 		t.Errorf("expected status 200, got %d", rec.Code)
@@ -94,7 +94,7 @@ func TestRSSHandler(t *testing.T) {
 }
 
 func TestRSSHandlerTrailingSlash(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	t.Setenv("SITE_URL", "https://example.com/")
 
 	req := httptest.NewRequestWithContext(
@@ -102,7 +102,7 @@ func TestRSSHandlerTrailingSlash(t *testing.T) {
 	)
 	rec := httptest.NewRecorder()
 
-	web.RSSHandler(rec, req, *s)
+	web.RSSHandler(rec, req, s)
 
 	body := rec.Body.String()
 	if strings.Contains(body, "https://example.com//") {
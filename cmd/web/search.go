@@ -0,0 +1,58 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	apperrors "timterests/internal/errors"
+
+	"timterests/internal/auth"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+
+	"github.com/a-h/templ"
+)
+
+// searchTypeLabels names a single document of each type in search results.
+var searchTypeLabels = map[string]string{
+	"articles":     "Article",
+	"projects":     "Project",
+	"reading-list": "Book",
+	"letters":      "Letter",
+}
+
+// SearchPageHandler serves /search. HTMX requests from the search box get just
+// the results list, so results update as the visitor types.
+func SearchPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, query string, a *auth.Auth) {
+	query = strings.TrimSpace(query)
+
+	results, err := service.Search(r.Context(), s, query, a.IsAuthenticated(r))
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "SearchPageHandler", "search")
+
+		return
+	}
+
+	var component templ.Component
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
+
+		component = SearchResults(query, results)
+	} else {
+		component = SearchPage(query, results)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "SearchPageHandler", "render")
+	}
+}
+
+func resultNoun(n int) string {
+	if n == 1 {
+		return "result"
+	}
+
+	return "results"
+}
@@ -0,0 +1,92 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestSearchPageHandler(t *testing.T) {
+	s := testSetup(t)
+
+	t.Run("renders the full page with highlighted results", func(t *testing.T) {
+		a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/search?q=article", nil)
+		rec := httptest.NewRecorder()
+
+		web.SearchPageHandler(rec, req, s, "article", a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		if doc.Find("title").Length() == 0 {
+			t.Error("expected title element to be rendered, but it wasn't")
+		}
+
+		href, _ := doc.Find("a.search-result-link").First().Attr("href")
+		if href != "/articles/test-article" {
+			t.Errorf("expected first result to link to /articles/test-article, got %q", href)
+		}
+
+		if doc.Find("li.search-result mark").Length() == 0 {
+			t.Error("expected matches to be highlighted, but none were")
+		}
+	})
+
+	t.Run("renders only the results for HTMX requests", func(t *testing.T) {
+		a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/search?q=test", nil)
+		req.Header.Set("Hx-Request", "true")
+
+		rec := httptest.NewRecorder()
+
+		web.SearchPageHandler(rec, req, s, "test", a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to read template: %v", err)
+		}
+
+		if doc.Find("title").Length() > 0 {
+			t.Error("expected title element to not be rendered, but it was")
+		}
+
+		if doc.Find("#search-results").Length() == 0 {
+			t.Error("expected search results to be rendered, but they weren't")
+		}
+	})
+
+	t.Run("includes letters only when signed in", func(t *testing.T) {
+		a, addAuthCookie := testAuthentication(t)
+
+		for _, signedIn := range []bool{false, true} {
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/search?q=letter", nil)
+			if signedIn {
+				addAuthCookie(req)
+			}
+
+			rec := httptest.NewRecorder()
+
+			web.SearchPageHandler(rec, req, s, "letter", a)
+
+			doc, err := goquery.NewDocumentFromReader(rec.Body)
+			if err != nil {
+				t.Fatalf("failed to read template: %v", err)
+			}
+
+			found := doc.Find(`a[href="/letters/test-letter"]`).Length() > 0
+			if found != signedIn {
+				t.Errorf("signed in %v: expected letter listed %v, got %v", signedIn, signedIn, found)
+			}
+		}
+	})
+}
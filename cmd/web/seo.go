@@ -1,27 +1,67 @@
 package web
 
 import (
+	"cmp"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 
+	"timterests/internal/model"
 	"timterests/internal/service"
 	"timterests/internal/storage"
 )
 
+// sitemapMaxURLs is how many URLs /sitemap.xml lists itself before it becomes
+// an index of per-section sitemaps, unless SITEMAP_MAX_URLS says otherwise.
+const sitemapMaxURLs = 1000
+
+// sitemapPages is the section holding the site's listing pages. The other
+// sections are named after their types, and a type name cannot begin with an
+// underscore, so the two can never clash.
+const sitemapPages = "_pages"
+
 type urlSet struct {
-	XMLName xml.Name     `xml:"urlset"`
-	XMLNS   string       `xml:"xmlns,attr"`
-	URLs    []sitemapURL `xml:"url"`
+	XMLName    xml.Name     `xml:"urlset"`
+	XMLNS      string       `xml:"xmlns,attr"`
+	XMLNSImage string       `xml:"xmlns:image,attr"`
+	URLs       []sitemapURL `xml:"url"`
 }
 
 type sitemapURL struct {
-	Loc        string `xml:"loc"`
-	LastMod    string `xml:"lastmod,omitempty"`
-	ChangeFreq string `xml:"changefreq,omitempty"`
-	Priority   string `xml:"priority,omitempty"`
+	Loc        string         `xml:"loc"`
+	LastMod    string         `xml:"lastmod,omitempty"`
+	ChangeFreq string         `xml:"changefreq,omitempty"`
+	Priority   string         `xml:"priority,omitempty"`
+	Images     []sitemapImage `xml:"image:image"`
+
+	modified time.Time
+}
+
+type sitemapImage struct {
+	Loc string `xml:"image:loc"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapRef `xml:"sitemap"`
+}
+
+type sitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// sitemapSection is the part of the sitemap covering one document type, or the
+// listing pages.
+type sitemapSection struct {
+	Name string
+	URLs []sitemapURL
 }
 
 // RobotsHandler serves robots.txt.
@@ -45,70 +85,247 @@ func RobotsHandler(w http.ResponseWriter, _ *http.Request) {
 	}
 }
 
-// SitemapHandler generates and serves sitemap.xml.
-func SitemapHandler(w http.ResponseWriter, r *http.Request, s storage.Storage) {
-	baseURL := Site().URL
-	now := time.Now().Format("2006-01-02")
+// SitemapHandler serves sitemap.xml: every published page, or once there are
+// more than SITEMAP_MAX_URLS of them, an index of the per-section sitemaps
+// SitemapSectionHandler serves. Drafts, scheduled documents and letters are
+// never listed.
+func SitemapHandler(w http.ResponseWriter, r *http.Request, s storage.Backend) {
+	sections := sitemapSections(r.Context(), s)
+
+	var urls []sitemapURL
+	for _, section := range sections {
+		urls = append(urls, section.URLs...)
+	}
+
+	if len(urls) <= maxSitemapURLs() {
+		writeSitemap(w, urlSet{
+			XMLNS:      sitemapNamespace,
+			XMLNSImage: sitemapImageNamespace,
+			URLs:       urls,
+		})
 
-	staticPages := []sitemapURL{
-		{Loc: baseURL + "/", Priority: "1.0", ChangeFreq: "weekly", LastMod: now},
-		{Loc: baseURL + "/articles", Priority: "0.9", ChangeFreq: "weekly", LastMod: now},
-		{Loc: baseURL + "/projects", Priority: "0.9", ChangeFreq: "monthly", LastMod: now},
-		{Loc: baseURL + "/reading-list", Priority: "0.7", ChangeFreq: "monthly", LastMod: now},
-		{Loc: baseURL + "/about", Priority: "0.8", ChangeFreq: "monthly", LastMod: now},
+		return
 	}
 
-	articles, err := service.ListArticles(r.Context(), s, "all")
+	index := sitemapIndex{XMLNS: sitemapNamespace}
+
+	for _, section := range sections {
+		index.Sitemaps = append(index.Sitemaps, sitemapRef{
+			Loc:     absoluteURL(SitemapSectionPath(section.Name)),
+			LastMod: sitemapDate(newestURL(section.URLs)),
+		})
+	}
+
+	writeSitemap(w, index)
+}
+
+// SitemapSectionHandler serves the sitemap of one section, the listing pages or
+// a document type, as linked from the sitemap index.
+func SitemapSectionHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, name string) {
+	sections := sitemapSections(r.Context(), s)
+
+	for _, section := range sections {
+		if section.Name == name {
+			writeSitemap(w, urlSet{
+				XMLNS:      sitemapNamespace,
+				XMLNSImage: sitemapImageNamespace,
+				URLs:       section.URLs,
+			})
+
+			return
+		}
+	}
+
+	http.NotFound(w, r)
+}
+
+// SitemapSectionPath is where the sitemap of the section called name is served.
+func SitemapSectionPath(name string) string {
+	return "/sitemaps/" + name + ".xml"
+}
+
+const (
+	sitemapNamespace      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	sitemapImageNamespace = "http://www.google.com/schemas/sitemap-image/1.1"
+)
+
+// maxSitemapURLs reads SITEMAP_MAX_URLS, falling back to sitemapMaxURLs.
+func maxSitemapURLs() int {
+	n, err := strconv.Atoi(os.Getenv("SITEMAP_MAX_URLS"))
+	if err != nil || n < 1 {
+		return sitemapMaxURLs
+	}
+
+	return n
+}
+
+// sitemapSections lists the published pages of the site, section by section:
+// the listing pages first, each dated by the newest document it shows. A type
+// that cannot be listed is logged and left out rather than failing the whole
+// sitemap.
+func sitemapSections(ctx context.Context, s storage.Backend) []sitemapSection {
+	now := time.Now()
+
+	articles, err := service.ListArticles(ctx, s, "all")
 	if err != nil {
 		log.Printf("SitemapHandler: failed to list articles: %v", err)
 	}
 
-	projects, err := service.ListProjects(r.Context(), s, "all")
+	projects, err := service.ListProjects(ctx, s, "all")
 	if err != nil {
 		log.Printf("SitemapHandler: failed to list projects: %v", err)
 	}
 
-	books, err := service.ListBooks(r.Context(), s, "all")
+	books, err := service.ListBooks(ctx, s, "all")
 	if err != nil {
 		log.Printf("SitemapHandler: failed to list books: %v", err)
 	}
 
-	urls := make([]sitemapURL, 0, len(staticPages)+len(articles)+len(projects)+len(books))
-	urls = append(urls, staticPages...)
+	articleURLs := sitemapURLs(ctx, s, "articles", service.Published(articles, now),
+		func(a model.Article) (model.Document, model.Date, []string) { return a.Document, a.Date, nil },
+		"yearly", "0.8")
 
-	for _, a := range articles {
-		urls = append(urls, sitemapURL{
-			Loc:        baseURL + "/article?id=" + a.ID,
-			LastMod:    a.Date,
-			ChangeFreq: "yearly",
-			Priority:   "0.8",
-		})
+	projectURLs := sitemapURLs(ctx, s, "projects", service.Published(projects, now),
+		func(p model.Project) (model.Document, model.Date, []string) {
+			return p.Document, cmp.Or(p.EndDate, p.StartDate), []string{p.Image}
+		},
+		"monthly", "0.7")
+
+	bookURLs := sitemapURLs(ctx, s, "reading-list", service.Published(books, now),
+		func(b model.ReadingList) (model.Document, model.Date, []string) {
+			return b.Document, cmp.Or(b.Finished, b.Started), []string{b.Image}
+		},
+		"yearly", "0.5")
+
+	sections := []sitemapSection{
+		{Name: "articles", URLs: articleURLs},
+		{Name: "projects", URLs: projectURLs},
+		{Name: "reading-list", URLs: bookURLs},
 	}
 
-	for _, p := range projects {
-		urls = append(urls, sitemapURL{
-			Loc:        baseURL + "/project?id=" + p.ID,
-			ChangeFreq: "monthly",
-			Priority:   "0.7",
-		})
+	for _, schema := range CustomTypes() {
+		docs, err := service.ListCustom(ctx, s, &schema, "all")
+		if err != nil {
+			log.Printf("SitemapHandler: failed to list %s: %v", schema.Name, err)
+		}
+
+		urls := sitemapURLs(ctx, s, schema.Name, service.Published(docs, now),
+			func(d model.CustomDocument) (model.Document, model.Date, []string) {
+				return d.Document, "", []string{d.Value(schema.Card.Image)}
+			},
+			"monthly", "0.6")
+
+		sections = append(sections, sitemapSection{Name: schema.Name, URLs: urls})
 	}
 
-	for _, b := range books {
-		urls = append(urls, sitemapURL{
-			Loc:        baseURL + "/book?id=" + b.ID,
-			ChangeFreq: "yearly",
-			Priority:   "0.5",
-		})
+	var aboutModified time.Time
+	for _, t := range sitemapModified(ctx, s, "about") {
+		aboutModified = latest(aboutModified, t)
+	}
+
+	newest := latest(newestURL(articleURLs), newestURL(projectURLs), newestURL(bookURLs))
+
+	pages := []sitemapURL{
+		{Loc: absoluteURL("/"), Priority: "1.0", ChangeFreq: "weekly", modified: newest},
+		{Loc: absoluteURL("/articles"), Priority: "0.9", ChangeFreq: "weekly", modified: newestURL(articleURLs)},
+		{Loc: absoluteURL("/projects"), Priority: "0.9", ChangeFreq: "monthly", modified: newestURL(projectURLs)},
+		{Loc: absoluteURL("/reading-list"), Priority: "0.7", ChangeFreq: "monthly", modified: newestURL(bookURLs)},
+		{Loc: absoluteURL("/about"), Priority: "0.8", ChangeFreq: "monthly", modified: aboutModified},
+	}
+
+	for i := range pages {
+		pages[i].LastMod = sitemapDate(pages[i].modified)
+	}
+
+	return append([]sitemapSection{{Name: sitemapPages, URLs: pages}}, sections...)
+}
+
+// sitemapURLs lists published documents of docType. Each is dated by whichever
+// is later, the date it carries or the last time its files were written, and
+// lists the images fields names.
+func sitemapURLs[T any](
+	ctx context.Context,
+	s storage.Backend,
+	docType string,
+	docs []T,
+	fields func(T) (model.Document, model.Date, []string),
+	changeFreq, priority string,
+) []sitemapURL {
+	modified := sitemapModified(ctx, s, docType)
+
+	urls := make([]sitemapURL, 0, len(docs))
+
+	for _, doc := range docs {
+		d, date, images := fields(doc)
+
+		u := sitemapURL{
+			Loc:        absoluteURL(DocumentURL(docType, d.ID)),
+			ChangeFreq: changeFreq,
+			Priority:   priority,
+			modified:   latest(date.Time(), modified[d.S3Key], modified[storage.BodyKey(d.S3Key)]),
+		}
+
+		u.LastMod = sitemapDate(u.modified)
+
+		for _, image := range images {
+			if image != "" {
+				u.Images = append(u.Images, sitemapImage{Loc: absoluteURL(image)})
+			}
+		}
+
+		urls = append(urls, u)
+	}
+
+	return urls
+}
+
+// sitemapModified is lastModified for the sitemap, where the dates are a
+// nicety: without them the pages are still listed, just undated.
+func sitemapModified(ctx context.Context, s storage.Backend, docType string) map[string]time.Time {
+	modified, err := lastModified(ctx, s, docType)
+	if err != nil {
+		log.Printf("SitemapHandler: failed to list %s files: %v", docType, err)
 	}
 
-	sitemap := urlSet{
-		XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9",
-		URLs:  urls,
+	return modified
+}
+
+// newestURL is when the most recently changed of urls changed.
+func newestURL(urls []sitemapURL) time.Time {
+	var newest time.Time
+	for _, u := range urls {
+		newest = latest(newest, u.modified)
 	}
 
+	return newest
+}
+
+// latest returns the latest of times.
+func latest(times ...time.Time) time.Time {
+	var t time.Time
+	for _, candidate := range times {
+		if candidate.After(t) {
+			t = candidate
+		}
+	}
+
+	return t
+}
+
+// sitemapDate formats t as a sitemap lastmod, leaving it out when unknown.
+func sitemapDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(model.DateLayout)
+}
+
+// writeSitemap writes a sitemap or sitemap index.
+func writeSitemap(w http.ResponseWriter, doc any) {
 	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
 
-	_, err = w.Write([]byte(xml.Header))
+	_, err := w.Write([]byte(xml.Header))
 	if err != nil {
 		log.Printf("SitemapHandler: failed to write XML header: %v", err)
 
@@ -118,7 +335,7 @@ func SitemapHandler(w http.ResponseWriter, r *http.Request, s storage.Storage) {
 	enc := xml.NewEncoder(w)
 	enc.Indent("", "  ")
 
-	err = enc.Encode(sitemap)
+	err = enc.Encode(doc)
 	if err != nil {
 		log.Printf("SitemapHandler: failed to encode sitemap: %v", err)
 	}
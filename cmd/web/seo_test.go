@@ -7,8 +7,10 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"timterests/cmd/web"
+	"timterests/internal/storage"
 )
 
 func TestRobotsHandler(t *testing.T) {
@@ -40,13 +42,13 @@ func TestRobotsHandler(t *testing.T) {
 }
 
 func TestSitemapHandler(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	t.Setenv("SITE_URL", "https://example.com")
 
 	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/sitemap.xml", nil)
 	rec := httptest.NewRecorder()
 
-	web.SitemapHandler(rec, req, *s)
+	web.SitemapHandler(rec, req, s)
 
 	if rec.Code != http.StatusOK {
 		t.Errorf("expected status 200, got %d", rec.Code)
@@ -87,13 +89,13 @@ func TestSitemapHandler(t *testing.T) {
 }
 
 func TestMetaTagsRendered(t *testing.T) {
-	s := testSetup(t, context.Background())
+	s := testSetup(t)
 	t.Setenv("SITE_URL", "https://example.com")
 
 	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/about", nil)
 	rec := httptest.NewRecorder()
 
-	web.AboutHandler(rec, req, *s)
+	web.AboutHandler(rec, req, s)
 
 	body := rec.Body.String()
 
@@ -113,3 +115,133 @@ func TestMetaTagsRendered(t *testing.T) {
 		}
 	}
 }
+
+type sitemapResult struct {
+	URLs []struct {
+		Loc     string   `xml:"loc"`
+		LastMod string   `xml:"lastmod"`
+		Images  []string `xml:"image>loc"`
+	} `xml:"url"`
+}
+
+func getSitemap(t *testing.T, handler http.HandlerFunc, out any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/sitemap.xml", nil)
+	rec := httptest.NewRecorder()
+
+	handler(rec, req)
+
+	if rec.Code == http.StatusOK {
+		err := xml.Unmarshal(rec.Body.Bytes(), out)
+		if err != nil {
+			t.Fatalf("failed to parse sitemap XML: %v", err)
+		}
+	}
+
+	return rec
+}
+
+func TestSitemapEntries(t *testing.T) {
+	s := testSetup(t)
+	t.Setenv("SITE_URL", "https://example.com")
+
+	err := storage.WriteDocument(context.Background(), s, "articles/draft-article.yaml",
+		[]byte("title: Draft Article\ndate: \"2026-02-01\"\nstatus: draft\n"), []byte("Draft body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	var result sitemapResult
+
+	getSitemap(t, func(w http.ResponseWriter, r *http.Request) { web.SitemapHandler(w, r, s) }, &result)
+
+	entries := make(map[string]int, len(result.URLs))
+	for i, u := range result.URLs {
+		entries[u.Loc] = i
+	}
+
+	for _, loc := range []string{
+		"https://example.com/articles/draft-article",
+		"https://example.com/letters/test-letter",
+	} {
+		if _, ok := entries[loc]; ok {
+			t.Errorf("expected %s to be left out", loc)
+		}
+	}
+
+	for _, u := range result.URLs {
+		if u.LastMod == "" {
+			t.Errorf("expected a lastmod for %s", u.Loc)
+
+			continue
+		}
+
+		_, err := time.Parse("2006-01-02", u.LastMod)
+		if err != nil {
+			t.Errorf("lastmod %q for %s is not a date: %v", u.LastMod, u.Loc, err)
+		}
+	}
+
+	article, ok := entries["https://example.com/articles/test-article"]
+	if !ok || result.URLs[article].LastMod < "2026-01-01" {
+		t.Errorf("expected the article dated no earlier than its date, got %+v", result.URLs)
+	}
+
+	if ok && result.URLs[entries["https://example.com/articles"]].LastMod != result.URLs[article].LastMod {
+		t.Error("expected the articles page dated by its newest article")
+	}
+
+	project, ok := entries["https://example.com/projects/test-project"]
+	if !ok || len(result.URLs[project].Images) != 1 ||
+		result.URLs[project].Images[0] != "https://example.com/storage/testdata/images/test.png" {
+		t.Errorf("expected the project image, got %+v", result.URLs)
+	}
+}
+
+func TestSitemapIndex(t *testing.T) {
+	s := testSetup(t)
+	t.Setenv("SITE_URL", "https://example.com")
+	t.Setenv("SITEMAP_MAX_URLS", "3")
+
+	var index struct {
+		XMLName  xml.Name `xml:"sitemapindex"`
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
+	}
+
+	getSitemap(t, func(w http.ResponseWriter, r *http.Request) { web.SitemapHandler(w, r, s) }, &index)
+
+	var locs []string
+	for _, sitemap := range index.Sitemaps {
+		locs = append(locs, sitemap.Loc)
+	}
+
+	want := []string{
+		"https://example.com/sitemaps/_pages.xml",
+		"https://example.com/sitemaps/articles.xml",
+		"https://example.com/sitemaps/projects.xml",
+		"https://example.com/sitemaps/reading-list.xml",
+	}
+	if strings.Join(locs, " ") != strings.Join(want, " ") {
+		t.Errorf("expected sitemaps %v, got %v", want, locs)
+	}
+
+	var projects sitemapResult
+
+	getSitemap(t, func(w http.ResponseWriter, r *http.Request) {
+		web.SitemapSectionHandler(w, r, s, "projects")
+	}, &projects)
+
+	if len(projects.URLs) != 3 {
+		t.Errorf("expected the three projects, got %d", len(projects.URLs))
+	}
+
+	rec := getSitemap(t, func(w http.ResponseWriter, r *http.Request) {
+		web.SitemapSectionHandler(w, r, s, "letters")
+	}, &projects)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected no sitemap of letters, got %d", rec.Code)
+	}
+}
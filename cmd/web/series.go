@@ -0,0 +1,39 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	apperrors "timterests/internal/errors"
+
+	"timterests/internal/service"
+	"timterests/internal/storage"
+
+	"github.com/a-h/templ"
+)
+
+// SeriesPageHandler renders the landing page of a series, listing its parts in
+// reading order.
+func SeriesPageHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, slug string) {
+	series, err := service.GetSeries(r.Context(), s, slug, time.Now())
+	if err != nil {
+		HandleError(w, r, lookupError(err), "SeriesPageHandler", "getSeries")
+
+		return
+	}
+
+	var component templ.Component
+
+	if IsHTMXRequest(r) {
+		SetPartialResponseHeaders(w)
+
+		component = SeriesParts(*series)
+	} else {
+		component = SeriesPage(*series)
+	}
+
+	err = renderHTML(w, r, http.StatusOK, component)
+	if err != nil {
+		HandleError(w, r, apperrors.RenderFailed(err), "SeriesPageHandler", "render")
+	}
+}
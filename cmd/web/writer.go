@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"timterests/internal/auth"
 	apperrors "timterests/internal/errors"
@@ -16,6 +20,7 @@ import (
 	"timterests/internal/storage"
 
 	"github.com/a-h/templ"
+	"gopkg.in/yaml.v2"
 )
 
 type WriterFormData struct {
@@ -23,9 +28,18 @@ type WriterFormData struct {
 	Body    string
 	DocType string
 	Fields  templ.Component
+	// SeriesNames lists the article series in use, for the series picker.
+	SeriesNames []string
 }
 
 func emptyFormData(docType string) WriterFormData {
+	schema, ok := customType(docType)
+	if ok {
+		doc := model.CustomDocument{Schema: schema}
+
+		return WriterFormData{Doc: doc.Document, DocType: docType, Fields: CustomFormContent(&doc)}
+	}
+
 	switch docType {
 	case "projects":
 		doc := model.Project{}
@@ -49,9 +63,8 @@ func emptyFormData(docType string) WriterFormData {
 func WriterPageHandler(
 	w http.ResponseWriter,
 	r *http.Request,
-	s storage.Storage,
+	s storage.Backend,
 	docType, key string,
-	typeID int,
 	a *auth.Auth) {
 	var (
 		data      WriterFormData
@@ -66,7 +79,7 @@ func WriterPageHandler(
 	}
 
 	if key != "" {
-		data, err = getTypeContentRaw(r.Context(), docType, key, typeID, s)
+		data, err = getTypeContentRaw(r.Context(), docType, key, s)
 		if err != nil {
 			HandleError(w, r, apperrors.StorageFailed(err), "WriterPageHandler", "loadDocument")
 
@@ -74,6 +87,17 @@ func WriterPageHandler(
 		}
 	} else {
 		data = emptyFormData(docType)
+		// Nothing new goes live until it is deliberately published.
+		data.Doc.Status = model.StatusDraft
+	}
+
+	if data.DocType == "articles" {
+		data.SeriesNames, err = service.SeriesNames(r.Context(), s)
+		if err != nil {
+			HandleError(w, r, apperrors.StorageFailed(err), "WriterPageHandler", "listSeries")
+
+			return
+		}
 	}
 
 	if IsHTMXRequest(r) && key == "" {
@@ -90,7 +114,7 @@ func WriterPageHandler(
 	}
 }
 
-func WriteDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Storage, a *auth.Auth) {
+func WriteDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Backend, a *auth.Auth) {
 	if !a.IsAuthenticated(r) {
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 
@@ -103,7 +127,7 @@ func WriteDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Stor
 		return
 	}
 
-	formData, s3Upload, err := extractFormData(r)
+	formData, err := extractFormData(r)
 	if err != nil {
 		HandleError(w, r, apperrors.ParseFormFailed(err), "WriteDocumentHandler", "extractForm")
 
@@ -117,51 +141,60 @@ func WriteDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Stor
 		return
 	}
 
-	slug, err := generateSlug(formData, docType)
+	err = validatePublication(formData)
 	if err != nil {
-		HandleError(w, r, apperrors.BadRequest(err), "WriteDocumentHandler", "generateSlug")
+		HandleError(w, r, apperrors.BadRequest(err), "WriteDocumentHandler", "validatePublication")
 
 		return
 	}
 
-	yamlFilename := docType + "/" + slug + ".yaml"
-	mdFilename := docType + "/" + slug + ".md"
-
-	yamlPath, err := storage.LocalPath(s.BaseDir, yamlFilename)
+	err = normalizeDates(formData, docType)
 	if err != nil {
-		HandleError(w, r, apperrors.BadRequest(err), "WriteDocumentHandler", "yamlPath")
+		HandleError(w, r, apperrors.BadRequest(err), "WriteDocumentHandler", "normalizeDates")
 
 		return
 	}
 
-	mdPath, err := storage.LocalPath(s.BaseDir, mdFilename)
+	schema, ok := customType(docType)
+	if ok {
+		coerceCustomFields(formData, schema)
+	}
+
+	if docType == "reading-list" {
+		err = coerceBookFields(formData)
+		if err != nil {
+			HandleError(w, r, apperrors.BadRequest(err), "WriteDocumentHandler", "validateReading")
+
+			return
+		}
+	}
+
+	slug, err := generateSlug(formData, docType)
 	if err != nil {
-		HandleError(w, r, apperrors.BadRequest(err), "WriteDocumentHandler", "mdPath")
+		HandleError(w, r, apperrors.BadRequest(err), "WriteDocumentHandler", "generateSlug")
 
 		return
 	}
 
-	err = storage.WriteMarkdownDocument(yamlPath, mdPath, formData)
+	err = carryOverFields(r.Context(), s, docType+"/"+slug+".yaml", formData, carriedFields[docType])
 	if err != nil {
-		HandleError(w, r, apperrors.StorageFailed(err), "WriteDocumentHandler", "writeDocument")
+		HandleError(w, r, apperrors.StorageFailed(err), "WriteDocumentHandler", "carryOverFields")
 
 		return
 	}
 
-	if s3Upload {
-		err = s.UploadFileToS3(r.Context(), yamlFilename)
-		if err != nil {
-			HandleError(w, r, apperrors.StorageFailed(err), "WriteDocumentHandler", "uploadYaml")
+	yamlBytes, mdBytes, err := storage.MarshalMarkdownDocument(formData)
+	if err != nil {
+		HandleError(w, r, apperrors.BadRequest(err), "WriteDocumentHandler", "marshalDocument")
 
-			return
-		}
+		return
+	}
 
-		err = s.UploadFileToS3(r.Context(), mdFilename)
-		if err != nil {
-			HandleError(w, r, apperrors.StorageFailed(err), "WriteDocumentHandler", "uploadMd")
+	err = storage.WriteDocument(r.Context(), s, docType+"/"+slug+".yaml", yamlBytes, mdBytes)
+	if err != nil {
+		HandleError(w, r, apperrors.StorageFailed(err), "WriteDocumentHandler", "writeDocument")
 
-			return
-		}
+		return
 	}
 
 	http.Redirect(w, r, "/writer", http.StatusSeeOther)
@@ -170,16 +203,16 @@ func WriteDocumentHandler(w http.ResponseWriter, r *http.Request, s storage.Stor
 func loadRawDoc[T any, PT interface {
 	*T
 	model.MetaSetter
-}](ctx context.Context, key, idStr string, s storage.Storage) (model.Content[T], error) {
+}](ctx context.Context, key string, s storage.Backend) (model.Content[T], error) {
 	var doc T
-	PT(&doc).SetMeta(idStr, key)
+	PT(&doc).SetMeta(storage.SlugFromKey(key), key)
 
-	err := s.GetPreparedFile(ctx, key, PT(&doc))
+	err := storage.GetPreparedFile(ctx, s, key, PT(&doc))
 	if err != nil {
 		return model.Content[T]{}, fmt.Errorf("failed to get raw file: %w", err)
 	}
 
-	body, err := s.GetDocumentBodyRaw(ctx, key)
+	body, err := storage.GetDocumentBodyRaw(ctx, s, key)
 	if err != nil {
 		log.Printf("loadRawDoc: failed to get raw body, leaving empty: %v", err)
 
@@ -206,26 +239,24 @@ func StripDocumentHeaders(raw string) string {
 	return raw
 }
 
-func getTypeContentRaw(ctx context.Context, docType, key string, id int, s storage.Storage) (WriterFormData, error) {
-	idStr := strconv.Itoa(id)
-
+func getTypeContentRaw(ctx context.Context, docType, key string, s storage.Backend) (WriterFormData, error) {
 	switch docType {
 	case "articles":
-		c, err := loadRawDoc[model.Article](ctx, key, idStr, s)
+		c, err := loadRawDoc[model.Article](ctx, key, s)
 		if err != nil {
 			return WriterFormData{}, err
 		}
 
 		return WriterFormData{Doc: c.Doc.Document, Body: c.Body, DocType: "articles", Fields: ArticleFormContent(&c.Doc)}, nil
 	case "projects":
-		c, err := loadRawDoc[model.Project](ctx, key, idStr, s)
+		c, err := loadRawDoc[model.Project](ctx, key, s)
 		if err != nil {
 			return WriterFormData{}, err
 		}
 
 		return WriterFormData{Doc: c.Doc.Document, Body: c.Body, DocType: "projects", Fields: ProjectFormContent(&c.Doc)}, nil
 	case "reading-list":
-		c, err := loadRawDoc[model.ReadingList](ctx, key, idStr, s)
+		c, err := loadRawDoc[model.ReadingList](ctx, key, s)
 		if err != nil {
 			return WriterFormData{}, err
 		}
@@ -233,26 +264,35 @@ func getTypeContentRaw(ctx context.Context, docType, key string, id int, s stora
 		return WriterFormData{Doc: c.Doc.Document, Body: c.Body, DocType: "reading-list",
 			Fields: BookFormContent(&c.Doc)}, nil
 	case "letters":
-		c, err := loadRawDoc[model.Letter](ctx, key, idStr, s)
+		c, err := loadRawDoc[model.Letter](ctx, key, s)
 		if err != nil {
 			return WriterFormData{}, err
 		}
 
 		return WriterFormData{Doc: c.Doc.Document, Body: c.Body, DocType: "letters", Fields: LetterFormContent(&c.Doc)}, nil
 	default:
-		return WriterFormData{}, fmt.Errorf("unsupported document type: %s", docType)
+		schema, ok := customType(docType)
+		if !ok {
+			return WriterFormData{}, fmt.Errorf("unsupported document type: %s", docType)
+		}
+
+		c, err := loadRawDoc[model.CustomDocument](ctx, key, s)
+		if err != nil {
+			return WriterFormData{}, err
+		}
+
+		c.Doc.Schema = schema
+
+		return WriterFormData{Doc: c.Doc.Document, Body: c.Body, DocType: docType, Fields: CustomFormContent(&c.Doc)}, nil
 	}
 }
 
-func extractFormData(r *http.Request) (map[string]any, bool, error) {
+func extractFormData(r *http.Request) (map[string]any, error) {
 	err := r.ParseForm()
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to parse form: %w", err)
+		return nil, fmt.Errorf("failed to parse form: %w", err)
 	}
 
-	s3Upload := r.FormValue("s3-upload") == "on"
-	delete(r.Form, "s3-upload")
-
 	formData := make(map[string]any)
 
 	for key, values := range r.Form {
@@ -263,12 +303,33 @@ func extractFormData(r *http.Request) (map[string]any, bool, error) {
 			}
 
 			formData[key] = tags
+		} else if key == "related" {
+			// Empty means the suggestions are worked out, so nothing is stored.
+			related := strings.FieldsFunc(values[0], func(r rune) bool {
+				return r == ',' || unicode.IsSpace(r)
+			})
+			if len(related) > 0 {
+				formData[key] = related
+			}
+		} else if key == "seriesOrder" {
+			// Stored as a number, so the metadata decodes into the int field.
+			// Left empty, the article simply has no place in the order.
+			if values[0] == "" {
+				continue
+			}
+
+			order, err := strconv.Atoi(values[0])
+			if err != nil || order < 1 {
+				return nil, fmt.Errorf("invalid series part %q: want a whole number from 1", values[0])
+			}
+
+			formData[key] = order
 		} else if len(values) > 0 {
 			formData[key] = values[0]
 		}
 	}
 
-	return formData, s3Upload, nil
+	return formData, nil
 }
 
 func extractDocType(formData map[string]any) (string, error) {
@@ -287,6 +348,183 @@ func extractDocType(formData map[string]any) (string, error) {
 	return docType, nil
 }
 
+// validatePublication rejects a status or publishAt that IsPublished would not
+// understand, before anything is written.
+func validatePublication(formData map[string]any) error {
+	status, _ := formData["status"].(string)
+	publishAt, _ := formData["publishAt"].(string)
+
+	doc := model.Document{Status: status, PublishAt: publishAt}
+
+	return doc.ValidatePublication()
+}
+
+// carriedFields are the metadata fields of each document type that the writer
+// form does not edit, such as a book's imported highlights. Saving from the
+// writer keeps them rather than dropping them.
+var carriedFields = map[string][]string{
+	"reading-list": {"highlights"},
+}
+
+// carryOverFields copies the named fields from the document already stored at
+// key into formData, unless the form set them. A new document has nothing to
+// carry over.
+func carryOverFields(ctx context.Context, s storage.Backend, key string, formData map[string]any, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	metaBytes, _, err := storage.ReadDocument(ctx, s, key)
+	if err != nil {
+		return err
+	}
+
+	if metaBytes == nil {
+		return nil
+	}
+
+	var stored map[string]any
+
+	err = yaml.Unmarshal(metaBytes, &stored)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+
+	for _, name := range names {
+		value, ok := stored[name]
+		_, set := formData[name]
+
+		if ok && !set {
+			formData[name] = value
+		}
+	}
+
+	return nil
+}
+
+// normalizeDates rewrites the date fields of docType in formData to ISO 8601,
+// whichever accepted format they were typed in, and rejects any that are not
+// dates. Empty dates are left as they are.
+func normalizeDates(formData map[string]any, docType string) error {
+	var names []string
+
+	schema, ok := customType(docType)
+	if ok {
+		for _, f := range schema.Fields {
+			if f.Type == model.FieldDate {
+				names = append(names, f.Name)
+			}
+		}
+	} else {
+		names = slices.Sorted(maps.Keys(model.DateFields(emptyDocument(docType))))
+	}
+
+	var invalid []string
+
+	for _, name := range names {
+		value, _ := formData[name].(string)
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		date, err := model.ParseDate(value)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s (%q)", name, value))
+
+			continue
+		}
+
+		formData[name] = string(date)
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("not a date such as 2026-01-02, Jan 2026 or 2026: %s", strings.Join(invalid, ", "))
+	}
+
+	return nil
+}
+
+// bookNumberFields are the reading-list fields stored as whole numbers.
+var bookNumberFields = []string{"pages", "progress", "rating", "rereads"}
+
+// coerceBookFields stores the reading-list number fields as numbers, so the
+// metadata decodes into the int fields, and rejects reading values the list
+// could not use. Numbers left empty are not stored.
+func coerceBookFields(formData map[string]any) error {
+	numbers := make(map[string]int, len(bookNumberFields))
+
+	for _, key := range bookNumberFields {
+		value, _ := formData[key].(string)
+		if strings.TrimSpace(value) == "" {
+			delete(formData, key)
+
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: want a whole number", key, value)
+		}
+
+		formData[key] = n
+		numbers[key] = n
+	}
+
+	state, _ := formData["readingStatus"].(string)
+	started, _ := formData["started"].(string)
+	finished, _ := formData["finished"].(string)
+
+	book := model.ReadingList{
+		Pages:         numbers["pages"],
+		ReadingStatus: state,
+		Started:       model.Date(started),
+		Finished:      model.Date(finished),
+		Progress:      numbers["progress"],
+		Rating:        numbers["rating"],
+		Rereads:       numbers["rereads"],
+	}
+
+	return book.ValidateFields()
+}
+
+// documentState is the publication state shown next to a document.
+func documentState(doc model.Document) string {
+	return doc.State(time.Now())
+}
+
+// numberInputValue shows an unset count as an empty field.
+func numberInputValue(n int) string {
+	if n == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(n)
+}
+
+// seriesOrderInputValue shows an unset part number as an empty field.
+func seriesOrderInputValue(order int) string {
+	if order < 1 {
+		return ""
+	}
+
+	return strconv.Itoa(order)
+}
+
+// publishAtInputValue formats a stored publishAt for a datetime-local input,
+// which only accepts "2006-01-02T15:04". Unparseable values are left blank.
+func publishAtInputValue(publishAt string) string {
+	if strings.TrimSpace(publishAt) == "" {
+		return ""
+	}
+
+	at, err := model.ParsePublishAt(publishAt)
+	if err != nil {
+		return ""
+	}
+
+	return at.In(time.Local).Format("2006-01-02T15:04")
+}
+
 func generateSlug(formData map[string]any, docType string) (string, error) {
 	title, ok := formData["title"].(string)
 	if !ok || strings.TrimSpace(title) == "" {
@@ -7,21 +7,25 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 	"timterests/cmd/web"
 	"timterests/internal/auth"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
 func TestWriterPageHandler(t *testing.T) {
 	t.Run("redirects to login when unauthenticated", func(t *testing.T) {
-		s := testSetup(t, context.Background())
+		s := testSetup(t)
 		a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/writer", nil)
 		rec := httptest.NewRecorder()
 
-		web.WriterPageHandler(rec, req, *s, "articles", "", 0, a)
+		web.WriterPageHandler(rec, req, s, "articles", "", a)
 
 		if rec.Code != http.StatusSeeOther {
 			t.Errorf("expected status %d, got %d", http.StatusSeeOther, rec.Code)
@@ -33,7 +37,7 @@ func TestWriterPageHandler(t *testing.T) {
 	})
 
 	t.Run("renders full writer page when authenticated", func(t *testing.T) {
-		s := testSetup(t, context.Background())
+		s := testSetup(t)
 		a, addAuthCookie := testAuthentication(t)
 
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/writer", nil)
@@ -41,7 +45,7 @@ func TestWriterPageHandler(t *testing.T) {
 
 		addAuthCookie(req)
 
-		web.WriterPageHandler(rec, req, *s, "articles", "", 0, a)
+		web.WriterPageHandler(rec, req, s, "articles", "", a)
 
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
@@ -62,7 +66,7 @@ func TestWriterPageHandler(t *testing.T) {
 	})
 
 	t.Run("renders partial form on HTMX request", func(t *testing.T) {
-		s := testSetup(t, context.Background())
+		s := testSetup(t)
 		a, addAuthCookie := testAuthentication(t)
 
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/writer", nil)
@@ -72,7 +76,7 @@ func TestWriterPageHandler(t *testing.T) {
 
 		addAuthCookie(req)
 
-		web.WriterPageHandler(rec, req, *s, "projects", "", 0, a)
+		web.WriterPageHandler(rec, req, s, "projects", "", a)
 
 		if rec.Code != http.StatusOK {
 			t.Errorf("expected status 200, got %d", rec.Code)
@@ -101,7 +105,7 @@ func TestWriterPageHandler(t *testing.T) {
 	})
 
 	t.Run("renders different doc type fields", func(t *testing.T) {
-		s := testSetup(t, context.Background())
+		s := testSetup(t)
 		a, addAuthCookie := testAuthentication(t)
 
 		docTypes := []struct {
@@ -121,7 +125,7 @@ func TestWriterPageHandler(t *testing.T) {
 
 				addAuthCookie(req)
 
-				web.WriterPageHandler(rec, req, *s, dt.name, "", 0, a)
+				web.WriterPageHandler(rec, req, s, dt.name, "", a)
 
 				doc, err := goquery.NewDocumentFromReader(rec.Body)
 				if err != nil {
@@ -138,7 +142,7 @@ func TestWriterPageHandler(t *testing.T) {
 
 func TestWriteDocumentHandler(t *testing.T) {
 	t.Run("redirects to login when unauthenticated", func(t *testing.T) {
-		s := testSetup(t, context.Background())
+		s := testSetup(t)
 		a := auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!")
 
 		form := url.Values{}
@@ -155,7 +159,7 @@ func TestWriteDocumentHandler(t *testing.T) {
 
 		rec := httptest.NewRecorder()
 
-		web.WriteDocumentHandler(rec, req, *s, a)
+		web.WriteDocumentHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusSeeOther {
 			t.Errorf("expected status %d, got %d", http.StatusSeeOther, rec.Code)
@@ -163,7 +167,7 @@ func TestWriteDocumentHandler(t *testing.T) {
 	})
 
 	t.Run("rejects non-POST methods", func(t *testing.T) {
-		s := testSetup(t, context.Background())
+		s := testSetup(t)
 		a, addAuthCookie := testAuthentication(t)
 
 		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/write", nil)
@@ -171,20 +175,17 @@ func TestWriteDocumentHandler(t *testing.T) {
 
 		addAuthCookie(req)
 
-		web.WriteDocumentHandler(rec, req, *s, a)
+		web.WriteDocumentHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusMethodNotAllowed {
 			t.Errorf("expected status 405, got %d", rec.Code)
 		}
 	})
 
-	t.Run("writes document to local storage", func(t *testing.T) {
+	t.Run("writes document to the storage backend", func(t *testing.T) {
 		a, addAuthCookie := testAuthentication(t)
 
-		tmpDir := t.TempDir()
-
-		s := testSetup(t, context.Background())
-		s.BaseDir = tmpDir
+		s := storage.NewMemoryBackend()
 
 		form := url.Values{}
 		form.Set("document-type", "projects")
@@ -206,7 +207,7 @@ func TestWriteDocumentHandler(t *testing.T) {
 
 		rec := httptest.NewRecorder()
 
-		web.WriteDocumentHandler(rec, req, *s, a)
+		web.WriteDocumentHandler(rec, req, s, a)
 
 		if rec.Code != http.StatusSeeOther {
 			t.Errorf("expected redirect 303, got %d", rec.Code)
@@ -215,5 +216,389 @@ func TestWriteDocumentHandler(t *testing.T) {
 		if loc := rec.Header().Get("Location"); loc != "/writer" {
 			t.Errorf("expected redirect to /writer, got %q", loc)
 		}
+
+		for _, key := range []string{"projects/my-test-project.yaml", "projects/my-test-project.md"} {
+			file, err := s.GetFile(context.Background(), key)
+			if err != nil {
+				t.Errorf("expected %s to be written: %v", key, err)
+
+				continue
+			}
+
+			file.Close()
+		}
+	})
+}
+
+func TestWriteDocumentHandlerPublication(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	write := func(s storage.Backend, status, publishAt string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Set("document-type", "projects")
+		form.Set("title", "Scheduled Project")
+		form.Set("subtitle", "A subtitle")
+		form.Set("body", "Body")
+		form.Set("tags", "go")
+		form.Set("repository", "https://github.com/test/repo")
+		form.Set("status", status)
+		form.Set("publishAt", publishAt)
+
+		req := httptest.NewRequestWithContext(
+			context.Background(), http.MethodPost, "/write",
+			strings.NewReader(form.Encode()),
+		)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.WriteDocumentHandler(rec, req, s, a)
+
+		return rec
+	}
+
+	t.Run("new documents default to draft", func(t *testing.T) {
+		s := testSetup(t)
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/writer", nil)
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.WriterPageHandler(rec, req, s, "articles", "", a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+
+		selected := doc.Find(`#status option[selected]`).AttrOr("value", "")
+		if selected != model.StatusDraft {
+			t.Errorf("expected draft to be preselected, got %q", selected)
+		}
+	})
+
+	t.Run("stores status and publishAt", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		rec := write(s, "published", "2099-01-01T09:00")
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("expected redirect 303, got %d", rec.Code)
+		}
+
+		project, err := service.GetProjectBySlug(context.Background(), s, "scheduled-project")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if project.State(time.Now()) != model.StatusScheduled {
+			t.Errorf("expected the project to be scheduled, got %q", project.State(time.Now()))
+		}
+	})
+
+	t.Run("rejects an invalid publishAt", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		rec := write(s, "published", "tomorrow")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestWriteDocumentHandlerSeries(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	write := func(s storage.Backend, order string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Set("document-type", "articles")
+		form.Set("title", "Series Part")
+		form.Set("subtitle", "A subtitle")
+		form.Set("body", "Body")
+		form.Set("date", "2026-01-01")
+		form.Set("tags", "go")
+		form.Set("series", "Go Tips")
+		form.Set("seriesOrder", order)
+
+		req := httptest.NewRequestWithContext(
+			context.Background(), http.MethodPost, "/write",
+			strings.NewReader(form.Encode()),
+		)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.WriteDocumentHandler(rec, req, s, a)
+
+		return rec
+	}
+
+	t.Run("stores the series and part number", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		rec := write(s, "2")
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("expected redirect 303, got %d", rec.Code)
+		}
+
+		article, err := service.GetArticleBySlug(context.Background(), s, "series-part-01-01-2026")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if article.Series != "Go Tips" || article.SeriesOrder != 2 {
+			t.Errorf("expected part 2 of Go Tips, got %q part %d", article.Series, article.SeriesOrder)
+		}
+	})
+
+	t.Run("rejects a part that is not a number", func(t *testing.T) {
+		rec := write(storage.NewMemoryBackend(), "two")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("offers the series already in use", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		rec := write(s, "")
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("expected redirect 303, got %d", rec.Code)
+		}
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/writer", nil)
+		addAuthCookie(req)
+
+		rec = httptest.NewRecorder()
+
+		web.WriterPageHandler(rec, req, s, "articles", "", a)
+
+		doc, err := goquery.NewDocumentFromReader(rec.Body)
+		if err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+
+		if doc.Find(`#series-options option[value="Go Tips"]`).Length() != 1 {
+			t.Error("expected Go Tips to be offered as a series")
+		}
+	})
+}
+
+func TestWriteDocumentHandlerRelated(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+	s := storage.NewMemoryBackend()
+
+	form := url.Values{}
+	form.Set("document-type", "projects")
+	form.Set("title", "Related Project")
+	form.Set("subtitle", "A subtitle")
+	form.Set("body", "Body")
+	form.Set("tags", "go")
+	form.Set("repository", "https://github.com/test/repo")
+	form.Set("related", "articles/one, reading-list/two")
+
+	req := httptest.NewRequestWithContext(
+		context.Background(), http.MethodPost, "/write",
+		strings.NewReader(form.Encode()),
+	)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	addAuthCookie(req)
+
+	rec := httptest.NewRecorder()
+
+	web.WriteDocumentHandler(rec, req, s, a)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected redirect 303, got %d", rec.Code)
+	}
+
+	project, err := service.GetProjectBySlug(context.Background(), s, "related-project")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"articles/one", "reading-list/two"}
+	if strings.Join(project.Related, " ") != strings.Join(want, " ") {
+		t.Errorf("expected related %v, got %v", want, project.Related)
+	}
+}
+
+func TestWriteDocumentHandlerReading(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	write := func(s storage.Backend, fields map[string]string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Set("document-type", "reading-list")
+		form.Set("title", "Reading Book")
+		form.Set("subtitle", "A subtitle")
+		form.Set("author", "An Author")
+		form.Set("body", "Body")
+		form.Set("tags", "go")
+
+		for key, value := range fields {
+			form.Set(key, value)
+		}
+
+		req := httptest.NewRequestWithContext(
+			context.Background(), http.MethodPost, "/write",
+			strings.NewReader(form.Encode()),
+		)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.WriteDocumentHandler(rec, req, s, a)
+
+		return rec
+	}
+
+	t.Run("stores the reading fields", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		rec := write(s, map[string]string{
+			"readingStatus": "reading",
+			"started":       "2026-01-01",
+			"progress":      "45",
+			"rating":        "4",
+			"pages":         "",
+		})
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("expected redirect 303, got %d", rec.Code)
+		}
+
+		book, err := service.GetBookBySlug(context.Background(), s, "reading-book")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if book.State() != "reading" || book.Progress != 45 || book.Rating != 4 || book.Started != "2026-01-01" {
+			t.Errorf("unexpected reading fields: %+v", book)
+		}
+	})
+
+	t.Run("rejects a rating out of range", func(t *testing.T) {
+		rec := write(storage.NewMemoryBackend(), map[string]string{"rating": "9"})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects a finish before the start", func(t *testing.T) {
+		rec := write(storage.NewMemoryBackend(), map[string]string{"started": "2026-02-01", "finished": "2026-01-01"})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestWriteDocumentHandlerKeepsHighlights(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+	s := storage.NewMemoryBackend()
+
+	err := storage.WriteDocument(context.Background(), s, "reading-list/kept-book.yaml",
+		[]byte("title: Kept Book\nauthor: An Author\nhighlights:\n  - text: Worth keeping.\n"), []byte("Body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	form := url.Values{}
+	form.Set("document-type", "reading-list")
+	form.Set("title", "Kept Book")
+	form.Set("subtitle", "A subtitle")
+	form.Set("author", "An Author")
+	form.Set("body", "Edited body")
+	form.Set("tags", "go")
+
+	req := httptest.NewRequestWithContext(
+		context.Background(), http.MethodPost, "/write",
+		strings.NewReader(form.Encode()),
+	)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	addAuthCookie(req)
+
+	rec := httptest.NewRecorder()
+
+	web.WriteDocumentHandler(rec, req, s, a)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected redirect 303, got %d", rec.Code)
+	}
+
+	book, err := service.GetBookBySlug(context.Background(), s, "kept-book")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if book.Subtitle != "A subtitle" || len(book.Highlights) != 1 || book.Highlights[0].Text != "Worth keeping." {
+		t.Errorf("expected the edit saved and the highlights kept, got %+v", book)
+	}
+}
+
+func TestWriteDocumentHandlerDates(t *testing.T) {
+	a, addAuthCookie := testAuthentication(t)
+
+	write := func(s storage.Backend, startDate, endDate string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Set("document-type", "projects")
+		form.Set("title", "Dated Project")
+		form.Set("subtitle", "A subtitle")
+		form.Set("body", "Body")
+		form.Set("tags", "go")
+		form.Set("startDate", startDate)
+		form.Set("endDate", endDate)
+
+		req := httptest.NewRequestWithContext(
+			context.Background(), http.MethodPost, "/write",
+			strings.NewReader(form.Encode()),
+		)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		addAuthCookie(req)
+
+		rec := httptest.NewRecorder()
+
+		web.WriteDocumentHandler(rec, req, s, a)
+
+		return rec
+	}
+
+	t.Run("stores dates in ISO 8601", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		rec := write(s, "Jan 2023", "March 5, 2024")
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("expected redirect 303, got %d", rec.Code)
+		}
+
+		project, err := service.GetProjectBySlug(context.Background(), s, "dated-project")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if project.StartDate != "2023-01" || project.EndDate != "2024-03-05" {
+			t.Errorf("expected 2023-01 and 2024-03-05, got %q and %q", project.StartDate, project.EndDate)
+		}
+	})
+
+	t.Run("rejects a date that does not parse", func(t *testing.T) {
+		s := storage.NewMemoryBackend()
+
+		rec := write(s, "sometime in 2023", "")
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d", rec.Code)
+		}
+
+		_, err := service.GetProjectBySlug(context.Background(), s, "dated-project")
+		if err == nil {
+			t.Error("expected nothing to be written")
+		}
 	})
 }
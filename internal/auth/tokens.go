@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"timterests/internal/storage"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TokensKey is where personal access tokens are kept. Only a hash of each
+// secret is stored, so reading the file does not give anyone a usable token.
+const TokensKey = "auth/tokens.yaml"
+
+// TokenPrefix starts every token secret, so one is easy to recognise in a
+// config file or a secret scanner.
+const TokenPrefix = "tt_"
+
+// Scopes a token can be given. A token may only do what its scopes allow.
+const (
+	// ScopeRead reads drafts and letters through the API.
+	ScopeRead = "read"
+	// ScopeWrite creates and updates documents.
+	ScopeWrite = "write"
+	// ScopeDelete moves documents to the trash.
+	ScopeDelete = "delete"
+)
+
+// Scopes lists every scope, in the order they are offered.
+var Scopes = []string{ScopeRead, ScopeWrite, ScopeDelete}
+
+// lastUsedPrecision is how stale a token's LastUsed may get before a request
+// with it is recorded, so an active script does not rewrite the file on every
+// call.
+const lastUsedPrecision = time.Hour
+
+var (
+	// ErrInvalidToken is returned for a secret that matches no token.
+	ErrInvalidToken = errors.New("invalid or revoked token")
+	// ErrTokenNotFound is returned when revoking a token ID that does not exist.
+	ErrTokenNotFound = errors.New("token not found")
+)
+
+// tokensMu serialises changes to the tokens file, which is read, changed and
+// written back whole.
+var tokensMu sync.Mutex
+
+// Token is a personal access token, as stored. The secret itself is only shown
+// once, when the token is created.
+type Token struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+	// Hint is the start of the secret, to tell tokens apart in the admin list.
+	Hint     string    `yaml:"hint"`
+	Hash     string    `yaml:"hash"`
+	Scopes   []string  `yaml:"scopes"`
+	Created  time.Time `yaml:"created"`
+	LastUsed time.Time `yaml:"lastUsed,omitempty"`
+}
+
+// HasScope reports whether the token was given scope.
+func (t *Token) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// ListTokens returns every token, newest first.
+func ListTokens(ctx context.Context, s storage.Backend) ([]Token, error) {
+	tokens, err := readTokens(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(tokens, func(a, b Token) int { return b.Created.Compare(a.Created) })
+
+	return tokens, nil
+}
+
+// CreateToken mints a token called name with scopes and returns its secret,
+// which is not stored and cannot be shown again.
+func CreateToken(ctx context.Context, s storage.Backend, name string, scopes []string, now time.Time) (string, Token, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", Token{}, errors.New("a token needs a name")
+	}
+
+	if len(scopes) == 0 {
+		return "", Token{}, errors.New("a token needs at least one scope")
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return "", Token{}, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	secret, err := randomString(32)
+	if err != nil {
+		return "", Token{}, err
+	}
+
+	id, err := randomString(9)
+	if err != nil {
+		return "", Token{}, err
+	}
+
+	secret = TokenPrefix + secret
+
+	token := Token{
+		ID:      id,
+		Name:    name,
+		Hint:    secret[:len(TokenPrefix)+4],
+		Hash:    hashSecret(secret),
+		Scopes:  slices.Clone(scopes),
+		Created: now.UTC(),
+	}
+
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+
+	tokens, err := readTokens(ctx, s)
+	if err != nil {
+		return "", Token{}, err
+	}
+
+	err = writeTokens(ctx, s, append(tokens, token))
+	if err != nil {
+		return "", Token{}, err
+	}
+
+	return secret, token, nil
+}
+
+// RevokeToken deletes the token with id, so its secret stops working at once.
+func RevokeToken(ctx context.Context, s storage.Backend, id string) error {
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+
+	tokens, err := readTokens(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	kept := slices.DeleteFunc(tokens, func(t Token) bool { return t.ID == id })
+	if len(kept) == len(tokens) {
+		return fmt.Errorf("%w: %q", ErrTokenNotFound, id)
+	}
+
+	return writeTokens(ctx, s, kept)
+}
+
+// VerifyToken returns the token secret belongs to, recording that it was used
+// at now.
+func VerifyToken(ctx context.Context, s storage.Backend, secret string, now time.Time) (*Token, error) {
+	if !strings.HasPrefix(secret, TokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	tokens, err := readTokens(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashSecret(secret)
+
+	for i := range tokens {
+		if subtle.ConstantTimeCompare([]byte(tokens[i].Hash), []byte(hash)) != 1 {
+			continue
+		}
+
+		token := tokens[i]
+
+		if now.Sub(token.LastUsed) >= lastUsedPrecision {
+			touchToken(ctx, s, token.ID, now)
+		}
+
+		return &token, nil
+	}
+
+	return nil, ErrInvalidToken
+}
+
+// touchToken records that the token with id was used at now. Failing to is not
+// a reason to refuse the request, so errors are dropped.
+func touchToken(ctx context.Context, s storage.Backend, id string, now time.Time) {
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+
+	tokens, err := readTokens(ctx, s)
+	if err != nil {
+		return
+	}
+
+	for i := range tokens {
+		if tokens[i].ID == id {
+			tokens[i].LastUsed = now.UTC()
+		}
+	}
+
+	_ = writeTokens(ctx, s, tokens)
+}
+
+func readTokens(ctx context.Context, s storage.Backend) ([]Token, error) {
+	file, err := s.GetFile(ctx, TokensKey)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", TokensKey, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", TokensKey, err)
+	}
+
+	var tokens []Token
+
+	err = yaml.Unmarshal(content, &tokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", TokensKey, err)
+	}
+
+	return tokens, nil
+}
+
+func writeTokens(ctx context.Context, s storage.Backend, tokens []Token) error {
+	content, err := yaml.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to encode tokens: %w", err)
+	}
+
+	err = s.WriteFile(ctx, TokensKey, content)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", TokensKey, err)
+	}
+
+	return nil
+}
+
+// hashSecret is SHA-256 rather than a slow password hash: secrets are random
+// and long, so there is nothing to gain from slowing down a guess.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,170 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"timterests/internal/auth"
+	"timterests/internal/storage"
+)
+
+func TestCreateAndVerifyToken(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := storage.NewMemoryBackend()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	secret, token, err := auth.CreateToken(ctx, s, " Publishing script ", []string{auth.ScopeRead, auth.ScopeWrite}, now)
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+
+	if !strings.HasPrefix(secret, auth.TokenPrefix) || !strings.HasPrefix(secret, token.Hint) {
+		t.Errorf("unexpected secret %q for hint %q", secret, token.Hint)
+	}
+
+	if token.Name != "Publishing script" {
+		t.Errorf("expected the name to be trimmed, got %q", token.Name)
+	}
+
+	t.Run("only a hash is stored", func(t *testing.T) {
+		t.Parallel()
+
+		file, err := s.GetFile(ctx, auth.TokensKey)
+		if err != nil {
+			t.Fatalf("failed to read the tokens file: %v", err)
+		}
+
+		defer func() { _ = file.Close() }()
+
+		content, _ := io.ReadAll(file)
+		if strings.Contains(string(content), secret) {
+			t.Error("expected the secret not to be stored")
+		}
+	})
+
+	t.Run("verifies the secret", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := auth.VerifyToken(ctx, s, secret, now)
+		if err != nil {
+			t.Fatalf("VerifyToken failed: %v", err)
+		}
+
+		if got.ID != token.ID || !got.HasScope(auth.ScopeWrite) || got.HasScope(auth.ScopeDelete) {
+			t.Errorf("unexpected token: %+v", got)
+		}
+	})
+
+	t.Run("rejects an unknown secret", func(t *testing.T) {
+		t.Parallel()
+
+		for _, bad := range []string{"", "nope", auth.TokenPrefix + "nope", secret + "x"} {
+			_, err := auth.VerifyToken(ctx, s, bad, now)
+			if !errors.Is(err, auth.ErrInvalidToken) {
+				t.Errorf("secret %q: expected ErrInvalidToken, got %v", bad, err)
+			}
+		}
+	})
+}
+
+func TestCreateTokenValidation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := storage.NewMemoryBackend()
+
+	tests := []struct {
+		name   string
+		token  string
+		scopes []string
+	}{
+		{"no name", " ", []string{auth.ScopeRead}},
+		{"no scopes", "Script", nil},
+		{"unknown scope", "Script", []string{"admin"}},
+	}
+
+	for _, tt := range tests {
+		_, _, err := auth.CreateToken(ctx, s, tt.token, tt.scopes, time.Now())
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	tokens, err := auth.ListTokens(ctx, s)
+	if err != nil || len(tokens) != 0 {
+		t.Errorf("expected no tokens to be stored, got %d (%v)", len(tokens), err)
+	}
+}
+
+func TestVerifyTokenRecordsUse(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := storage.NewMemoryBackend()
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	secret, _, err := auth.CreateToken(ctx, s, "Script", []string{auth.ScopeRead}, created)
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+
+	used := created.Add(2 * time.Hour)
+
+	_, err = auth.VerifyToken(ctx, s, secret, used)
+	if err != nil {
+		t.Fatalf("VerifyToken failed: %v", err)
+	}
+
+	tokens, err := auth.ListTokens(ctx, s)
+	if err != nil {
+		t.Fatalf("ListTokens failed: %v", err)
+	}
+
+	if !tokens[0].LastUsed.Equal(used) {
+		t.Errorf("expected last used %v, got %v", used, tokens[0].LastUsed)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := storage.NewMemoryBackend()
+	now := time.Now()
+
+	secret, token, err := auth.CreateToken(ctx, s, "Old script", []string{auth.ScopeRead}, now)
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+
+	_, kept, err := auth.CreateToken(ctx, s, "New script", []string{auth.ScopeRead}, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+
+	err = auth.RevokeToken(ctx, s, token.ID)
+	if err != nil {
+		t.Fatalf("RevokeToken failed: %v", err)
+	}
+
+	_, err = auth.VerifyToken(ctx, s, secret, now)
+	if !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expected a revoked token to stop working, got %v", err)
+	}
+
+	tokens, _ := auth.ListTokens(ctx, s)
+	if len(tokens) != 1 || tokens[0].ID != kept.ID {
+		t.Errorf("expected only the other token to remain, got %+v", tokens)
+	}
+
+	err = auth.RevokeToken(ctx, s, token.ID)
+	if !errors.Is(err, auth.ErrTokenNotFound) {
+		t.Errorf("expected ErrTokenNotFound, got %v", err)
+	}
+}
@@ -93,6 +93,11 @@ func getRegistry() map[string]errorDef {
 			"Method not allowed.",
 			SeverityWarning, http.StatusMethodNotAllowed,
 		},
+		"CONFLICT": {
+			"CONFLICT",
+			"The resource already exists.",
+			SeverityWarning, http.StatusConflict,
+		},
 		"STORAGE_FAILED": {
 			"STORAGE_FAILED",
 			"Failed to access storage.",
@@ -148,6 +153,7 @@ func BadRequest(err error) *AppError          { return New("BAD_REQUEST", err) }
 func Unauthorized(err error) *AppError        { return New("UNAUTHORIZED", err) }
 func Forbidden() *AppError                    { return newFromDef("FORBIDDEN") }
 func MethodNotAllowed() *AppError             { return newFromDef("METHOD_NOT_ALLOWED") }
+func Conflict(err error) *AppError            { return New("CONFLICT", err) }
 func StorageFailed(err error) *AppError       { return New("STORAGE_FAILED", err) }
 func RenderFailed(err error) *AppError        { return New("RENDER_FAILED", err) }
 func ParseFormFailed(err error) *AppError     { return New("PARSE_FORM_FAILED", err) }
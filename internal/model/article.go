@@ -4,7 +4,12 @@ package model
 type Article struct {
 	Document `yaml:",inline"`
 
-	Date string `validate:"required" yaml:"date"`
+	Date Date `validate:"required" yaml:"date"`
+	// Series names the multi-part series the article belongs to, if any, and
+	// SeriesOrder its place in it. Articles without an order follow the
+	// numbered ones by date.
+	Series      string `yaml:"series"`
+	SeriesOrder int    `yaml:"seriesOrder"`
 }
 
 // Validate checks that the Article has the required fields populated.
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CustomDocument is a document of a type declared in a schema. The fields
+// every document has are decoded into Document; the schema's own fields are
+// kept by name in Fields.
+type CustomDocument struct {
+	Document `yaml:",inline"`
+
+	Fields map[string]any `yaml:",inline"`
+	// Schema is the type the document belongs to. It is set after decoding,
+	// since the YAML does not name its own type.
+	Schema *DocumentSchema `yaml:"-"`
+}
+
+// Value returns a field of the document as text, whether it is one every
+// document has or one from the schema. A list is joined with commas.
+func (d *CustomDocument) Value(name string) string {
+	switch name {
+	case "title":
+		return d.Title
+	case "subtitle":
+		return d.Subtitle
+	case "preview":
+		return d.Preview
+	case "tags":
+		return strings.Join(d.Tags, ", ")
+	}
+
+	value, ok := d.Fields[name]
+	if !ok || value == nil {
+		return ""
+	}
+
+	list, ok := value.([]any)
+	if ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+
+		return strings.Join(items, ", ")
+	}
+
+	return fmt.Sprint(value)
+}
+
+// MissingRequired lists the required fields with no value: title from
+// Document, and whichever the schema marks required. ValidateRequired uses it
+// in place of struct tags, which a schema type does not have.
+func (d *CustomDocument) MissingRequired() []string {
+	missing := missingRequired(reflect.ValueOf(&d.Document))
+
+	if d.Schema == nil {
+		return missing
+	}
+
+	for _, f := range d.Schema.Fields {
+		if f.Required && strings.TrimSpace(d.Value(f.Name)) == "" {
+			missing = append(missing, f.Name)
+		}
+	}
+
+	return missing
+}
+
+// ValidateFields checks each schema field holds a value of its declared type.
+func (d *CustomDocument) ValidateFields() error {
+	if d.Schema == nil {
+		return nil
+	}
+
+	var problems []string
+
+	for _, f := range d.Schema.Fields {
+		value, ok := d.Fields[f.Name]
+		if !ok || value == nil || value == "" {
+			continue
+		}
+
+		if !validFieldValue(f.Type, value) {
+			problems = append(problems, fmt.Sprintf("%s must be a %s", f.Name, f.Type))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid fields: %s", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+// validFieldValue reports whether value, as decoded from YAML, fits fieldType.
+func validFieldValue(fieldType string, value any) bool {
+	text := fmt.Sprint(value)
+
+	switch fieldType {
+	case FieldNumber:
+		_, err := strconv.ParseFloat(text, 64)
+
+		return err == nil
+	case FieldDate:
+		return Date(text).Valid()
+	case FieldBool:
+		_, err := strconv.ParseBool(text)
+
+		return err == nil
+	case FieldList:
+		_, ok := value.([]any)
+
+		return ok
+	case FieldURL:
+		u, err := url.Parse(text)
+
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+	default:
+		_, ok := value.(string)
+
+		return ok
+	}
+}
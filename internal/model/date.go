@@ -0,0 +1,163 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is the ISO 8601 form every date is stored in.
+const DateLayout = "2006-01-02"
+
+// Precision of a date: some are only known to the month or year, such as when
+// a project started.
+const (
+	PrecisionDay = iota
+	PrecisionMonth
+	PrecisionYear
+)
+
+// dateLayouts are the formats ParseDate accepts, each with the precision it
+// gives. Day-first and month-first numeric forms such as 01/02/2006 are left
+// out, since there is no telling which was meant.
+var dateLayouts = []struct {
+	layout    string
+	precision int
+}{
+	{DateLayout, PrecisionDay},
+	{time.RFC3339, PrecisionDay},
+	{"2006-01-02T15:04", PrecisionDay},
+	{"2006/01/02", PrecisionDay},
+	{"January 2, 2006", PrecisionDay},
+	{"Jan 2, 2006", PrecisionDay},
+	{"2 January 2006", PrecisionDay},
+	{"2 Jan 2006", PrecisionDay},
+	{"2006-01", PrecisionMonth},
+	{"January 2006", PrecisionMonth},
+	{"Jan 2006", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// isoLayouts are the ISO 8601 forms a date is normalized to, by precision.
+var isoLayouts = map[int]string{
+	PrecisionDay:   DateLayout,
+	PrecisionMonth: "2006-01",
+	PrecisionYear:  "2006",
+}
+
+// Date is a calendar date as written in a document's metadata. It is kept as
+// the text it was written as, so a document with a date that does not parse
+// still loads, and is checked with Valid or ValidateDates. Compare and Time
+// work from the parsed date, so dates written in different formats still sort
+// correctly.
+type Date string
+
+// ParseDate reads a date in any of the accepted formats and returns it in ISO
+// 8601 form: 2006-01-02, or 2006-01 or 2006 for one known only to the month or
+// year.
+func ParseDate(value string) (Date, error) {
+	t, precision, err := Date(value).parse()
+	if err != nil {
+		return "", err
+	}
+
+	return Date(t.Format(isoLayouts[precision])), nil
+}
+
+// parse reads the date, returning the first day it covers and its precision.
+func (d Date) parse() (time.Time, int, error) {
+	value := strings.TrimSpace(string(d))
+
+	for _, l := range dateLayouts {
+		t, err := time.Parse(l.layout, value)
+		if err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), l.precision, nil
+		}
+	}
+
+	return time.Time{}, 0, fmt.Errorf("%q is not a date such as 2026-01-02, Jan 2026 or 2026", value)
+}
+
+// IsZero reports whether no date is set.
+func (d Date) IsZero() bool {
+	return strings.TrimSpace(string(d)) == ""
+}
+
+// Valid reports whether the date is empty or parses.
+func (d Date) Valid() bool {
+	if d.IsZero() {
+		return true
+	}
+
+	_, _, err := d.parse()
+
+	return err == nil
+}
+
+// Normalize returns the date in ISO 8601 form, or the date as it is if it does
+// not parse.
+func (d Date) Normalize() Date {
+	iso, err := ParseDate(string(d))
+	if err != nil {
+		return d
+	}
+
+	return iso
+}
+
+// Time returns midnight UTC on the first day the date covers, or the zero time
+// if it is empty or does not parse.
+func (d Date) Time() time.Time {
+	t, _, err := d.parse()
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// Year returns the date's year, or 0 if it is empty or does not parse.
+func (d Date) Year() int {
+	t := d.Time()
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Year()
+}
+
+// Compare orders two dates, returning -1, 0 or +1. Dates that are empty or do
+// not parse come before every real date.
+func (d Date) Compare(other Date) int {
+	return d.Time().Compare(other.Time())
+}
+
+// Before reports whether d is earlier than other.
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+// Format renders the date for display with layout. A date known only to the
+// month or year is shown as "Jan 2006" or "2006" whatever the layout, rather
+// than inventing a day, and one that does not parse is shown as written.
+func (d Date) Format(layout string) string {
+	t, precision, err := d.parse()
+	if err != nil {
+		return string(d)
+	}
+
+	switch precision {
+	case PrecisionMonth:
+		return t.Format("Jan 2006")
+	case PrecisionYear:
+		return t.Format("2006")
+	default:
+		return t.Format(cmp.Or(layout, DateLayout))
+	}
+}
+
+// String returns the date as written.
+func (d Date) String() string {
+	return string(d)
+}
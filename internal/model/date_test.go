@@ -0,0 +1,129 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+	"timterests/internal/model"
+)
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  model.Date
+	}{
+		{"2026-01-02", "2026-01-02"},
+		{" 2026-01-02 ", "2026-01-02"},
+		{"2026/01/02", "2026-01-02"},
+		{"2026-01-02T15:04:05Z", "2026-01-02"},
+		{"2026-01-02T15:04", "2026-01-02"},
+		{"January 2, 2026", "2026-01-02"},
+		{"Jan 2, 2026", "2026-01-02"},
+		{"2 January 2026", "2026-01-02"},
+		{"2 Jan 2026", "2026-01-02"},
+		{"2026-01", "2026-01"},
+		{"January 2026", "2026-01"},
+		{"Jan 2026", "2026-01"},
+		{"2026", "2026"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := model.ParseDate(tc.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	// Day-first and month-first numeric dates cannot be told apart.
+	for _, input := range []string{"", "01/02/2026", "2026-13-01", "someday", "Jan"} {
+		_, err := model.ParseDate(input)
+		if err == nil {
+			t.Errorf("expected %q to be rejected", input)
+		}
+	}
+}
+
+func TestDateCompare(t *testing.T) {
+	t.Parallel()
+
+	if !model.Date("Jan 2026").Before("2026-01-15") {
+		t.Error("expected a month to sort before a later day in it")
+	}
+
+	if model.Date("2026-02-01").Compare("January 31, 2026") <= 0 {
+		t.Error("expected dates in different formats to compare by date")
+	}
+
+	if model.Date("2026-01-01").Compare("Jan 1, 2026") != 0 {
+		t.Error("expected the same day in two formats to be equal")
+	}
+
+	if !model.Date("").Before("1999") {
+		t.Error("expected an empty date to sort first")
+	}
+}
+
+func TestDateFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		date model.Date
+		want string
+	}{
+		{"2026-01-02", "Jan 2, 2026"},
+		{"2026-01", "Jan 2026"},
+		{"2026", "2026"},
+		{"not a date", "not a date"},
+	}
+
+	for _, tc := range tests {
+		got := tc.date.Format("Jan 2, 2006")
+		if got != tc.want {
+			t.Errorf("Format(%q): got %q, want %q", tc.date, got, tc.want)
+		}
+	}
+
+	if got := model.Date("March 5, 2026").Normalize(); got != "2026-03-05" {
+		t.Errorf("Normalize: got %q, want 2026-03-05", got)
+	}
+
+	if got := model.Date("2024-06-30").Year(); got != 2024 {
+		t.Errorf("Year: got %d, want 2024", got)
+	}
+}
+
+func TestValidateDates(t *testing.T) {
+	t.Parallel()
+
+	valid := model.Project{StartDate: "Jan 2023", EndDate: ""}
+
+	err := model.ValidateDates(&valid)
+	if err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	book := model.ReadingList{Started: "yesterday", Finished: "2026-01-02", Published: "1999"}
+
+	err = model.ValidateDates(&book)
+	if err == nil {
+		t.Fatal("expected an error for an unparseable date")
+	}
+
+	if !strings.Contains(err.Error(), "started") || strings.Contains(err.Error(), "finished") {
+		t.Errorf("expected only started to be named, got: %v", err)
+	}
+
+	fields := model.DateFields(&model.Article{Date: "2026-01-01"})
+	if fields["date"] != "2026-01-01" {
+		t.Errorf("expected the date field by its yaml name, got %v", fields)
+	}
+}
@@ -4,7 +4,7 @@ package model
 type Letter struct {
 	Document `yaml:",inline"`
 
-	Date     string `validate:"required" yaml:"date"`
+	Date     Date   `validate:"required" yaml:"date"`
 	Occasion string `yaml:"occasion"`
 }
 
@@ -78,9 +78,9 @@ func TestProjectTimespan(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			p := model.Project{StartDate: tc.start, EndDate: tc.end}
+			p := model.Project{StartDate: model.Date(tc.start), EndDate: model.Date(tc.end)}
 
-			result := p.Timespan()
+			result := p.Timespan(model.DateLayout)
 			if result != tc.expected {
 				t.Errorf("expected %q, got %q", tc.expected, result)
 			}
@@ -151,3 +151,19 @@ func TestReadingListValidate(t *testing.T) {
 		}
 	})
 }
+
+func TestSeriesSlug(t *testing.T) {
+	tests := map[string]string{
+		"Go Tips":                    "go-tips",
+		"Building a Blog, Part Two!": "building-a-blog-part-two",
+		"  --Spaced  Out--  ":        "spaced-out",
+		"":                           "",
+	}
+
+	for name, want := range tests {
+		got := model.SeriesSlug(name)
+		if got != want {
+			t.Errorf("SeriesSlug(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+// permalinkPaths maps a document type, as named by its storage prefix, to the
+// path its permalinks live under.
+var permalinkPaths = map[string]string{
+	"articles":     "/articles/",
+	"projects":     "/projects/",
+	"reading-list": "/books/",
+	"letters":      "/letters/",
+}
+
+// DocumentPath returns the permalink path for the document of docType with
+// slug, e.g. "/books/dune" for a reading-list entry. A type declared in a
+// schema lives under its own name.
+func DocumentPath(docType, slug string) string {
+	prefix, ok := permalinkPaths[docType]
+	if !ok {
+		prefix = "/" + docType + "/"
+	}
+
+	return prefix + slug
+}
+
+// DocumentLink is a link to another document, ready to render.
+type DocumentLink struct {
+	Title string
+	URL   string
+}
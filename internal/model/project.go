@@ -6,21 +6,22 @@ type Project struct {
 
 	Repository string `yaml:"repository"`
 	Image      string `yaml:"imagePath"`
-	StartDate  string `yaml:"startDate"`
-	EndDate    string `yaml:"endDate"`
+	StartDate  Date   `yaml:"startDate"`
+	EndDate    Date   `yaml:"endDate"`
 }
 
-// Timespan returns a formatted date range for display.
-func (p *Project) Timespan() string {
-	if p.StartDate == "" {
+// Timespan returns the project's date range for display, each end formatted
+// with layout.
+func (p *Project) Timespan(layout string) string {
+	if p.StartDate.IsZero() {
 		return ""
 	}
 
-	if p.EndDate == "" {
-		return p.StartDate + " — Present"
+	if p.EndDate.IsZero() {
+		return p.StartDate.Format(layout) + " — Present"
 	}
 
-	return p.StartDate + " — " + p.EndDate
+	return p.StartDate.Format(layout) + " — " + p.EndDate.Format(layout)
 }
 
 // Validate checks that the Project has the required fields populated.
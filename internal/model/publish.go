@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Publication states. Only draft and published are stored; scheduled is what a
+// published document with a future publishAt is reported as.
+//
+// A document with no status is published, so everything written before drafts
+// existed stays live.
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+	StatusScheduled = "scheduled"
+)
+
+// PublishAtLayouts are the formats accepted for publishAt, most precise first.
+// The second is what an HTML datetime-local input submits.
+var PublishAtLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// ParsePublishAt parses a publishAt value in any of PublishAtLayouts. Times
+// without a zone are taken as server local time.
+func ParsePublishAt(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range PublishAtLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("publishAt %q is not a date such as 2026-01-02 or 2026-01-02T15:04", value)
+}
+
+// IsDraft reports whether the document is explicitly marked as a draft.
+func (d *Document) IsDraft() bool {
+	return strings.EqualFold(strings.TrimSpace(d.Status), StatusDraft)
+}
+
+// IsPublished reports whether the public may see the document at now: it is not
+// a draft, and any publishAt has passed. A publishAt that cannot be parsed is
+// ignored rather than hiding the document; ValidatePublication rejects it on
+// the way in.
+func (d *Document) IsPublished(now time.Time) bool {
+	if d.IsDraft() {
+		return false
+	}
+
+	if strings.TrimSpace(d.PublishAt) == "" {
+		return true
+	}
+
+	at, err := ParsePublishAt(d.PublishAt)
+	if err != nil {
+		return true
+	}
+
+	return !at.After(now)
+}
+
+// State returns StatusDraft, StatusScheduled or StatusPublished for display.
+func (d *Document) State(now time.Time) string {
+	switch {
+	case d.IsDraft():
+		return StatusDraft
+	case !d.IsPublished(now):
+		return StatusScheduled
+	default:
+		return StatusPublished
+	}
+}
+
+// ValidatePublication checks status and publishAt hold values IsPublished
+// understands, so a typo cannot silently publish a document early.
+func (d *Document) ValidatePublication() error {
+	status := strings.ToLower(strings.TrimSpace(d.Status))
+	if status != "" && status != StatusDraft && status != StatusPublished {
+		return fmt.Errorf("status must be %q or %q, got %q", StatusDraft, StatusPublished, d.Status)
+	}
+
+	if strings.TrimSpace(d.PublishAt) == "" {
+		return nil
+	}
+
+	_, err := ParsePublishAt(d.PublishAt)
+
+	return err
+}
+
+// Publishable is implemented by every document type through Document.
+type Publishable interface {
+	IsPublished(now time.Time) bool
+}
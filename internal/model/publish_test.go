@@ -0,0 +1,76 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"timterests/internal/model"
+)
+
+func TestDocumentPublication(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		doc       model.Document
+		published bool
+		state     string
+	}{
+		{"no status is published", model.Document{}, true, model.StatusPublished},
+		{"explicit published", model.Document{Status: "published"}, true, model.StatusPublished},
+		{"draft is hidden", model.Document{Status: "Draft"}, false, model.StatusDraft},
+		{"past publishAt", model.Document{PublishAt: "2026-02-01"}, true, model.StatusPublished},
+		{"future publishAt", model.Document{PublishAt: "2026-03-01T13:00"}, false, model.StatusScheduled},
+		{"future RFC3339", model.Document{PublishAt: "2027-01-01T00:00:00Z"}, false, model.StatusScheduled},
+		{"draft with past publishAt", model.Document{Status: "draft", PublishAt: "2026-01-01"}, false, model.StatusDraft},
+		{"unparseable publishAt is ignored", model.Document{PublishAt: "soon"}, true, model.StatusPublished},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			published := tt.doc.IsPublished(now)
+			if published != tt.published {
+				t.Errorf("IsPublished() = %v, want %v", published, tt.published)
+			}
+
+			state := tt.doc.State(now)
+			if state != tt.state {
+				t.Errorf("State() = %q, want %q", state, tt.state)
+			}
+		})
+	}
+}
+
+func TestValidatePublication(t *testing.T) {
+	t.Parallel()
+
+	valid := []model.Document{
+		{},
+		{Status: "draft"},
+		{Status: "published", PublishAt: "2026-01-02"},
+		{PublishAt: "2026-01-02T15:04"},
+	}
+
+	for _, doc := range valid {
+		err := doc.ValidatePublication()
+		if err != nil {
+			t.Errorf("expected %+v to be valid, got %v", doc, err)
+		}
+	}
+
+	invalid := []model.Document{
+		{Status: "pending"},
+		{PublishAt: "next tuesday"},
+	}
+
+	for _, doc := range invalid {
+		err := doc.ValidatePublication()
+		if err == nil {
+			t.Errorf("expected %+v to be rejected", doc)
+		}
+	}
+}
@@ -1,17 +1,135 @@
 package model
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Reading states a book on the reading list can be in. A book with no state is
+// treated as finished, since the list predates tracking and only held books
+// already read.
+const (
+	ReadingWantToRead = "want-to-read"
+	ReadingReading    = "reading"
+	ReadingFinished   = "finished"
+	ReadingAbandoned  = "abandoned"
+)
+
+// ReadingStates lists every reading state, in the order a book moves through
+// them.
+var ReadingStates = []string{ReadingWantToRead, ReadingReading, ReadingFinished, ReadingAbandoned}
+
+// MaxRating is the highest rating a book can be given; 0 means unrated.
+const MaxRating = 5
+
 // ReadingList represents a book that appears in the reading list.
 type ReadingList struct {
 	Document `yaml:",inline"`
 
 	Image     string `yaml:"imagePath"`
 	Author    string `validate:"required" yaml:"author"`
-	Published string `yaml:"published"`
+	Published Date   `yaml:"published"`
 	ISBN      string `yaml:"isbn"`
 	Website   string `yaml:"website"`
+	Pages     int    `yaml:"pages"`
+
+	ReadingStatus string `yaml:"readingStatus"`
+	Started       Date   `yaml:"started"`
+	Finished      Date   `yaml:"finished"`
+	Progress      int    `yaml:"progress"` // percent, 0–100
+	Rating        int    `yaml:"rating"`   // 1–MaxRating, 0 when unrated
+	Rereads       int    `yaml:"rereads"`
+
+	Highlights []Highlight `yaml:"highlights"`
+}
+
+// Highlight is a passage marked in a book, usually imported from a Kindle.
+type Highlight struct {
+	Text string `yaml:"text"`
+	// Location is where the passage is, as the reader gave it, e.g. "Location
+	// 1406-1407" or "page 12".
+	Location string `yaml:"location,omitempty"`
+	Added    Date   `yaml:"added,omitempty"`
+	// Note is the reader's own note on the passage.
+	Note string `yaml:"note,omitempty"`
 }
 
 // Validate checks that the ReadingList entry has the required fields populated.
 func (r *ReadingList) Validate() error {
 	return ValidateRequired(r)
 }
+
+// State returns the book's reading state, ReadingFinished when none is set.
+func (r *ReadingList) State() string {
+	state := strings.ToLower(strings.TrimSpace(r.ReadingStatus))
+	if state == "" {
+		return ReadingFinished
+	}
+
+	return state
+}
+
+// PercentRead returns how far through the book the reader is: 100 once it is
+// finished, whatever progress was last recorded.
+func (r *ReadingList) PercentRead() int {
+	if r.State() == ReadingFinished {
+		return 100
+	}
+
+	return r.Progress
+}
+
+// FinishedYear returns the year the book was finished, or 0 when it has not
+// been or the date is missing.
+func (r *ReadingList) FinishedYear() int {
+	if r.State() != ReadingFinished {
+		return 0
+	}
+
+	return r.Finished.Year()
+}
+
+// ValidateFields checks the reading fields hold values the list can use: a
+// known state, real dates in order, and numbers in range.
+func (r *ReadingList) ValidateFields() error {
+	var problems []string
+
+	if !slices.Contains(ReadingStates, r.State()) {
+		problems = append(problems, fmt.Sprintf("readingStatus must be one of %s", strings.Join(ReadingStates, ", ")))
+	}
+
+	if !r.Started.Valid() {
+		problems = append(problems, "started must be a date such as 2026-01-02")
+	}
+
+	if !r.Finished.Valid() {
+		problems = append(problems, "finished must be a date such as 2026-01-02")
+	}
+
+	if !r.Started.Time().IsZero() && !r.Finished.Time().IsZero() && r.Finished.Before(r.Started) {
+		problems = append(problems, "finished must not be before started")
+	}
+
+	if r.Progress < 0 || r.Progress > 100 {
+		problems = append(problems, "progress must be from 0 to 100")
+	}
+
+	if r.Rating < 0 || r.Rating > MaxRating {
+		problems = append(problems, fmt.Sprintf("rating must be from 1 to %d, or 0 for unrated", MaxRating))
+	}
+
+	if r.Rereads < 0 {
+		problems = append(problems, "rereads must not be negative")
+	}
+
+	if r.Pages < 0 {
+		problems = append(problems, "pages must not be negative")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid fields: %s", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"timterests/internal/model"
+)
+
+func TestReadingListState(t *testing.T) {
+	t.Parallel()
+
+	book := model.ReadingList{Progress: 40}
+	if book.State() != model.ReadingFinished || book.PercentRead() != 100 {
+		t.Errorf("expected a book with no state to count as finished, got %q at %d%%", book.State(), book.PercentRead())
+	}
+
+	book.ReadingStatus = "Reading"
+	if book.State() != model.ReadingReading || book.PercentRead() != 40 {
+		t.Errorf("expected reading at 40%%, got %q at %d%%", book.State(), book.PercentRead())
+	}
+
+	book = model.ReadingList{Finished: "2025-03-04"}
+	if book.FinishedYear() != 2025 {
+		t.Errorf("expected 2025, got %d", book.FinishedYear())
+	}
+
+	book.ReadingStatus = model.ReadingAbandoned
+	if book.FinishedYear() != 0 {
+		t.Errorf("expected an abandoned book to have no finish year, got %d", book.FinishedYear())
+	}
+}
+
+func TestReadingListValidateFields(t *testing.T) {
+	t.Parallel()
+
+	valid := model.ReadingList{
+		ReadingStatus: model.ReadingFinished,
+		Started:       "2026-01-01",
+		Finished:      "2026-02-01",
+		Rating:        5,
+		Progress:      100,
+	}
+
+	err := valid.ValidateFields()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		book model.ReadingList
+		want string
+	}{
+		{"unknown state", model.ReadingList{ReadingStatus: "skimmed"}, "readingStatus"},
+		{"bad date", model.ReadingList{Started: "01/02/2026"}, "started must be a date"},
+		{"finished before started", model.ReadingList{Started: "2026-02-01", Finished: "2026-01-01"}, "before started"},
+		{"progress over 100", model.ReadingList{Progress: 120}, "progress"},
+		{"rating over max", model.ReadingList{Rating: 6}, "rating"},
+		{"negative rereads", model.ReadingList{Rereads: -1}, "rereads"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.book.ValidateFields()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Field types a schema field can declare.
+const (
+	FieldString = "string" // one line of text
+	FieldText   = "text"   // several lines of text
+	FieldNumber = "number"
+	FieldDate   = "date" // see Date for the formats accepted
+	FieldBool   = "bool"
+	FieldList   = "list" // a list of strings, written comma-separated
+	FieldURL    = "url"
+)
+
+// fieldTypes lists every field type, for validation and error messages.
+var fieldTypes = []string{FieldString, FieldText, FieldNumber, FieldDate, FieldBool, FieldList, FieldURL}
+
+// documentFields are the fields every document has through Document, so a
+// schema can refer to them but not declare them again.
+var documentFields = []string{"title", "subtitle", "preview", "tags", "status", "publishAt", "toc", "related"}
+
+// typeName is what a document type may be called. The name is both its storage
+// prefix and its URL path, so it is kept to a plain path segment.
+var typeName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// SchemaField is one field of a custom document type, on top of the fields
+// every document has.
+type SchemaField struct {
+	Name     string `yaml:"name"`
+	Label    string `yaml:"label"`
+	Type     string `yaml:"type"`
+	Required bool   `yaml:"required"`
+}
+
+// DisplayLabel returns the label to show for the field, falling back to its
+// name.
+func (f SchemaField) DisplayLabel() string {
+	if f.Label != "" {
+		return f.Label
+	}
+
+	return f.Name
+}
+
+// ListLayout controls the list page of a custom type.
+type ListLayout struct {
+	// Design is the default design: "grid", "links" or "large".
+	Design string `yaml:"design"`
+	// SortBy names the field documents are ordered by; the title when empty.
+	SortBy     string `yaml:"sortBy"`
+	Descending bool   `yaml:"descending"`
+}
+
+// DetailLayout controls the page of a single custom document.
+type DetailLayout struct {
+	// Fields are shown above the body, in this order, when they have a value.
+	Fields []string `yaml:"fields"`
+}
+
+// CardMapping names the fields a custom document's card is drawn from. Title,
+// subtitle and preview default to the document fields of the same name.
+type CardMapping struct {
+	Title    string `yaml:"title"`
+	Subtitle string `yaml:"subtitle"`
+	Preview  string `yaml:"preview"`
+	Date     string `yaml:"date"`
+	Image    string `yaml:"image"`
+}
+
+// DocumentSchema declares a custom document type: its fields and how it is
+// listed, shown and drawn as a card.
+type DocumentSchema struct {
+	Name   string        `yaml:"name"`
+	Label  string        `yaml:"label"`
+	Fields []SchemaField `yaml:"fields"`
+	List   ListLayout    `yaml:"list"`
+	Detail DetailLayout  `yaml:"detail"`
+	Card   CardMapping   `yaml:"card"`
+}
+
+// DisplayLabel returns the label to show for the type, falling back to its
+// name.
+func (s *DocumentSchema) DisplayLabel() string {
+	if s.Label != "" {
+		return s.Label
+	}
+
+	return s.Name
+}
+
+// Field returns the declared field called name.
+func (s *DocumentSchema) Field(name string) (SchemaField, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+
+	return SchemaField{}, false
+}
+
+// Validate checks the schema is complete and refers only to fields it has.
+func (s *DocumentSchema) Validate() error {
+	if !typeName.MatchString(s.Name) {
+		return fmt.Errorf("type name %q must be lowercase letters, digits and dashes", s.Name)
+	}
+
+	var problems []string
+
+	seen := make(map[string]bool, len(s.Fields))
+
+	for _, f := range s.Fields {
+		switch {
+		case f.Name == "":
+			problems = append(problems, "a field has no name")
+		case slices.Contains(documentFields, f.Name):
+			problems = append(problems, fmt.Sprintf("field %q is one every document already has", f.Name))
+		case seen[f.Name]:
+			problems = append(problems, fmt.Sprintf("field %q is declared twice", f.Name))
+		case !slices.Contains(fieldTypes, f.Type):
+			problems = append(problems, fmt.Sprintf("field %q has type %q, want one of %s", f.Name, f.Type, strings.Join(fieldTypes, ", ")))
+		}
+
+		seen[f.Name] = true
+	}
+
+	refs := append([]string{s.List.SortBy, s.Card.Title, s.Card.Subtitle, s.Card.Preview, s.Card.Date, s.Card.Image}, s.Detail.Fields...)
+	for _, ref := range refs {
+		if ref != "" && !seen[ref] && !slices.Contains(documentFields, ref) {
+			problems = append(problems, fmt.Sprintf("%q is not a field", ref))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("type %q: %s", s.Name, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// ParseSchemas decodes a schema file: a list of types under "types". Every type
+// must be valid and named only once.
+func ParseSchemas(content []byte) ([]DocumentSchema, error) {
+	var file struct {
+		Types []DocumentSchema `yaml:"types"`
+	}
+
+	err := yaml.UnmarshalStrict(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("the schema could not be parsed: %w", err)
+	}
+
+	var errs []error
+
+	seen := make(map[string]bool, len(file.Types))
+
+	for i := range file.Types {
+		err = file.Types[i].Validate()
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if seen[file.Types[i].Name] {
+			errs = append(errs, fmt.Errorf("type %q is declared twice", file.Types[i].Name))
+		}
+
+		seen[file.Types[i].Name] = true
+	}
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Types, nil
+}
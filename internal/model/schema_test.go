@@ -0,0 +1,158 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"timterests/internal/model"
+
+	"gopkg.in/yaml.v2"
+)
+
+const recipeSchema = `
+types:
+  - name: recipes
+    label: Recipes
+    fields:
+      - name: servings
+        type: number
+        required: true
+      - name: cuisine
+        type: string
+      - name: source
+        type: url
+      - name: ingredients
+        type: list
+    list:
+      sortBy: servings
+    detail:
+      fields: [cuisine, servings]
+    card:
+      subtitle: cuisine
+`
+
+func TestParseSchemas(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes a valid schema", func(t *testing.T) {
+		t.Parallel()
+
+		schemas, err := model.ParseSchemas([]byte(recipeSchema))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(schemas) != 1 || schemas[0].Name != "recipes" {
+			t.Fatalf("expected the recipes type, got %+v", schemas)
+		}
+
+		field, ok := schemas[0].Field("servings")
+		if !ok || field.Type != model.FieldNumber || !field.Required {
+			t.Errorf("expected a required number field, got %+v", field)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"bad type name", "types:\n  - name: Recipes\n", "lowercase"},
+		{"unknown field type", "types:\n  - name: recipes\n    fields:\n      - {name: x, type: colour}\n", "want one of"},
+		{"redeclared document field", "types:\n  - name: recipes\n    fields:\n      - {name: title, type: string}\n", "already has"},
+		{"unknown reference", "types:\n  - name: recipes\n    card:\n      image: photo\n", `"photo" is not a field`},
+		{"duplicate type", "types:\n  - name: recipes\n  - name: recipes\n", "declared twice"},
+		{"unknown key", "types:\n  - name: recipes\n    colour: red\n", "could not be parsed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := model.ParseSchemas([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCustomDocument(t *testing.T) {
+	t.Parallel()
+
+	schemas, err := model.ParseSchemas([]byte(recipeSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decode := func(t *testing.T, content string) *model.CustomDocument {
+		t.Helper()
+
+		doc := &model.CustomDocument{Schema: &schemas[0]}
+
+		err := yaml.Unmarshal([]byte(content), doc)
+		if err != nil {
+			t.Fatalf("failed to decode: %v", err)
+		}
+
+		return doc
+	}
+
+	t.Run("keeps schema fields by name", func(t *testing.T) {
+		t.Parallel()
+
+		doc := decode(t, "title: Pancakes\nservings: 4\ningredients: [flour, eggs]\n")
+
+		if doc.Title != "Pancakes" {
+			t.Errorf("expected the title on Document, got %q", doc.Title)
+		}
+
+		if got := doc.Value("servings"); got != "4" {
+			t.Errorf("expected servings 4, got %q", got)
+		}
+
+		if got := doc.Value("ingredients"); got != "flour, eggs" {
+			t.Errorf("expected the list joined, got %q", got)
+		}
+	})
+
+	t.Run("ValidateRequired uses the schema", func(t *testing.T) {
+		t.Parallel()
+
+		err := model.ValidateRequired(decode(t, "cuisine: French\n"))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		for _, want := range []string{"title", "servings"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected %q in %q", want, err)
+			}
+		}
+
+		err = model.ValidateRequired(decode(t, "title: Pancakes\nservings: 4\n"))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("ValidateFields checks field types", func(t *testing.T) {
+		t.Parallel()
+
+		err := decode(t, "title: Pancakes\nservings: lots\nsource: not a url\n").ValidateFields()
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		for _, want := range []string{"servings must be a number", "source must be a url"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected %q in %q", want, err)
+			}
+		}
+
+		err = decode(t, "title: Pancakes\nservings: 4\nsource: https://example.com\n").ValidateFields()
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package model
+
+import "strings"
+
+// Series is a run of articles sharing a series name, in reading order.
+type Series struct {
+	Name     string
+	Slug     string
+	Articles []Article
+}
+
+// SeriesNav places one article within its series for the "Part N of M" box.
+type SeriesNav struct {
+	Name  string
+	Slug  string
+	Part  int
+	Total int
+	Prev  *Article
+	Next  *Article
+}
+
+// SeriesSlug turns a series name into the slug its landing page lives under,
+// e.g. "Building a Blog, Part Two" becomes "building-a-blog-part-two". Names
+// that differ only in case or punctuation land on the same series.
+func SeriesSlug(name string) string {
+	var b strings.Builder
+
+	dash := false
+
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+
+			b.WriteRune(r)
+
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	return b.String()
+}
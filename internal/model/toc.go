@@ -0,0 +1,44 @@
+package model
+
+import "strconv"
+
+// Heading is one heading of a rendered document, with the ID its anchor links to.
+type Heading struct {
+	Level int
+	ID    string
+	Text  string
+}
+
+// The table of contents skips h1, which is the document's own title, and stops
+// at h3 so it stays short enough for a sidebar.
+const (
+	tocMinLevel = 2
+	tocMaxLevel = 3
+	// tocMinHeadings is how many listed headings a document needs before it gets
+	// a table of contents without asking for one.
+	tocMinHeadings = 3
+)
+
+// TableOfContents returns the headings to list beside the document, or nil when
+// it should show none. A toc field of true or false in the metadata decides;
+// without one, long documents get a table of contents and short ones do not.
+func (d *Document) TableOfContents(headings []Heading) []Heading {
+	var listed []Heading
+
+	for _, h := range headings {
+		if h.Level >= tocMinLevel && h.Level <= tocMaxLevel {
+			listed = append(listed, h)
+		}
+	}
+
+	show, err := strconv.ParseBool(d.TOC)
+	if err != nil {
+		show = len(listed) >= tocMinHeadings
+	}
+
+	if !show || len(listed) == 0 {
+		return nil
+	}
+
+	return listed
+}
@@ -0,0 +1,41 @@
+package model_test
+
+import (
+	"testing"
+
+	"timterests/internal/model"
+)
+
+func TestTableOfContents(t *testing.T) {
+	t.Parallel()
+
+	short := []model.Heading{{Level: 1, ID: "title"}, {Level: 2, ID: "a"}, {Level: 4, ID: "deep"}}
+	long := []model.Heading{{Level: 2, ID: "a"}, {Level: 3, ID: "b"}, {Level: 2, ID: "c"}}
+
+	tests := []struct {
+		name     string
+		toc      string
+		headings []model.Heading
+		want     int
+	}{
+		{"long document shows by default", "", long, 3},
+		{"short document hides by default", "", short, 0},
+		{"true forces it on, listing only h2 and h3", "true", short, 1},
+		{"false turns it off", "false", long, 0},
+		{"unrecognised value falls back to length", "sometimes", long, 3},
+		{"true with nothing to list shows nothing", "true", []model.Heading{{Level: 1, ID: "title"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc := model.Document{TOC: tt.toc}
+
+			got := doc.TableOfContents(tt.headings)
+			if len(got) != tt.want {
+				t.Errorf("expected %d entries, got %d (%+v)", tt.want, len(got), got)
+			}
+		})
+	}
+}
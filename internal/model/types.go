@@ -3,12 +3,20 @@ package model
 
 // Document represents the base structure for various content types.
 type Document struct {
-	ID       string
-	S3Key    string
-	Title    string   `validate:"required" yaml:"title"`
-	Subtitle string   `yaml:"subtitle"`
-	Preview  string   `yaml:"preview"`
-	Tags     []string `yaml:"tags"`
+	ID        string
+	S3Key     string
+	Title     string   `validate:"required" yaml:"title"`
+	Subtitle  string   `yaml:"subtitle"`
+	Preview   string   `yaml:"preview"`
+	Tags      []string `yaml:"tags"`
+	Status    string   `yaml:"status"`
+	PublishAt string   `yaml:"publishAt"`
+	// TOC turns the table of contents on ("true") or off ("false"); empty
+	// leaves it to the document's length.
+	TOC string `yaml:"toc"`
+	// Related lists "type/slug" keys to suggest after the document, in place
+	// of the suggestions worked out from its tags and text.
+	Related []string `yaml:"related"`
 }
 
 // SetMeta sets the ID and S3Key fields on the document.
@@ -23,6 +31,12 @@ type DisplayContent struct {
 	ID    string
 	S3Key string
 	Body  string
+	// TOC lists the headings to show in the table of contents, if any.
+	TOC []Heading
+	// Series places an article within its series, if it is in one.
+	Series *SeriesNav
+	// Backlinks lists the documents that link to this one.
+	Backlinks []DocumentLink
 }
 
 // Content holds a document with its body for editor forms (raw markdown).
@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -14,8 +15,19 @@ const requiredTag = "required"
 // Driven by struct tags rather than per-type code, so a new document type is
 // validated correctly without anything being written for it — the tags on the
 // struct are the only place the rules live.
+//
+// A document whose rules come from a schema rather than struct tags reports its
+// own missing fields instead.
 func ValidateRequired(doc any) error {
-	missing := missingRequired(reflect.ValueOf(doc))
+	var missing []string
+
+	checker, ok := doc.(interface{ MissingRequired() []string })
+	if ok {
+		missing = checker.MissingRequired()
+	} else {
+		missing = missingRequired(reflect.ValueOf(doc))
+	}
+
 	if len(missing) == 0 {
 		return nil
 	}
@@ -81,3 +93,63 @@ func fieldName(field reflect.StructField) string {
 
 	return name
 }
+
+// ValidateDates reports every date field that is set but does not parse, named
+// as in the uploaded file. Like ValidateRequired it works from the struct, so a
+// new document type's dates are checked as soon as they are typed as Date.
+func ValidateDates(doc any) error {
+	var invalid []string
+
+	for name, date := range DateFields(doc) {
+		if !date.Valid() {
+			invalid = append(invalid, fmt.Sprintf("%s (%q)", name, date))
+		}
+	}
+
+	if len(invalid) == 0 {
+		return nil
+	}
+
+	slices.Sort(invalid)
+
+	return fmt.Errorf("not a date such as 2026-01-02, Jan 2026 or 2026: %s", strings.Join(invalid, ", "))
+}
+
+// DateFields returns the document's Date fields by their yaml name.
+func DateFields(doc any) map[string]Date {
+	fields := map[string]Date{}
+	collectDates(reflect.ValueOf(doc), fields)
+
+	return fields
+}
+
+func collectDates(v reflect.Value, fields map[string]Date) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	structType := v.Type()
+	dateType := reflect.TypeFor[Date]()
+
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+
+		if field.Anonymous {
+			collectDates(v.Field(i), fields)
+
+			continue
+		}
+
+		if field.Type == dateType {
+			fields[fieldName(field)] = v.Field(i).Interface().(Date)
+		}
+	}
+}
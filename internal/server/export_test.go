@@ -1,6 +1,10 @@
 package server
 
-import "net/http"
+import (
+	"net/http"
+
+	"timterests/internal/auth"
+)
 
 func (s *Server) MaxBytesMiddleware(next http.Handler) http.Handler {
 	return s.maxBytesMiddleware(next)
@@ -9,3 +13,7 @@ func (s *Server) MaxBytesMiddleware(next http.Handler) http.Handler {
 func StaticCacheMiddleware(next http.Handler) http.Handler {
 	return staticCacheMiddleware(next)
 }
+
+func (s *Server) SetAuth(a *auth.Auth) {
+	s.auth = a
+}
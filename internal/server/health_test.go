@@ -15,10 +15,7 @@ func TestHealthHandlerOK(t *testing.T) {
 	isolateWorkingDir(t)
 
 	s := &server.Server{
-		Storage: &storage.Storage{
-			UseS3:   false,
-			BaseDir: t.TempDir(),
-		},
+		Storage: storage.NewLocalBackend(t.TempDir()),
 	}
 
 	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/health", nil)
@@ -59,10 +56,7 @@ func TestHealthHandlerDegraded(t *testing.T) {
 	t.Chdir(t.TempDir())
 
 	s := &server.Server{
-		Storage: &storage.Storage{
-			UseS3:   false,
-			BaseDir: filepath.Join(t.TempDir(), "does-not-exist"),
-		},
+		Storage: storage.NewLocalBackend(filepath.Join(t.TempDir(), "does-not-exist")),
 	}
 
 	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/health", nil)
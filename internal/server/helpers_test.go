@@ -2,8 +2,22 @@ package server_test
 
 import (
 	"testing"
+
+	"timterests/internal/model"
 )
 
+// testDocumentTypes declares a custom type, whose routes the server adds.
+func testDocumentTypes(t *testing.T) []model.DocumentSchema {
+	t.Helper()
+
+	schemas, err := model.ParseSchemas([]byte("types:\n  - name: recipes\n"))
+	if err != nil {
+		t.Fatalf("failed to parse document types: %v", err)
+	}
+
+	return schemas
+}
+
 // isolateWorkingDir moves the test into an empty directory so anything resolved
 // relative to the working directory cannot pick up the developer's real files.
 func isolateWorkingDir(t *testing.T) {
@@ -6,12 +6,14 @@ import (
 	"log"
 	"net/http"
 	"path"
-	"strconv"
+	"slices"
 	"strings"
 
 	"timterests/cmd/web"
 	"timterests/internal/auth"
 	apperrors "timterests/internal/errors"
+	"timterests/internal/model"
+	"timterests/internal/storage"
 )
 
 // Asset cache lifetimes. Filenames are not content-hashed, so changes only
@@ -28,13 +30,67 @@ const (
 
 // RegisterRoutes configures all HTTP routes and returns the handler.
 func (s *Server) RegisterRoutes() http.Handler {
-	mux := http.NewServeMux()
+	mux := s.routes()
+
+	// Wrap: recovery is outermost so it catches panics from all inner middleware.
+	return recoveryMiddleware(
+		securityHeadersMiddleware(
+			s.corsMiddleware(s.maxBytesMiddleware(s.authContextMiddleware(mux))),
+		),
+	)
+}
+
+// routeMux is a ServeMux that remembers the first path segment of every
+// pattern. Each one is a name a schema-declared type cannot take, since its
+// routes would conflict; customRoutes turns such a type away.
+type routeMux struct {
+	*http.ServeMux
+
+	segments map[string]bool
+}
+
+func newRouteMux() *routeMux {
+	return &routeMux{ServeMux: http.NewServeMux(), segments: make(map[string]bool)}
+}
+
+// Handle implements the ServeMux method, recording the pattern's segment.
+func (m *routeMux) Handle(pattern string, handler http.Handler) {
+	m.segments[firstSegment(pattern)] = true
+	m.ServeMux.Handle(pattern, handler)
+}
+
+// HandleFunc implements the ServeMux method, recording the pattern's segment.
+func (m *routeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.Handle(pattern, http.HandlerFunc(handler))
+}
+
+// firstSegment returns the first segment of the path in pattern, "articles"
+// for "GET /articles/{slug}".
+func firstSegment(pattern string) string {
+	_, urlPath, ok := strings.Cut(pattern, " ")
+	if !ok {
+		urlPath = pattern
+	}
+
+	segment, _, _ := strings.Cut(strings.TrimPrefix(urlPath, "/"), "/")
+
+	return segment
+}
+
+// routes registers every route on a new mux.
+func (s *Server) routes() *routeMux {
+	mux := newRouteMux()
 
 	// Favicon Route
 	mux.Handle("/favicon.ico", http.FileServer(http.Dir(".")))
 
-	// Serve static files from the "storage" directory
-	mux.Handle("/storage/", http.StripPrefix("/storage/", http.FileServer(http.Dir("storage"))))
+	// Serve images from the "storage" directory. Nothing else there is public:
+	// it also holds drafts, letters and the access tokens.
+	mux.Handle("/storage/", imagesOnly(http.StripPrefix("/storage/", http.FileServer(http.Dir("storage")))))
+
+	// The code highlighting stylesheet is generated rather than embedded, but is
+	// cached like the other stylesheets.
+	mux.Handle("/assets/css/highlight.css", staticCacheMiddleware(http.HandlerFunc(web.HighlightCSSHandler)))
 
 	// Serve static files from the "web" directory
 	fileServer := http.FileServer(http.FS(web.Files))
@@ -42,16 +98,16 @@ func (s *Server) RegisterRoutes() http.Handler {
 
 	// Home Routes
 	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.HomeHandler(w, r, *s.Storage)
+		web.HomeHandler(w, r, s.Storage)
 	}))
 	mux.Handle("/home", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.HomeHandler(w, r, *s.Storage)
+		web.HomeHandler(w, r, s.Storage)
 	}))
 	mux.Handle("/web", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.HomeHandler(w, r, *s.Storage)
+		web.HomeHandler(w, r, s.Storage)
 	}))
 	mux.Handle("/web/home", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.HomeHandler(w, r, *s.Storage)
+		web.HomeHandler(w, r, s.Storage)
 	}))
 
 	mux.Handle("/admin", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -59,16 +115,58 @@ func (s *Server) RegisterRoutes() http.Handler {
 	}))
 
 	mux.Handle("/admin/documents", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.AdminDocumentsPageHandler(w, r, *s.Storage, s.auth)
+		web.AdminDocumentsPageHandler(w, r, s.Storage, s.auth)
 	}))
 
 	mux.Handle("/admin/documents/delete", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.DeleteDocumentHandler(w, r, *s.Storage, s.auth)
+		web.DeleteDocumentHandler(w, r, s.Storage, s.auth)
+	}))
+
+	mux.Handle("/admin/documents/status", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.SetDocumentStatusHandler(w, r, s.Storage, s.auth)
+	}))
+
+	mux.Handle("/admin/revisions", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.RevisionsPageHandler(w, r, s.Storage, s.auth)
+	}))
+
+	mux.Handle("/admin/revisions/diff", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.RevisionDiffHandler(w, r, s.Storage, s.auth)
+	}))
+
+	mux.Handle("/admin/revisions/restore", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.RestoreRevisionHandler(w, r, s.Storage, s.auth)
+	}))
+
+	mux.Handle("/admin/trash", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.TrashPageHandler(w, r, s.Storage, s.auth)
+	}))
+
+	mux.Handle("/admin/trash/restore", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.RestoreTrashHandler(w, r, s.Storage, s.auth)
+	}))
+
+	mux.Handle("/admin/trash/purge", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.PurgeTrashHandler(w, r, s.Storage, s.auth)
+	}))
+
+	mux.Handle("/admin/tokens", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			web.CreateTokenHandler(w, r, s.Storage, s.auth)
+
+			return
+		}
+
+		web.TokensPageHandler(w, r, s.Storage, s.auth)
+	}))
+
+	mux.Handle("/admin/tokens/revoke", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.RevokeTokenHandler(w, r, s.Storage, s.auth)
 	}))
 
 	mux.Handle("/admin/upload", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
-			web.UploadDocumentHandler(w, r, *s.Storage, s.auth)
+			web.UploadDocumentHandler(w, r, s.Storage, s.auth)
 
 			return
 		}
@@ -76,11 +174,28 @@ func (s *Server) RegisterRoutes() http.Handler {
 		web.UploadPageHandler(w, r, s.auth)
 	}))
 
+	mux.Handle("/admin/import", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			web.ImportBooksHandler(w, r, s.Storage, s.auth)
+
+			return
+		}
+
+		web.ImportBooksPageHandler(w, r, s.auth)
+	}))
+
+	mux.Handle("/admin/clippings", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			web.ImportClippingsHandler(w, r, s.Storage, s.auth)
+
+			return
+		}
+
+		web.ImportClippingsPageHandler(w, r, s.auth)
+	}))
+
 	mux.Handle("/writer", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		var (
-			docType, key string
-			typeID       int
-		)
+		var docType, key string
 
 		err := r.ParseForm()
 		if err != nil {
@@ -94,30 +209,18 @@ func (s *Server) RegisterRoutes() http.Handler {
 			docType = "articles" // default
 		}
 
-		typeIDString := r.FormValue("type-id")
-		if typeIDString != "" {
-			var err error
-
-			typeID, err = strconv.Atoi(typeIDString)
-			if err != nil {
-				web.HandleError(w, r, apperrors.BadRequest(err), "writer", "parseTypeID")
-
-				return
-			}
-		}
-
 		key = r.FormValue("document-key")
 
-		web.WriterPageHandler(w, r, *s.Storage, docType, key, typeID, s.auth)
+		web.WriterPageHandler(w, r, s.Storage, docType, key, s.auth)
 	}))
 
 	mux.Handle("/write", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.WriteDocumentHandler(w, r, *s.Storage, s.auth)
+		web.WriteDocumentHandler(w, r, s.Storage, s.auth)
 	}))
 
 	mux.Handle("/download", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		documentKey := r.URL.Query().Get("key")
-		web.DownloadDocumentHandler(w, r, *s.Storage, documentKey, s.auth)
+		web.DownloadDocumentHandler(w, r, s.Storage, documentKey, s.auth)
 	}))
 
 	mux.Handle("/download/new", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -127,18 +230,64 @@ func (s *Server) RegisterRoutes() http.Handler {
 	// SEO
 	mux.HandleFunc("/robots.txt", web.RobotsHandler)
 	mux.Handle("/sitemap.xml", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.SitemapHandler(w, r, *s.Storage)
+		web.SitemapHandler(w, r, s.Storage)
+	}))
+	mux.Handle("GET /sitemaps/{file}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutSuffix(r.PathValue("file"), ".xml")
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		web.SitemapSectionHandler(w, r, s.Storage, name)
 	}))
 	mux.Handle("/rss.xml", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.RSSHandler(w, r, *s.Storage)
+		web.RSSHandler(w, r, s.Storage)
 	}))
+	mux.Handle("GET /og/{type}/{file}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug, ok := strings.CutSuffix(r.PathValue("file"), ".png")
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		web.OGImageHandler(w, r, s.Storage, r.PathValue("type"), slug)
+	}))
+
+	// Feeds: the site's article feeds, each feed type's under its page, and
+	// everything carrying a tag.
+	for _, format := range []string{web.FeedRSS, web.FeedAtom, web.FeedJSON} {
+		if format != web.FeedRSS {
+			mux.Handle("GET "+web.FeedPath("", format), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				web.FeedHandler(w, r, s.Storage, "articles", format)
+			}))
+		}
+
+		for _, docType := range []string{"projects", "reading-list"} {
+			mux.Handle("GET "+web.FeedPath("/"+docType, format), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				web.FeedHandler(w, r, s.Storage, docType, format)
+			}))
+		}
+
+		mux.Handle("GET "+web.FeedPath("/tags/{tag}", format), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			web.TagFeedHandler(w, r, s.Storage, r.PathValue("tag"), format)
+		}))
+	}
 
 	// Health check
 	mux.HandleFunc("/health", s.HealthHandler)
 
 	// About Routes
 	mux.Handle("/about", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		web.AboutHandler(w, r, *s.Storage)
+		web.AboutHandler(w, r, s.Storage)
+	}))
+
+	// Search Routes
+	mux.Handle("/search", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		web.SearchPageHandler(w, r, s.Storage, query, s.auth)
 	}))
 
 	// Login Routes
@@ -159,48 +308,141 @@ func (s *Server) RegisterRoutes() http.Handler {
 	mux.Handle("/articles", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		design := r.URL.Query().Get("design")
 		tag := r.URL.Query().Get("tag")
-		web.ArticlesPageHandler(w, r, *s.Storage, tag, design)
+		web.ArticlesPageHandler(w, r, s.Storage, tag, design)
+	}))
+	mux.Handle("GET /articles/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.GetArticleHandler(w, r, s.Storage, r.PathValue("slug"), s.auth)
 	}))
 	mux.Handle("/article", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		articleID := r.URL.Query().Get("id")
-		web.GetArticleHandler(w, r, *s.Storage, articleID, s.auth)
+		web.LegacyRedirectHandler(w, r, s.Storage, "articles", s.auth)
+	}))
+	mux.Handle("GET /series/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.SeriesPageHandler(w, r, s.Storage, r.PathValue("slug"))
 	}))
 	// Projects Routes
 	mux.Handle("/projects", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		design := r.URL.Query().Get("design")
 		tag := r.URL.Query().Get("tag")
-		web.ProjectsPageHandler(w, r, *s.Storage, tag, design)
+		web.ProjectsPageHandler(w, r, s.Storage, tag, design)
+	}))
+	mux.Handle("GET /projects/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.GetProjectHandler(w, r, s.Storage, r.PathValue("slug"), s.auth)
 	}))
 	mux.Handle("/project", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		projectID := r.URL.Query().Get("id")
-		web.GetProjectHandler(w, r, *s.Storage, projectID, s.auth)
+		web.LegacyRedirectHandler(w, r, s.Storage, "projects", s.auth)
 	}))
 	// Reading List Routes
 	mux.Handle("/reading-list", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		design := r.URL.Query().Get("design")
 		tag := r.URL.Query().Get("tag")
-		web.ReadingListPageHandler(w, r, *s.Storage, tag, design)
+		status := r.URL.Query().Get("status")
+		sortBy := r.URL.Query().Get("sort")
+		web.ReadingListPageHandler(w, r, s.Storage, tag, status, sortBy, design)
+	}))
+	mux.Handle("/quotes", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.QuotesPageHandler(w, r, s.Storage, r.URL.Query().Get("tag"))
+	}))
+	mux.Handle("GET /reading-list/stats", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.ReadingStatsPageHandler(w, r, s.Storage)
+	}))
+	mux.Handle("GET /books/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.GetReadingListBook(w, r, s.Storage, r.PathValue("slug"), s.auth)
 	}))
 	mux.Handle("/book", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		articleID := r.URL.Query().Get("id")
-		web.GetReadingListBook(w, r, *s.Storage, articleID, s.auth)
+		web.LegacyRedirectHandler(w, r, s.Storage, "reading-list", s.auth)
 	}))
 	// Letter Routes
 	mux.Handle("/letters", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		design := r.URL.Query().Get("design")
 		tag := r.URL.Query().Get("tag")
-		web.LettersPageHandler(w, r, *s.Storage, tag, design, s.auth)
+		web.LettersPageHandler(w, r, s.Storage, tag, design, s.auth)
+	}))
+	mux.Handle("GET /letters/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.GetLetterHandler(w, r, s.Storage, r.PathValue("slug"), s.auth)
 	}))
 	mux.Handle("/letter", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		letterID := r.URL.Query().Get("id")
-		web.GetLetterHandler(w, r, *s.Storage, letterID, s.auth)
+		web.LegacyRedirectHandler(w, r, s.Storage, "letters", s.auth)
 	}))
-	// Wrap: recovery is outermost so it catches panics from all inner middleware.
-	return recoveryMiddleware(
-		securityHeadersMiddleware(
-			s.corsMiddleware(s.maxBytesMiddleware(s.authContextMiddleware(mux))),
-		),
-	)
+	// JSON API Routes
+	mux.Handle("GET "+web.APIPrefix+"/articles", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APIArticlesHandler(w, r, s.Storage, s.auth)
+	}))
+	mux.Handle("GET "+web.APIPrefix+"/articles/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APIArticleHandler(w, r, s.Storage, r.PathValue("slug"), s.auth)
+	}))
+	mux.Handle("GET "+web.APIPrefix+"/projects", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APIProjectsHandler(w, r, s.Storage, s.auth)
+	}))
+	mux.Handle("GET "+web.APIPrefix+"/projects/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APIProjectHandler(w, r, s.Storage, r.PathValue("slug"), s.auth)
+	}))
+	mux.Handle("GET "+web.APIPrefix+"/books", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APIBooksHandler(w, r, s.Storage, s.auth)
+	}))
+	mux.Handle("GET "+web.APIPrefix+"/books/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APIBookHandler(w, r, s.Storage, r.PathValue("slug"), s.auth)
+	}))
+	mux.Handle("GET "+web.APIPrefix+"/letters", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APILettersHandler(w, r, s.Storage, s.auth)
+	}))
+	mux.Handle("GET "+web.APIPrefix+"/letters/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APILetterHandler(w, r, s.Storage, r.PathValue("slug"), s.auth)
+	}))
+	mux.Handle("GET "+web.APIPrefix+"/about", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APIAboutHandler(w, r, s.Storage)
+	}))
+
+	mux.Handle("POST "+web.APIPrefix+"/{type}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APICreateDocumentHandler(w, r, s.Storage, r.PathValue("type"), s.auth)
+	}))
+	mux.Handle("PUT "+web.APIPrefix+"/{type}/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APIUpdateDocumentHandler(w, r, s.Storage, r.PathValue("type"), r.PathValue("slug"), s.auth)
+	}))
+	mux.Handle("DELETE "+web.APIPrefix+"/{type}/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		web.APIDeleteDocumentHandler(w, r, s.Storage, r.PathValue("type"), r.PathValue("slug"), s.auth)
+	}))
+
+	mux.HandleFunc("/api/", web.APINotFoundHandler)
+
+	// Schema-declared types come last, so a type named after a route above
+	// can be turned away rather than clash with it.
+	web.SetCustomTypes(s.customRoutes(mux))
+
+	return mux
+}
+
+// customRoutes registers the routes of each schema-declared type and returns
+// the types served. A type whose name an earlier route already uses is logged
+// and left out: its routes would conflict.
+func (s *Server) customRoutes(mux *routeMux) []model.DocumentSchema {
+	served := make([]model.DocumentSchema, 0, len(s.DocumentTypes))
+
+	for _, schema := range s.DocumentTypes {
+		if mux.segments[schema.Name] {
+			log.Printf("ignoring document type %q: the name is already in use by a route", schema.Name)
+
+			continue
+		}
+
+		mux.Handle("/"+schema.Name, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			design := r.URL.Query().Get("design")
+			tag := r.URL.Query().Get("tag")
+			web.CustomListPageHandler(w, r, s.Storage, &schema, tag, design)
+		}))
+		mux.Handle("GET /"+schema.Name+"/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			web.GetCustomHandler(w, r, s.Storage, &schema, r.PathValue("slug"), s.auth)
+		}))
+		mux.Handle("GET "+web.APIPrefix+"/"+schema.Name, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			web.APICustomListHandler(w, r, s.Storage, &schema, s.auth)
+		}))
+		mux.Handle("GET "+web.APIPrefix+"/"+schema.Name+"/{slug}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			web.APICustomHandler(w, r, s.Storage, &schema, r.PathValue("slug"), s.auth)
+		}))
+
+		served = append(served, schema)
+	}
+
+	return served
 }
 
 // authContextMiddleware resolves the request's auth state once and stores it in
@@ -280,6 +522,22 @@ func staticCacheMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// imageExtensions are the files imagesOnly lets through.
+var imageExtensions = []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".svg", ".ico"}
+
+// imagesOnly serves only requests for image files, and not found for the rest.
+func imagesOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(imageExtensions, strings.ToLower(path.Ext(r.URL.Path))) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // cacheControlFor picks a lifetime from the asset's extension. Images and fonts
 // change far less often than CSS and JS.
 func cacheControlFor(urlPath string) string {
@@ -311,8 +569,8 @@ func (s *Server) HelloWorldHandler(w http.ResponseWriter, _ *http.Request) {
 }
 
 // HealthHandler responds with the current health status of the application.
-func (s *Server) HealthHandler(w http.ResponseWriter, _ *http.Request) {
-	result := s.Storage.Health()
+func (s *Server) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	result := storage.Health(r.Context(), s.Storage)
 
 	resp, err := json.Marshal(result)
 	if err != nil {
@@ -4,9 +4,15 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"timterests/cmd/web"
+	"timterests/internal/auth"
+	"timterests/internal/model"
 	"timterests/internal/server"
 	"timterests/internal/storage"
 )
@@ -59,10 +65,7 @@ func TestSecurityHeaders(t *testing.T) {
 	isolateWorkingDir(t)
 
 	s := &server.Server{
-		Storage: &storage.Storage{
-			UseS3:   false,
-			BaseDir: t.TempDir(),
-		},
+		Storage: storage.NewLocalBackend(t.TempDir()),
 	}
 
 	svr := httptest.NewServer(s.RegisterRoutes())
@@ -101,16 +104,66 @@ func TestSecurityHeaders(t *testing.T) {
 	}
 }
 
+// The storage directory is served for its images; the tokens, drafts and
+// letters beside them are not public.
+func TestStorageServesOnlyImages(t *testing.T) {
+	isolateWorkingDir(t)
+
+	for name, content := range map[string]string{
+		"storage/auth/tokens.yaml":       "tokens: []\n",
+		"storage/letters/test-letter.md": "Dear reader\n",
+		"storage/images/test.png":        "png",
+	} {
+		err := os.MkdirAll(filepath.Dir(name), 0o750)
+		if err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+
+		err = os.WriteFile(name, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	s := &server.Server{
+		Storage: storage.NewLocalBackend("storage"),
+	}
+	s.SetAuth(auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!"))
+
+	svr := httptest.NewServer(s.RegisterRoutes())
+	defer svr.Close()
+
+	for path, want := range map[string]int{
+		"/storage/auth/tokens.yaml":       http.StatusNotFound,
+		"/storage/letters/test-letter.md": http.StatusNotFound,
+		"/storage/auth/":                  http.StatusNotFound,
+		"/storage/images/test.png":        http.StatusOK,
+	} {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, svr.URL+path, nil)
+		if err != nil {
+			t.Fatalf("error creating request: %v", err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("error making request to %s: %v", path, err)
+		}
+
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != want {
+			t.Errorf("%s: expected %d, got %d", path, want, resp.StatusCode)
+		}
+	}
+}
+
 func TestCORSPreflight(t *testing.T) {
 	isolateWorkingDir(t)
 
 	t.Setenv("SITE_URL", "https://example.com")
 
 	s := &server.Server{
-		Storage: &storage.Storage{
-			UseS3:   false,
-			BaseDir: t.TempDir(),
-		},
+		Storage: storage.NewLocalBackend(t.TempDir()),
 	}
 
 	svr := httptest.NewServer(s.RegisterRoutes())
@@ -171,10 +224,8 @@ func TestHelloWorldContentType(t *testing.T) {
 
 func TestRegisterRoutesEndpoints(t *testing.T) {
 	s := &server.Server{
-		Storage: &storage.Storage{
-			UseS3:   false,
-			BaseDir: t.TempDir(),
-		},
+		Storage:       storage.NewLocalBackend(t.TempDir()),
+		DocumentTypes: testDocumentTypes(t),
 	}
 
 	svr := httptest.NewServer(s.RegisterRoutes())
@@ -190,11 +241,23 @@ func TestRegisterRoutesEndpoints(t *testing.T) {
 		"/reading-list",
 		"/login",
 		"/sitemap.xml",
+		"/sitemaps/articles.xml",
+		"/rss.xml",
+		"/og/articles/test-article.png",
+		"/atom.xml",
+		"/feed.json",
+		"/projects/feed.xml",
+		"/reading-list/atom.xml",
+		"/tags/tag1/feed.json",
+		"/assets/css/highlight.css",
 		"/about",
+		"/search?q=test",
 		"/writer",
-		"/writer?type-id=invalid",
 		"/admin",
 		"/admin/documents",
+		"/admin/revisions?key=articles/test-article.yaml",
+		"/admin/trash",
+		"/admin/tokens",
 		"/admin/users",
 		"/admin/users/create",
 		"/write",
@@ -206,6 +269,22 @@ func TestRegisterRoutesEndpoints(t *testing.T) {
 		"/book",
 		"/letter",
 		"/letters",
+		"/articles/does-not-exist",
+		"/projects/does-not-exist",
+		"/books/does-not-exist",
+		"/letters/does-not-exist",
+		"/series/does-not-exist",
+		"/reading-list/stats",
+		"/admin/import",
+		"/admin/clippings",
+		"/quotes",
+		"/recipes",
+		"/api/v1/articles",
+		"/api/v1/articles/does-not-exist",
+		"/api/v1/projects",
+		"/api/v1/books",
+		"/api/v1/about",
+		"/api/v1/nothing-here",
 	}
 
 	for _, path := range endpoints {
@@ -229,12 +308,59 @@ func TestRegisterRoutesEndpoints(t *testing.T) {
 	}
 }
 
+// A schema type named after a built-in route would register a conflicting
+// pattern and panic at startup, so it is turned away when the routes are built.
+func TestSchemaTypeNamedAfterRoute(t *testing.T) {
+	schemas, err := model.ParseSchemas([]byte(`types:
+  - name: recipes
+  - name: quotes
+  - name: sitemaps
+  - name: api
+`))
+	if err != nil {
+		t.Fatalf("failed to parse document types: %v", err)
+	}
+
+	s := &server.Server{
+		Storage:       storage.NewLocalBackend(t.TempDir()),
+		DocumentTypes: schemas,
+	}
+	s.SetAuth(auth.NewAuth("test-session", "test-signing-key-at-least-32-chars!!"))
+
+	svr := httptest.NewServer(s.RegisterRoutes())
+	defer svr.Close()
+
+	var names []string
+	for _, schema := range web.CustomTypes() {
+		names = append(names, schema.Name)
+	}
+
+	if !slices.Equal(names, []string{"recipes"}) {
+		t.Errorf("expected only recipes to be served, got %v", names)
+	}
+
+	for _, path := range []string{"/recipes", "/quotes"} {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, svr.URL+path, nil)
+		if err != nil {
+			t.Fatalf("error creating request: %v", err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("error making request to %s: %v", path, err)
+		}
+
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected 200 from %s, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
 func TestRecoveryMiddlewarePanic(t *testing.T) {
 	s := &server.Server{
-		Storage: &storage.Storage{
-			UseS3:   false,
-			BaseDir: t.TempDir(),
-		},
+		Storage: storage.NewLocalBackend(t.TempDir()),
 		// auth is nil — /letters calls a.IsAuthenticated → nil deref → panic
 	}
 
@@ -262,12 +388,18 @@ func TestNewServer(t *testing.T) {
 	t.Setenv("PORT", "18080")
 	t.Setenv("SESSION_NAME", "test-session")
 	t.Setenv("SESSION_KEY", "test-signing-key-at-least-32-chars!!")
+	// The default local backend would be the repository's own storage/ tree,
+	// which the background jobs must never touch.
+	t.Setenv("STORAGE_BACKEND", "memory")
 
-	svr := server.NewServer()
+	svr, startJobs := server.NewServer()
 	if svr == nil {
 		t.Fatal("expected non-nil server")
 	}
 
+	// The jobs stop with the test's context.
+	startJobs(t.Context())
+
 	if svr.Addr != ":18080" {
 		t.Errorf("expected addr :18080, got %s", svr.Addr)
 	}
@@ -279,6 +411,7 @@ func TestNewServerRejectsWeakSessionKey(t *testing.T) {
 	t.Setenv("PORT", "18081")
 	t.Setenv("SESSION_NAME", "test-session")
 	t.Setenv("SESSION_KEY", "too-short")
+	t.Setenv("STORAGE_BACKEND", "memory")
 
 	defer func() {
 		if recover() == nil {
@@ -286,5 +419,5 @@ func TestNewServerRejectsWeakSessionKey(t *testing.T) {
 		}
 	}()
 
-	server.NewServer()
+	_, _ = server.NewServer()
 }
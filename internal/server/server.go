@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
@@ -12,30 +13,39 @@ import (
 	_ "github.com/joho/godotenv/autoload"
 
 	"timterests/internal/auth"
+	"timterests/internal/model"
+	"timterests/internal/service"
 	"timterests/internal/storage"
 )
 
 // Server provides HTTP server configuration with storage backend.
 type Server struct {
 	port    int
-	Storage *storage.Storage
-	auth    *auth.Auth
-	oidc    *auth.OIDC
+	Storage storage.Backend
+	// DocumentTypes are the schema-declared document types to serve.
+	DocumentTypes []model.DocumentSchema
+	auth          *auth.Auth
+	oidc          *auth.OIDC
 }
 
-// NewServer creates and configures a new HTTP server instance.
-func NewServer() *http.Server {
+// NewServer creates and configures a new HTTP server instance. Its background
+// jobs are left to the caller: the returned function starts them, and they run
+// until the context it is given is done.
+func NewServer() (*http.Server, func(ctx context.Context)) {
 	port, err := strconv.Atoi(os.Getenv("PORT"))
 	if err != nil {
 		panic(fmt.Sprintf("failed to parse PORT: %v", err))
 	}
 
-	// Initialize Storage (handles both S3 and local)
-	store, err := storage.NewStorage(context.Background())
+	// Initialize the storage backend selected by STORAGE_BACKEND
+	backend, err := storage.NewBackend(context.Background())
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize storage: %v", err))
 	}
 
+	// Serve reads from memory, rescanning for changes made outside the app
+	store := service.NewIndex(backend)
+
 	// Initialize Auth. A weak signing key is refused outright: it would let anyone
 	// forge a session cookie and bypass sign-in entirely.
 	sessionKey := os.Getenv("SESSION_KEY")
@@ -49,10 +59,11 @@ func NewServer() *http.Server {
 	authInstance := auth.NewAuth(os.Getenv("SESSION_NAME"), sessionKey)
 
 	NewServer := &Server{
-		port:    port,
-		Storage: store,
-		auth:    authInstance,
-		oidc:    auth.NewOIDC(auth.OIDCConfigFromEnv(), authInstance),
+		port:          port,
+		Storage:       store,
+		DocumentTypes: documentTypes(),
+		auth:          authInstance,
+		oidc:          auth.NewOIDC(auth.OIDCConfigFromEnv(), authInstance),
 	}
 
 	// Declare Server config
@@ -64,5 +75,49 @@ func NewServer() *http.Server {
 		WriteTimeout: 30 * time.Second,
 	}
 
-	return server
+	startJobs := func(ctx context.Context) {
+		go store.Run(ctx, rescanInterval())
+
+		// Empty the trash of anything older than the retention period
+		retention := storage.TrashRetention()
+		if retention > 0 {
+			go storage.RunTrashPurge(ctx, store, retention)
+		}
+	}
+
+	return server, startJobs
+}
+
+// documentTypes loads the custom document types declared in the file named by
+// DOCUMENT_TYPES_FILE. A file that cannot be used is logged and ignored, so a
+// mistake in it does not take the site down.
+func documentTypes() []model.DocumentSchema {
+	path := os.Getenv("DOCUMENT_TYPES_FILE")
+
+	schemas, err := service.LoadDocumentTypes(path)
+	if err != nil {
+		log.Printf("ignoring DOCUMENT_TYPES_FILE %q: %v", path, err)
+
+		return nil
+	}
+
+	return schemas
+}
+
+// rescanInterval reads INDEX_RESCAN_INTERVAL as a Go duration such as "5m",
+// falling back to the default when it is unset or unusable.
+func rescanInterval() time.Duration {
+	value := os.Getenv("INDEX_RESCAN_INTERVAL")
+	if value == "" {
+		return service.DefaultRescanInterval
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		log.Printf("ignoring INDEX_RESCAN_INTERVAL %q: want a positive duration such as 5m", value)
+
+		return service.DefaultRescanInterval
+	}
+
+	return interval
 }
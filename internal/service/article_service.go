@@ -5,85 +5,66 @@ package service
 import (
 	"context"
 	"errors"
-	"fmt"
-	"log"
 	"slices"
 	"sort"
-	"strings"
 	"time"
 	"timterests/internal/model"
 	"timterests/internal/storage"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
 )
 
 // ListArticles retrieves all articles from storage, optionally filtering by tag.
 // Pass tag="" or tag="all" to retrieve all articles.
-func ListArticles(ctx context.Context, s storage.Storage, tag string) ([]model.Article, error) {
+func ListArticles(ctx context.Context, s storage.Backend, tag string) ([]model.Article, error) {
 	var articles []model.Article
 
-	prefix := "articles/"
-
-	articleFiles, err := s.ListObjects(ctx, prefix)
+	keys, err := documentKeys(ctx, s, ArticlesPrefix)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list objects with prefix %q: %w", prefix, err)
-	}
-
-	mdKeys := make(map[string]bool, len(articleFiles))
-	for _, obj := range articleFiles {
-		key := aws.ToString(obj.Key)
-		if strings.HasSuffix(key, ".md") {
-			mdKeys[key] = true
-		}
+		return nil, err
 	}
 
-	docIdx := 0
-
-	for _, obj := range articleFiles {
-		key := aws.ToString(obj.Key)
-
-		if key == prefix || !strings.HasSuffix(key, ".yaml") {
-			continue
-		}
-
-		if !mdKeys[strings.TrimSuffix(key, ".yaml")+".md"] {
-			log.Printf("ListArticles: skipping %s — no paired .md body file", key)
-
-			continue
-		}
-
-		article, err := GetArticle(ctx, s, key, docIdx)
+	for _, key := range keys {
+		article, err := GetArticle(ctx, s, key)
 		if err != nil {
 			return nil, err
 		}
 
-		docIdx++
-
 		if tag == "all" || tag == "" || slices.Contains(article.Tags, tag) {
 			articles = append(articles, *article)
 		}
 	}
 
 	sort.Slice(articles, func(i, j int) bool {
-		return articles[i].Date > articles[j].Date
+		return articles[i].Date.Compare(articles[j].Date) > 0
 	})
 
 	return articles, nil
 }
 
-// GetArticle retrieves a single article by its storage key and numeric ID.
-func GetArticle(ctx context.Context, s storage.Storage, key string, id int) (*model.Article, error) {
-	return getDoc[model.Article](ctx, s, key, id)
+// GetArticle retrieves a single article by its storage key.
+func GetArticle(ctx context.Context, s storage.Backend, key string) (*model.Article, error) {
+	return getDoc[model.Article](ctx, s, key)
+}
+
+// GetArticleBySlug retrieves the article addressed by slug, as used in its permalink.
+func GetArticleBySlug(ctx context.Context, s storage.Backend, slug string) (*model.Article, error) {
+	key, err := documentKey(ArticlesPrefix, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetArticle(ctx, s, key)
 }
 
-// GetLatestArticle retrieves the most recently dated article from storage.
-// Articles are sorted by date descending; the first one is the latest.
-func GetLatestArticle(ctx context.Context, s storage.Storage) (*model.Article, error) {
+// GetLatestArticle retrieves the most recently dated published article from
+// storage. Articles are sorted by date descending; the first one is the latest.
+func GetLatestArticle(ctx context.Context, s storage.Backend) (*model.Article, error) {
 	articles, err := ListArticles(ctx, s, "all")
 	if err != nil {
 		return nil, err
 	}
 
+	articles = Published(articles, time.Now())
+
 	if len(articles) == 0 {
 		return nil, errors.New("no articles found")
 	}
@@ -2,17 +2,21 @@ package service_test
 
 import (
 	"context"
+	"errors"
 	"slices"
 	"testing"
+	"time"
+	"timterests/internal/model"
 	"timterests/internal/service"
+	"timterests/internal/storage"
 )
 
 func TestListArticles(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("returns all articles when tag is empty", func(t *testing.T) {
-		articles, err := service.ListArticles(ctx, *s, "")
+		articles, err := service.ListArticles(ctx, s, "")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -23,7 +27,7 @@ func TestListArticles(t *testing.T) {
 	})
 
 	t.Run("returns all articles when tag is 'all'", func(t *testing.T) {
-		articles, err := service.ListArticles(ctx, *s, "all")
+		articles, err := service.ListArticles(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -34,7 +38,7 @@ func TestListArticles(t *testing.T) {
 	})
 
 	t.Run("filters articles by tag", func(t *testing.T) {
-		articles, err := service.ListArticles(ctx, *s, "tag1")
+		articles, err := service.ListArticles(ctx, s, "tag1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -51,7 +55,7 @@ func TestListArticles(t *testing.T) {
 	})
 
 	t.Run("returns empty slice for non-existent tag", func(t *testing.T) {
-		articles, err := service.ListArticles(ctx, *s, "does-not-exist")
+		articles, err := service.ListArticles(ctx, s, "does-not-exist")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -62,7 +66,7 @@ func TestListArticles(t *testing.T) {
 	})
 
 	t.Run("articles are sorted by date descending", func(t *testing.T) {
-		articles, err := service.ListArticles(ctx, *s, "all")
+		articles, err := service.ListArticles(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -77,16 +81,16 @@ func TestListArticles(t *testing.T) {
 
 func TestGetArticle(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
-	t.Run("retrieves article by key and id", func(t *testing.T) {
-		article, err := service.GetArticle(ctx, *s, "articles/test-article.yaml", 1)
+	t.Run("retrieves article by key", func(t *testing.T) {
+		article, err := service.GetArticle(ctx, s, "articles/test-article.yaml")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		if article.ID != "1" {
-			t.Errorf("expected ID '1', got %q", article.ID)
+		if article.ID != "test-article" {
+			t.Errorf("expected ID 'test-article', got %q", article.ID)
 		}
 
 		if article.Title != "Test Article" {
@@ -99,19 +103,111 @@ func TestGetArticle(t *testing.T) {
 	})
 
 	t.Run("returns error for non-existent key", func(t *testing.T) {
-		_, err := service.GetArticle(ctx, *s, "articles/does-not-exist.yaml", 0)
+		_, err := service.GetArticle(ctx, s, "articles/does-not-exist.yaml")
 		if err == nil {
 			t.Error("expected error for non-existent file, got nil")
 		}
 	})
 }
 
+func TestGetArticleBySlug(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+
+	t.Run("retrieves article by slug", func(t *testing.T) {
+		article, err := service.GetArticleBySlug(ctx, s, "test-article")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if article.ID != "test-article" {
+			t.Errorf("expected ID 'test-article', got %q", article.ID)
+		}
+	})
+
+	t.Run("rejects slugs that leave the articles directory", func(t *testing.T) {
+		for _, slug := range []string{"", ".", "..", "../letters/test-letter", `..\secret`} {
+			_, err := service.GetArticleBySlug(ctx, s, slug)
+			if !errors.Is(err, service.ErrInvalidSlug) {
+				t.Errorf("slug %q: expected ErrInvalidSlug, got %v", slug, err)
+			}
+		}
+	})
+}
+
+func TestSingleFileArticle(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+
+	content := "---\ntitle: Front Matter\ndate: \"2026-02-01\"\ntags:\n  - tag1\n---\n# Front Matter\n"
+
+	err := storage.WriteSingleFile(ctx, s, "articles/front-matter.yaml", []byte(content))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	articles, err := service.ListArticles(ctx, s, "tag1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := slices.ContainsFunc(articles, func(a model.Article) bool {
+		return a.Title == "Front Matter"
+	})
+	if !found {
+		t.Error("expected the single-file article to be listed")
+	}
+
+	article, err := service.GetArticleBySlug(ctx, s, "front-matter")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if article.Date != "2026-02-01" {
+		t.Errorf("expected date '2026-02-01', got %q", article.Date)
+	}
+}
+
+func TestLegacySlug(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+
+	t.Run("maps a numeric id to the slug at that position", func(t *testing.T) {
+		slug, err := service.LegacySlug(ctx, s, service.ArticlesPrefix, "0")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if slug != "test-article" {
+			t.Errorf("expected slug 'test-article', got %q", slug)
+		}
+	})
+
+	t.Run("passes a non-numeric id through as a slug", func(t *testing.T) {
+		slug, err := service.LegacySlug(ctx, s, service.ArticlesPrefix, "test-article")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if slug != "test-article" {
+			t.Errorf("expected slug 'test-article', got %q", slug)
+		}
+	})
+
+	t.Run("returns error for an out of range id", func(t *testing.T) {
+		_, err := service.LegacySlug(ctx, s, service.ArticlesPrefix, "99")
+		if err == nil {
+			t.Error("expected error for out of range id, got nil")
+		}
+	})
+}
+
 func TestGetLatestArticle(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("returns the most recent article", func(t *testing.T) {
-		article, err := service.GetLatestArticle(ctx, *s)
+		article, err := service.GetLatestArticle(ctx, s)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -145,3 +241,29 @@ func TestFormatArticleDateForFilename(t *testing.T) {
 		})
 	}
 }
+
+func TestPublished(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+
+	articles := []model.Article{
+		{Document: model.Document{ID: "live"}},
+		{Document: model.Document{ID: "draft", Status: model.StatusDraft}},
+		{Document: model.Document{ID: "scheduled", PublishAt: "2026-04-01"}},
+		{Document: model.Document{ID: "released", PublishAt: "2026-02-01"}},
+	}
+
+	got := service.Published(articles, now)
+
+	var slugs []string
+	for _, a := range got {
+		slugs = append(slugs, a.ID)
+	}
+
+	if !slices.Equal(slugs, []string{"live", "released"}) {
+		t.Errorf("expected [live released], got %v", slugs)
+	}
+
+	if len(articles) != 4 {
+		t.Errorf("expected the input to be left alone, got %d articles", len(articles))
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+	"timterests/internal/model"
+	"timterests/internal/storage"
+)
+
+// linkGraph records which documents link to which with wiki links.
+type linkGraph struct {
+	docs []model.Document
+	// types holds the type of each document in docs.
+	types []string
+	// linkedFrom maps a "type/slug" target to the documents linking to it,
+	// as positions in docs.
+	linkedFrom map[string][]int
+}
+
+// Backlinks lists the public documents whose bodies link to the document of
+// docType with slug, ordered by title.
+func Backlinks(ctx context.Context, s storage.Backend, docType, slug string, now time.Time) ([]model.DocumentLink, error) {
+	g, err := getLinkGraph(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []model.DocumentLink
+
+	for _, i := range g.linkedFrom[docType+"/"+slug] {
+		doc := g.docs[i]
+		if !doc.IsPublished(now) {
+			continue
+		}
+
+		links = append(links, model.DocumentLink{Title: doc.Title, URL: model.DocumentPath(g.types[i], doc.ID)})
+	}
+
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].Title < links[j].Title
+	})
+
+	return links, nil
+}
+
+// getLinkGraph returns the link graph for s. Behind an Index it is built once
+// and reused until any document changes.
+func getLinkGraph(ctx context.Context, s storage.Backend) (*linkGraph, error) {
+	idx, indexed := s.(*Index)
+	if !indexed {
+		return buildLinkGraph(ctx, s)
+	}
+
+	g, gen := idx.linkGraph()
+	if g != nil {
+		return g, nil
+	}
+
+	g, err := buildLinkGraph(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.storeLinkGraph(g, gen)
+
+	return g, nil
+}
+
+// buildLinkGraph scans the body of every linkable document for wiki links. A
+// bare slug counts against the first of storage.WikiLinkTypes that has it.
+func buildLinkGraph(ctx context.Context, s storage.Backend) (*linkGraph, error) {
+	g := &linkGraph{linkedFrom: make(map[string][]int)}
+
+	articles, err := ListArticles(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range articles {
+		g.add("articles", a.Document)
+	}
+
+	projects, err := ListProjects(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range projects {
+		g.add("projects", p.Document)
+	}
+
+	books, err := ListBooks(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range books {
+		g.add("reading-list", b.Document)
+	}
+
+	exists := make(map[string]bool, len(g.docs))
+	for i, doc := range g.docs {
+		exists[g.types[i]+"/"+doc.ID] = true
+	}
+
+	for i, doc := range g.docs {
+		body, err := storage.GetDocumentBodyRaw(ctx, s, doc.S3Key)
+		if err != nil {
+			continue
+		}
+
+		seen := make(map[string]bool)
+
+		for _, target := range storage.WikiLinkTargets([]byte(body)) {
+			key, ok := resolveTarget(target, exists)
+			if !ok || seen[key] || key == g.types[i]+"/"+doc.ID {
+				continue
+			}
+
+			seen[key] = true
+			g.linkedFrom[key] = append(g.linkedFrom[key], i)
+		}
+	}
+
+	return g, nil
+}
+
+func (g *linkGraph) add(docType string, doc model.Document) {
+	g.docs = append(g.docs, doc)
+	g.types = append(g.types, docType)
+}
+
+// resolveTarget returns the "type/slug" key a wiki link target names.
+func resolveTarget(target string, exists map[string]bool) (string, bool) {
+	docType, slug := storage.ParseWikiTarget(target)
+	if docType != "" {
+		key := docType + "/" + slug
+
+		return key, exists[key]
+	}
+
+	for _, t := range storage.WikiLinkTypes() {
+		key := t + "/" + slug
+		if exists[key] {
+			return key, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,50 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+func TestBacklinks(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	docs := map[string][2]string{
+		"articles/b-linker.yaml": {"title: B Linker\ndate: \"2026-01-01\"\n", "Read [[reading-list/test-book]] and [[test-book|again]]."},
+		"projects/a-linker.yaml": {"title: A Linker\n", "Built after [[test-book]]."},
+		"articles/draft.yaml":    {"title: Draft\ndate: \"2026-01-01\"\nstatus: draft\n", "[[test-book]]"},
+		"articles/coded.yaml":    {"title: Coded\ndate: \"2026-01-01\"\n", "`[[test-book]]`"},
+	}
+
+	for key, doc := range docs {
+		err := storage.WriteDocument(ctx, s, key, []byte(doc[0]), []byte(doc[1]))
+		if err != nil {
+			t.Fatalf("failed to seed %s: %v", key, err)
+		}
+	}
+
+	links, err := service.Backlinks(ctx, s, "reading-list", "test-book", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []model.DocumentLink{
+		{Title: "A Linker", URL: "/projects/a-linker"},
+		{Title: "B Linker", URL: "/articles/b-linker"},
+	}
+
+	if len(links) != len(want) {
+		t.Fatalf("expected %v, got %v", want, links)
+	}
+
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("backlink %d: expected %v, got %v", i, want[i], links[i])
+		}
+	}
+}
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"timterests/internal/model"
+	"timterests/internal/storage"
+)
+
+// Export formats ParseBookExport recognises.
+const (
+	ExportGoodreads  = "Goodreads"
+	ExportStoryGraph = "StoryGraph"
+)
+
+// shelfStates maps the reading-state shelves of Goodreads and StoryGraph to
+// reading states. These shelves say where a book is, so they are not tags.
+var shelfStates = map[string]string{
+	"read":              model.ReadingFinished,
+	"currently-reading": model.ReadingReading,
+	"to-read":           model.ReadingWantToRead,
+	"did-not-finish":    model.ReadingAbandoned,
+	"dnf":               model.ReadingAbandoned,
+	"abandoned":         model.ReadingAbandoned,
+}
+
+// exportDateLayouts are the date formats the exports write.
+var exportDateLayouts = []string{"2006/01/02", "2006-01-02", "01/02/2006"}
+
+// BookImport is one book from a reading-history export, as it would be stored.
+type BookImport struct {
+	Slug string
+	Book model.ReadingList
+	// Review becomes the document body.
+	Review string
+	// Skip says why the book will not be imported; empty when it will be.
+	Skip string
+}
+
+// exportRow reads the columns of one CSV row by header name.
+type exportRow struct {
+	columns map[string]int
+	record  []string
+}
+
+func (r exportRow) get(name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+
+	return strings.TrimSpace(r.record[i])
+}
+
+// ParseBookExport reads a Goodreads or StoryGraph CSV export, telling which from
+// its header, and returns its books along with the format's name.
+func ParseBookExport(reader io.Reader) ([]BookImport, string, error) {
+	records := csv.NewReader(reader)
+	records.FieldsPerRecord = -1
+
+	header, err := records.Read()
+	if err != nil {
+		return nil, "", fmt.Errorf("the file is not a CSV export: %w", err)
+	}
+
+	// Excel-saved exports start with a byte order mark.
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+
+	var (
+		format string
+		parse  func(exportRow) BookImport
+	)
+
+	switch {
+	case hasColumns(columns, "Exclusive Shelf", "My Rating"):
+		format, parse = ExportGoodreads, goodreadsBook
+	case hasColumns(columns, "Read Status", "Star Rating"):
+		format, parse = ExportStoryGraph, storyGraphBook
+	default:
+		return nil, "", errors.New("the file is neither a Goodreads nor a StoryGraph export")
+	}
+
+	var books []BookImport
+
+	for {
+		record, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, "", fmt.Errorf("the export could not be read: %w", err)
+		}
+
+		books = append(books, parse(exportRow{columns: columns, record: record}))
+	}
+
+	return books, format, nil
+}
+
+// hasColumns reports whether the header has every named column.
+func hasColumns(columns map[string]int, names ...string) bool {
+	for _, name := range names {
+		_, ok := columns[name]
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// goodreadsBook maps a row of a Goodreads "Export Library" CSV.
+func goodreadsBook(row exportRow) BookImport {
+	var book model.ReadingList
+
+	book.Title, book.Subtitle = splitBookTitle(row.get("Title"))
+	book.Author = row.get("Author")
+	book.ISBN = firstNonEmpty(exportISBN(row.get("ISBN13")), exportISBN(row.get("ISBN")))
+	book.Published = model.Date(firstNonEmpty(row.get("Original Publication Year"), row.get("Year Published"))).Normalize()
+	book.Pages, _ = strconv.Atoi(row.get("Number of Pages"))
+	book.Rating, _ = strconv.Atoi(row.get("My Rating"))
+	book.Finished = exportDate(row.get("Date Read"))
+	book.ReadingStatus = shelfState(row.get("Exclusive Shelf"))
+	book.Rereads = rereads(row.get("Read Count"))
+
+	for shelf := range strings.SplitSeq(row.get("Bookshelves"), ",") {
+		book.Tags = appendTag(book.Tags, shelf)
+	}
+
+	// Goodreads reviews are HTML with <br/> for line breaks.
+	review := strings.NewReplacer("<br/>", "\n", "<br />", "\n", "<br>", "\n").Replace(row.get("My Review"))
+
+	return BookImport{Book: book, Review: review}
+}
+
+// storyGraphBook maps a row of a StoryGraph export.
+func storyGraphBook(row exportRow) BookImport {
+	var book model.ReadingList
+
+	book.Title, book.Subtitle = splitBookTitle(row.get("Title"))
+	book.Author = row.get("Authors")
+	book.ISBN = exportISBN(row.get("ISBN/UID"))
+	book.ReadingStatus = shelfState(row.get("Read Status"))
+	book.Rereads = rereads(row.get("Read Count"))
+	book.Finished = exportDate(row.get("Last Date Read"))
+
+	// Star ratings come in quarter stars; the reading list keeps whole ones.
+	stars, err := strconv.ParseFloat(row.get("Star Rating"), 64)
+	if err == nil {
+		book.Rating = int(math.Round(stars))
+	}
+
+	// "Dates Read" holds a start-end range per read, most recent last.
+	reads := strings.Split(row.get("Dates Read"), ",")
+	start, end, ranged := strings.Cut(strings.TrimSpace(reads[len(reads)-1]), "-")
+	book.Started = exportDate(start)
+
+	if ranged && book.Finished == "" {
+		book.Finished = exportDate(end)
+	}
+
+	for tag := range strings.SplitSeq(row.get("Tags"), ",") {
+		book.Tags = appendTag(book.Tags, tag)
+	}
+
+	return BookImport{Book: book, Review: row.get("Review")}
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// splitBookTitle splits "Title: Subtitle" the way the exports write it.
+func splitBookTitle(full string) (string, string) {
+	title, subtitle, ok := strings.Cut(full, ": ")
+	if !ok {
+		return full, ""
+	}
+
+	return strings.TrimSpace(title), strings.TrimSpace(subtitle)
+}
+
+// shelfState maps a reading-state shelf to a reading state, want-to-read for
+// any the list does not know.
+func shelfState(shelf string) string {
+	state, ok := shelfStates[strings.ToLower(strings.TrimSpace(shelf))]
+	if !ok {
+		return model.ReadingWantToRead
+	}
+
+	return state
+}
+
+// appendTag adds a shelf or tag to tags, leaving out blanks, reading-state
+// shelves and repeats.
+func appendTag(tags []string, tag string) []string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return tags
+	}
+
+	_, isState := shelfStates[strings.ToLower(tag)]
+	if isState || slices.Contains(tags, tag) {
+		return tags
+	}
+
+	return append(tags, tag)
+}
+
+// rereads turns a read count into how many times the book was read again.
+func rereads(count string) int {
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 2 {
+		return 0
+	}
+
+	return n - 1
+}
+
+// exportDate converts an export date to YYYY-MM-DD, or "" if it is not one.
+// Goodreads may write month-first dates, which model.Date leaves out as
+// ambiguous, so the export's own layouts are tried here.
+func exportDate(value string) model.Date {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range exportDateLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return model.Date(t.Format(model.DateLayout))
+		}
+	}
+
+	return ""
+}
+
+// exportISBN cleans an exported ISBN. Goodreads writes them as ="0439023483"
+// so spreadsheets keep the leading zero.
+func exportISBN(value string) string {
+	return strings.ToUpper(strings.Trim(value, `=" `))
+}
+
+// normalizeISBN reduces an ISBN to the digits of its ISBN-13 form, so the same
+// book matches whether it was stored as ISBN-10 or ISBN-13, with or without
+// hyphens. Anything that is not an ISBN comes back as "".
+func normalizeISBN(isbn string) string {
+	var digits strings.Builder
+
+	for _, r := range strings.ToUpper(isbn) {
+		if (r >= '0' && r <= '9') || r == 'X' {
+			digits.WriteRune(r)
+		}
+	}
+
+	isbn = digits.String()
+
+	switch len(isbn) {
+	case 13:
+		return isbn
+	case 10:
+		// ISBN-10s are ISBN-13s with the 978 prefix and a new check digit.
+		core := "978" + isbn[:9]
+		sum := 0
+
+		for i, r := range core {
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+
+			sum += int(r-'0') * weight
+		}
+
+		return core + strconv.Itoa((10-sum%10)%10)
+	default:
+		return ""
+	}
+}
+
+// PlanBookImport decides which books an import would write: each gets a slug,
+// and books that are already on the reading list, by ISBN or by slug, that
+// appear earlier in the export, or that the writer would reject are marked to
+// be skipped. Nothing is written.
+func PlanBookImport(ctx context.Context, s storage.Backend, books []BookImport) ([]BookImport, error) {
+	existing, err := ListBooks(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]string, len(existing))
+
+	for _, book := range existing {
+		isbn := normalizeISBN(book.ISBN)
+		if isbn != "" {
+			stored[isbn] = book.Title
+		}
+	}
+
+	seenISBN := make(map[string]bool)
+	seenSlug := make(map[string]bool)
+
+	planned := make([]BookImport, len(books))
+
+	for i, book := range books {
+		book.Slug = storage.SanitizeFilename(book.Book.Title)
+		isbn := normalizeISBN(book.Book.ISBN)
+
+		switch {
+		case book.Book.Title == "" || book.Slug == "":
+			book.Skip = "no title"
+		case book.Book.Author == "":
+			book.Skip = "no author"
+		case stored[isbn] != "":
+			book.Skip = fmt.Sprintf("ISBN already on the reading list as %q", stored[isbn])
+		case isbn != "" && seenISBN[isbn]:
+			book.Skip = "ISBN repeated earlier in the export"
+		case seenSlug[book.Slug]:
+			book.Skip = "title repeated earlier in the export"
+		}
+
+		if book.Skip == "" {
+			err := validateBookImport(book.Book)
+			if err != nil {
+				book.Skip = err.Error()
+			}
+		}
+
+		if book.Skip == "" {
+			_, err := getDoc[model.ReadingList](ctx, s, ReadingListPrefix+book.Slug+".yaml")
+			if err == nil {
+				book.Skip = "a book is already stored at " + book.Slug
+			}
+		}
+
+		if book.Skip == "" {
+			seenISBN[isbn] = isbn != ""
+			seenSlug[book.Slug] = true
+		}
+
+		planned[i] = book
+	}
+
+	return planned, nil
+}
+
+// validateBookImport runs the checks the writer and uploads run on a book, on
+// the book as it would be stored.
+func validateBookImport(book model.ReadingList) error {
+	book.ReadingStatus = book.State()
+
+	err := model.ValidateRequired(&book)
+	if err != nil {
+		return err
+	}
+
+	err = model.ValidateDates(&book)
+	if err != nil {
+		return err
+	}
+
+	return book.ValidateFields()
+}
+
+// ImportBooks writes the books of a plan that are not skipped as reading-list
+// documents and returns how many it wrote. Each is written as a draft, so an
+// export's whole history does not go public until it has been looked over. It
+// stops at the first failure, so the count says how far it got.
+func ImportBooks(ctx context.Context, s storage.Backend, plan []BookImport) (int, error) {
+	imported := 0
+
+	for _, book := range plan {
+		if book.Skip != "" {
+			continue
+		}
+
+		yamlBytes, mdBytes, err := storage.MarshalMarkdownDocument(bookImportFields(book))
+		if err != nil {
+			return imported, fmt.Errorf("failed to prepare %s: %w", book.Slug, err)
+		}
+
+		err = storage.WriteDocument(ctx, s, ReadingListPrefix+book.Slug+".yaml", yamlBytes, mdBytes)
+		if err != nil {
+			return imported, fmt.Errorf("failed to write %s: %w", book.Slug, err)
+		}
+
+		imported++
+	}
+
+	return imported, nil
+}
+
+// bookImportFields lays an imported book out as the writer would, as a draft,
+// storing only the fields the export filled in.
+func bookImportFields(book BookImport) map[string]any {
+	b := book.Book
+
+	fields := map[string]any{
+		"title":         b.Title,
+		"subtitle":      b.Subtitle,
+		"author":        b.Author,
+		"readingStatus": b.State(),
+		"status":        model.StatusDraft,
+		"tags":          b.Tags,
+		"body":          book.Review,
+	}
+
+	for key, value := range map[string]string{
+		"isbn": b.ISBN, "published": string(b.Published), "started": string(b.Started), "finished": string(b.Finished),
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+
+	for key, value := range map[string]int{"pages": b.Pages, "rating": b.Rating, "rereads": b.Rereads} {
+		if value != 0 {
+			fields[key] = value
+		}
+	}
+
+	return fields
+}
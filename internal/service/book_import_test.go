@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+const goodreadsExport = `Book Id,Title,Author,ISBN,ISBN13,My Rating,Number of Pages,Year Published,Original Publication Year,Date Read,Bookshelves,Exclusive Shelf,My Review,Read Count
+1,"Dune: Deluxe Edition",Frank Herbert,"=""0441013597""","=""9780441013593""",5,688,2005,1965,2024/08/14,"sci-fi, favourites, read",read,Loved it.<br/>Again soon.,2
+2,Go in Action,William Kennedy,"=""""","=""""",0,264,2015,2015,,"to-read",to-read,,0
+3,Duplicate Dune,Frank Herbert,"=""0441013597""","=""""",4,600,2005,1965,2023/01/01,,read,,1
+`
+
+const storyGraphExport = `Title,Authors,Contributors,ISBN/UID,Format,Read Status,Date Added,Last Date Read,Dates Read,Read Count,Moods,Pace,Character- or Plot-Driven?,Strong Character Development?,Loveable Characters?,Diverse Characters?,Flawed Characters?,Star Rating,Review,Content Warnings,Content Warning Description,Tags,Owned?
+Piranesi,Susanna Clarke,,9781635575996,hardcover,read,2024/01/01,2024/02/10,2024/01/20-2024/02/10,1,mysterious,medium,,,,,,4.75,Strange and lovely.,,,"fantasy, favourites",Yes
+`
+
+func TestParseBookExport(t *testing.T) {
+	t.Run("maps a Goodreads export", func(t *testing.T) {
+		books, format, err := service.ParseBookExport(strings.NewReader(goodreadsExport))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if format != service.ExportGoodreads || len(books) != 3 {
+			t.Fatalf("expected 3 Goodreads books, got %d from %q", len(books), format)
+		}
+
+		dune := books[0].Book
+		if dune.Title != "Dune" || dune.Subtitle != "Deluxe Edition" || dune.Author != "Frank Herbert" {
+			t.Errorf("unexpected title or author: %+v", dune)
+		}
+
+		if dune.ISBN != "9780441013593" || dune.Published != "1965" || dune.Pages != 688 {
+			t.Errorf("unexpected bibliographic fields: %+v", dune)
+		}
+
+		if dune.State() != model.ReadingFinished || dune.Finished != "2024-08-14" || dune.Rating != 5 || dune.Rereads != 1 {
+			t.Errorf("unexpected reading fields: %+v", dune)
+		}
+
+		if strings.Join(dune.Tags, ",") != "sci-fi,favourites" {
+			t.Errorf("expected shelves as tags without read, got %v", dune.Tags)
+		}
+
+		if books[0].Review != "Loved it.\nAgain soon." {
+			t.Errorf("expected the review with line breaks, got %q", books[0].Review)
+		}
+
+		if books[1].Book.State() != model.ReadingWantToRead {
+			t.Errorf("expected to-read as want-to-read, got %q", books[1].Book.State())
+		}
+	})
+
+	t.Run("maps a StoryGraph export", func(t *testing.T) {
+		books, format, err := service.ParseBookExport(strings.NewReader(storyGraphExport))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if format != service.ExportStoryGraph || len(books) != 1 {
+			t.Fatalf("expected 1 StoryGraph book, got %d from %q", len(books), format)
+		}
+
+		book := books[0].Book
+		if book.Started != "2024-01-20" || book.Finished != "2024-02-10" || book.Rating != 5 {
+			t.Errorf("unexpected reading fields: %+v", book)
+		}
+
+		if strings.Join(book.Tags, ",") != "fantasy,favourites" {
+			t.Errorf("unexpected tags: %v", book.Tags)
+		}
+	})
+
+	t.Run("rejects other CSV files", func(t *testing.T) {
+		_, _, err := service.ParseBookExport(strings.NewReader("a,b\n1,2\n"))
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestBookImport(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+
+	books, _, err := service.ParseBookExport(strings.NewReader(goodreadsExport))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// test-book is stored with ISBN 978-0-134685991.
+	books = append(books, service.BookImport{Book: model.ReadingList{
+		Document: model.Document{Title: "Same Book"},
+		Author:   "Someone",
+		ISBN:     "9780134685991",
+	}})
+
+	// A StoryGraph row can finish a read before it started it.
+	books = append(books, service.BookImport{Book: model.ReadingList{
+		Document: model.Document{Title: "Backwards Book"},
+		Author:   "Someone",
+		Started:  "2024-03-01",
+		Finished: "2024-02-01",
+	}})
+
+	plan, err := service.PlanBookImport(ctx, s, books)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if plan[0].Skip != "" || plan[1].Skip != "" {
+		t.Errorf("expected the first two books to be imported, got %q and %q", plan[0].Skip, plan[1].Skip)
+	}
+
+	// Duplicate Dune has the ISBN-10 of the first row's ISBN-13.
+	if !strings.Contains(plan[2].Skip, "repeated") {
+		t.Errorf("expected a repeated ISBN to be skipped, got %q", plan[2].Skip)
+	}
+
+	if !strings.Contains(plan[3].Skip, "already on the reading list") {
+		t.Errorf("expected a stored ISBN to be skipped, got %q", plan[3].Skip)
+	}
+
+	if !strings.Contains(plan[4].Skip, "finished must not be before started") {
+		t.Errorf("expected a book the writer would reject to be skipped, got %q", plan[4].Skip)
+	}
+
+	imported, err := service.ImportBooks(ctx, s, plan)
+	if err != nil || imported != 2 {
+		t.Fatalf("expected 2 imported, got %d: %v", imported, err)
+	}
+
+	dune, err := service.GetBookBySlug(ctx, s, "dune")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dune.Rating != 5 || dune.Finished != "2024-08-14" || dune.ISBN != "9780441013593" {
+		t.Errorf("unexpected stored book: %+v", dune)
+	}
+
+	if !dune.IsDraft() {
+		t.Errorf("expected the book imported as a draft, got status %q", dune.Status)
+	}
+
+	body, err := storage.GetDocumentBodyRaw(ctx, s, dune.S3Key)
+	if err != nil || !strings.Contains(body, "Loved it.") {
+		t.Errorf("expected the review as the body, got %q: %v", body, err)
+	}
+
+	// Importing again finds everything already there.
+	plan, err = service.PlanBookImport(ctx, s, books[:2])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, book := range plan {
+		if book.Skip == "" {
+			t.Errorf("expected %s to be skipped on a second import", book.Slug)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"timterests/internal/model"
+	"timterests/internal/storage"
+	"unicode"
+)
+
+// clippingSeparator ends every entry in a Kindle clippings file.
+const clippingSeparator = "=========="
+
+// clippingAddedLayout is how an English Kindle writes "Added on".
+const clippingAddedLayout = "Monday, January 2, 2006 3:04:05 PM"
+
+// clippingMeta reads the kind and location out of an entry's second line, e.g.
+// "- Your Highlight on page 12 | Location 150-152 | Added on ...".
+var clippingMeta = regexp.MustCompile(`(?i)^-\s*Your (Highlight|Note|Bookmark)\b(.*?)(?:\|\s*Added on (.*))?$`)
+
+// Clipping is one highlight or note from a Kindle clippings file.
+type Clipping struct {
+	Title  string
+	Author string
+	// Note is true for the reader's own note rather than a highlighted passage.
+	Note      bool
+	Highlight model.Highlight
+}
+
+// ParseClippings reads a Kindle "My Clippings.txt". Bookmarks carry no text and
+// are left out; notes come back with Note set, to be attached to the highlight
+// they were written on.
+func ParseClippings(reader io.Reader) ([]Clipping, error) {
+	var (
+		clippings []Clipping
+		entry     []string
+	)
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxClippingLine)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\ufeff"), "\r")
+
+		if strings.TrimSpace(line) != clippingSeparator {
+			entry = append(entry, line)
+
+			continue
+		}
+
+		clipping, ok := parseClipping(entry)
+		if ok {
+			clippings = append(clippings, clipping)
+		}
+
+		entry = nil
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("the clippings could not be read: %w", err)
+	}
+
+	return clippings, nil
+}
+
+// maxClippingLine allows for a long passage highlighted as one line.
+const maxClippingLine = 1 << 20
+
+// parseClipping reads one entry: the book, the kind and location, a blank line,
+// then the text.
+func parseClipping(entry []string) (Clipping, bool) {
+	if len(entry) < 2 {
+		return Clipping{}, false
+	}
+
+	meta := clippingMeta.FindStringSubmatch(strings.TrimSpace(entry[1]))
+	if meta == nil || strings.EqualFold(meta[1], "Bookmark") {
+		return Clipping{}, false
+	}
+
+	text := strings.TrimSpace(strings.Join(entry[2:], "\n"))
+	if text == "" {
+		return Clipping{}, false
+	}
+
+	title, author := splitClippingBook(strings.TrimSpace(entry[0]))
+
+	clipping := Clipping{
+		Title:  title,
+		Author: author,
+		Note:   strings.EqualFold(meta[1], "Note"),
+		Highlight: model.Highlight{
+			Text:     text,
+			Location: clippingLocation(meta[2]),
+			Added:    clippingAdded(meta[3]),
+		},
+	}
+
+	return clipping, true
+}
+
+// splitClippingBook splits "Title (Author)" into its parts. Titles can hold
+// parentheses of their own, so the author is the last group.
+func splitClippingBook(line string) (string, string) {
+	if !strings.HasSuffix(line, ")") {
+		return line, ""
+	}
+
+	open := strings.LastIndex(line, "(")
+	if open <= 0 {
+		return line, ""
+	}
+
+	return strings.TrimSpace(line[:open]), strings.TrimSpace(line[open+1 : len(line)-1])
+}
+
+// clippingLocation tidies "on page 12 | Location 150-152 " into
+// "page 12, location 150-152".
+func clippingLocation(raw string) string {
+	var parts []string
+
+	for part := range strings.SplitSeq(raw, "|") {
+		part = strings.TrimSpace(part)
+		part = strings.TrimPrefix(strings.TrimPrefix(part, "on "), "at ")
+
+		if part != "" {
+			parts = append(parts, strings.ToLower(part[:1])+part[1:])
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// clippingAdded converts the "Added on" date to YYYY-MM-DD, or "" for dates in
+// a format it does not know.
+func clippingAdded(raw string) model.Date {
+	added, err := time.Parse(clippingAddedLayout, strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+
+	return model.Date(added.Format(model.DateLayout))
+}
+
+// ClippingMatch is the highlights a clippings file adds to one book.
+type ClippingMatch struct {
+	Slug  string
+	Key   string
+	Title string
+	Added []model.Highlight
+	// Existing counts the highlights already on the book, which are not added
+	// again.
+	Existing int
+	// Noted are highlights already on the book that gain a note, with the
+	// note set.
+	Noted []model.Highlight
+	// Dropped lists the notes written at no highlight in the file or on the
+	// book, which are left out.
+	Dropped []string
+}
+
+// ClippingsPlan is what a clippings import would do.
+type ClippingsPlan struct {
+	Books []ClippingMatch
+	// Unmatched lists the books in the file that are not on the reading list,
+	// as "Title (Author)".
+	Unmatched []string
+}
+
+// PlanClippings matches clippings to reading-list books by title and author
+// and works out which highlights are new. A clippings file holds everything
+// ever highlighted, so importing it again only adds what is new. Notes are
+// attached to the highlight they were written at. Nothing is written.
+func PlanClippings(ctx context.Context, s storage.Backend, clippings []Clipping) (ClippingsPlan, error) {
+	var plan ClippingsPlan
+
+	books, err := ListBooks(ctx, s, "all")
+	if err != nil {
+		return plan, err
+	}
+
+	matches := make(map[string]*ClippingMatch)
+
+	for _, clipping := range clippings {
+		book := matchClippingBook(books, clipping)
+		if book == nil {
+			label := clipping.Title
+			if clipping.Author != "" {
+				label += " (" + clipping.Author + ")"
+			}
+
+			if !slices.Contains(plan.Unmatched, label) {
+				plan.Unmatched = append(plan.Unmatched, label)
+			}
+
+			continue
+		}
+
+		match, ok := matches[book.S3Key]
+		if !ok {
+			match = &ClippingMatch{Slug: book.ID, Key: book.S3Key, Title: book.Title}
+			matches[book.S3Key] = match
+		}
+
+		if clipping.Note {
+			attachNote(match, book.Highlights, clipping.Highlight)
+
+			continue
+		}
+
+		if slices.ContainsFunc(book.Highlights, sameHighlight(clipping.Highlight)) {
+			match.Existing++
+
+			continue
+		}
+
+		if !slices.ContainsFunc(match.Added, sameHighlight(clipping.Highlight)) {
+			match.Added = append(match.Added, clipping.Highlight)
+		}
+	}
+
+	for _, match := range matches {
+		plan.Books = append(plan.Books, *match)
+	}
+
+	slices.SortFunc(plan.Books, func(a, b ClippingMatch) int { return strings.Compare(a.Title, b.Title) })
+
+	return plan, nil
+}
+
+// ImportClippings adds the new highlights of a plan to their books, and the new
+// notes to highlights they already have, and returns how many highlights it
+// added. Each book's other fields are left as they are.
+func ImportClippings(ctx context.Context, s storage.Backend, plan ClippingsPlan) (int, error) {
+	imported := 0
+
+	for _, match := range plan.Books {
+		if len(match.Added) == 0 && len(match.Noted) == 0 {
+			continue
+		}
+
+		book, err := getDoc[model.ReadingList](ctx, s, match.Key)
+		if err != nil {
+			return imported, err
+		}
+
+		highlights := slices.Clone(book.Highlights)
+
+		for _, noted := range match.Noted {
+			i := slices.IndexFunc(highlights, sameHighlight(noted))
+			if i >= 0 {
+				highlights[i].Note = noted.Note
+			}
+		}
+
+		highlights = append(highlights, match.Added...)
+
+		err = storage.SetDocumentFields(ctx, s, match.Key, map[string]any{"highlights": highlights})
+		if err != nil {
+			return imported, fmt.Errorf("failed to add highlights to %s: %w", match.Slug, err)
+		}
+
+		imported += len(match.Added)
+	}
+
+	return imported, nil
+}
+
+// attachNote puts a note on the highlight it was written at: Kindle records a
+// note at the last location of the passage it belongs to. The highlight may be
+// new in this file or already on the book from an earlier import; a note that
+// matches neither is dropped.
+func attachNote(match *ClippingMatch, existing []model.Highlight, note model.Highlight) {
+	end := locationEnd(note.Location)
+	at := func(h model.Highlight) bool {
+		return end != "" && locationEnd(h.Location) == end
+	}
+
+	for i := len(match.Added) - 1; i >= 0; i-- {
+		if at(match.Added[i]) {
+			match.Added[i].Note = note.Text
+
+			return
+		}
+	}
+
+	for i := len(existing) - 1; i >= 0; i-- {
+		if !at(existing[i]) {
+			continue
+		}
+
+		if existing[i].Note == note.Text {
+			return
+		}
+
+		noted := existing[i]
+		noted.Note = note.Text
+
+		j := slices.IndexFunc(match.Noted, sameHighlight(noted))
+		if j >= 0 {
+			match.Noted[j] = noted
+		} else {
+			match.Noted = append(match.Noted, noted)
+		}
+
+		return
+	}
+
+	match.Dropped = append(match.Dropped, note.Text)
+}
+
+// locationEnd returns the last number of a location, "152" for "location
+// 150-152".
+func locationEnd(location string) string {
+	end := strings.LastIndexFunc(location, func(r rune) bool { return !unicode.IsDigit(r) })
+
+	return location[end+1:]
+}
+
+// sameHighlight matches a highlight with the same text, ignoring spacing.
+func sameHighlight(h model.Highlight) func(model.Highlight) bool {
+	text := strings.Join(strings.Fields(h.Text), " ")
+
+	return func(other model.Highlight) bool {
+		return strings.Join(strings.Fields(other.Text), " ") == text
+	}
+}
+
+// matchClippingBook finds the reading-list book a clipping came from. Kindle
+// titles often carry the subtitle or series, so the book's title need only
+// begin the clipping's; the author must match too when both are known. The
+// longest title that matches wins, so "Dune Messiah" is not taken for "Dune",
+// and an exact match beats any other.
+func matchClippingBook(books []model.ReadingList, clipping Clipping) *model.ReadingList {
+	title := matchWords(clipping.Title)
+
+	var (
+		match   *model.ReadingList
+		matched int
+	)
+
+	for i := range books {
+		bookTitle := matchWords(books[i].Title)
+		if len(bookTitle) <= matched || len(title) < len(bookTitle) || !slices.Equal(title[:len(bookTitle)], bookTitle) {
+			continue
+		}
+
+		if clipping.Author == "" || books[i].Author == "" || sameAuthor(books[i].Author, clipping.Author) {
+			match, matched = &books[i], len(bookTitle)
+		}
+	}
+
+	return match
+}
+
+// sameAuthor reports whether two spellings name the same author, in either
+// "First Last" or "Last, First" order: every word of the shorter appears in the
+// longer.
+func sameAuthor(a, b string) bool {
+	wordsA, wordsB := matchWords(a), matchWords(b)
+	if len(wordsA) > len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+
+	for _, word := range wordsA {
+		if !slices.Contains(wordsB, word) {
+			return false
+		}
+	}
+
+	return len(wordsA) > 0
+}
+
+// matchWords lowercases text and splits it into words, dropping punctuation.
+func matchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Quote is a highlight shown with the book it came from.
+type Quote struct {
+	model.Highlight
+
+	Book model.ReadingList
+}
+
+// ListQuotes gathers the highlights of the published books, optionally only
+// from books with tag, most recently added first. Pass tag="" or tag="all" for
+// every book.
+func ListQuotes(ctx context.Context, s storage.Backend, tag string, now time.Time) ([]Quote, error) {
+	books, err := ListBooks(ctx, s, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	var quotes []Quote
+
+	for _, book := range Published(books, now) {
+		for _, h := range book.Highlights {
+			quotes = append(quotes, Quote{Highlight: h, Book: book})
+		}
+	}
+
+	slices.SortStableFunc(quotes, func(a, b Quote) int { return b.Added.Compare(a.Added) })
+
+	return quotes, nil
+}
@@ -0,0 +1,252 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+const kindleClippings = "\ufeffDune: Deluxe Edition (Herbert, Frank)\r\n" +
+	"- Your Highlight on page 8 | Location 110-112 | Added on Monday, March 4, 2024 10:15:32 PM\r\n" +
+	"\r\n" +
+	"I must not fear.\r\n" +
+	"==========\r\n" +
+	"Dune: Deluxe Edition (Herbert, Frank)\r\n" +
+	"- Your Note on page 8 | Location 112 | Added on Monday, March 4, 2024 10:16:00 PM\r\n" +
+	"\r\n" +
+	"The litany.\r\n" +
+	"==========\r\n" +
+	"Dune: Deluxe Edition (Herbert, Frank)\r\n" +
+	"- Your Bookmark on page 9 | Location 120 | Added on Monday, March 4, 2024 10:17:00 PM\r\n" +
+	"\r\n" +
+	"\r\n" +
+	"==========\r\n" +
+	"Some Other Book (Someone Else)\r\n" +
+	"- Your Highlight at location 5-6 | Added on Tuesday, March 5, 2024 9:00:00 AM\r\n" +
+	"\r\n" +
+	"Elsewhere.\r\n" +
+	"==========\r\n"
+
+func TestParseClippings(t *testing.T) {
+	clippings, err := service.ParseClippings(strings.NewReader(kindleClippings))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(clippings) != 3 {
+		t.Fatalf("expected 2 highlights and a note without the bookmark, got %d", len(clippings))
+	}
+
+	first := clippings[0]
+	if first.Title != "Dune: Deluxe Edition" || first.Author != "Herbert, Frank" {
+		t.Errorf("unexpected book %q by %q", first.Title, first.Author)
+	}
+
+	if first.Highlight.Text != "I must not fear." || first.Highlight.Location != "page 8, location 110-112" || first.Highlight.Added != "2024-03-04" {
+		t.Errorf("unexpected highlight %+v", first.Highlight)
+	}
+
+	if !clippings[1].Note {
+		t.Error("expected the second entry to be a note")
+	}
+}
+
+func TestClippingsImport(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+
+	err := storage.WriteDocument(ctx, s, "reading-list/dune.yaml",
+		[]byte("title: Dune\nauthor: Frank Herbert\ntags: [SciFi]\n"), []byte("Body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	clippings, err := service.ParseClippings(strings.NewReader(kindleClippings))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan, err := service.PlanClippings(ctx, s, clippings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(plan.Books) != 1 || plan.Books[0].Slug != "dune" || len(plan.Books[0].Added) != 1 {
+		t.Fatalf("expected one highlight for dune, got %+v", plan.Books)
+	}
+
+	if plan.Books[0].Added[0].Note != "The litany." {
+		t.Errorf("expected the note on its highlight, got %q", plan.Books[0].Added[0].Note)
+	}
+
+	if len(plan.Unmatched) != 1 || plan.Unmatched[0] != "Some Other Book (Someone Else)" {
+		t.Errorf("unexpected unmatched books %v", plan.Unmatched)
+	}
+
+	added, err := service.ImportClippings(ctx, s, plan)
+	if err != nil || added != 1 {
+		t.Fatalf("expected 1 added, got %d: %v", added, err)
+	}
+
+	book, err := service.GetBookBySlug(ctx, s, "dune")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(book.Highlights) != 1 || book.Author != "Frank Herbert" {
+		t.Errorf("expected the highlight added and the book kept, got %+v", book)
+	}
+
+	t.Run("importing again adds nothing", func(t *testing.T) {
+		plan, err := service.PlanClippings(ctx, s, clippings)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(plan.Books[0].Added) != 0 || plan.Books[0].Existing != 1 {
+			t.Errorf("expected the highlight to be found already there, got %+v", plan.Books[0])
+		}
+	})
+
+	t.Run("lists quotes by book tag", func(t *testing.T) {
+		now := time.Now()
+
+		quotes, err := service.ListQuotes(ctx, s, "SciFi", now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(quotes) != 1 || quotes[0].Book.Title != "Dune" {
+			t.Errorf("expected the Dune quote, got %+v", quotes)
+		}
+
+		quotes, err = service.ListQuotes(ctx, s, "Testing", now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(quotes) != 0 {
+			t.Errorf("expected no quotes for another tag, got %d", len(quotes))
+		}
+	})
+}
+
+// A note on a passage highlighted in an earlier import goes onto the highlight
+// the book already has, and a note at no highlight is reported, not lost.
+func TestClippingsNoteOnExistingHighlight(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+
+	err := storage.WriteDocument(ctx, s, "reading-list/dune.yaml", []byte("title: Dune\nauthor: Frank Herbert\n"+
+		"highlights:\n  - text: I must not fear.\n    location: page 8, location 110-112\n"), []byte("Body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	notes := "Dune (Frank Herbert)\n" +
+		"- Your Note on page 8 | Location 112 | Added on Monday, March 4, 2024 10:16:00 PM\n" +
+		"\n" +
+		"The litany.\n" +
+		"==========\n" +
+		"Dune (Frank Herbert)\n" +
+		"- Your Note on page 40 | Location 900 | Added on Monday, March 4, 2024 10:20:00 PM\n" +
+		"\n" +
+		"A stray thought.\n" +
+		"==========\n"
+
+	clippings, err := service.ParseClippings(strings.NewReader(notes))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan, err := service.PlanClippings(ctx, s, clippings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(plan.Books) != 1 || len(plan.Books[0].Noted) != 1 || plan.Books[0].Noted[0].Note != "The litany." {
+		t.Fatalf("expected the note for the existing highlight, got %+v", plan.Books)
+	}
+
+	if dropped := plan.Books[0].Dropped; len(dropped) != 1 || dropped[0] != "A stray thought." {
+		t.Errorf("expected the stray note to be reported, got %v", dropped)
+	}
+
+	_, err = service.ImportClippings(ctx, s, plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	book, err := service.GetBookBySlug(ctx, s, "dune")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(book.Highlights) != 1 || book.Highlights[0].Note != "The litany." {
+		t.Errorf("expected the note on the existing highlight, got %+v", book.Highlights)
+	}
+
+	t.Run("importing again adds nothing", func(t *testing.T) {
+		plan, err := service.PlanClippings(ctx, s, clippings)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(plan.Books[0].Noted) != 0 {
+			t.Errorf("expected the note to be found already there, got %+v", plan.Books[0])
+		}
+	})
+}
+
+// Highlights from a sequel go to the sequel, not to the book whose title it
+// begins with, whichever is stored first.
+func TestClippingsMatchLongestTitle(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+
+	// Dune is written last, so it is listed first by name and by time.
+	for _, slug := range []string{"dune-messiah", "dune"} {
+		title := map[string]string{"dune": "Dune", "dune-messiah": "Dune Messiah"}[slug]
+
+		err := storage.WriteDocument(ctx, s, "reading-list/"+slug+".yaml",
+			[]byte("title: "+title+"\nauthor: Frank Herbert\n"), []byte("Body\n"))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+	}
+
+	clippings, err := service.ParseClippings(strings.NewReader(
+		"Dune Messiah (Herbert, Frank)\r\n" +
+			"- Your Highlight on page 3 | Location 40-41 | Added on Monday, March 4, 2024 10:15:32 PM\r\n" +
+			"\r\n" +
+			"The sequel.\r\n" +
+			"==========\r\n" +
+			"Dune (Herbert, Frank)\r\n" +
+			"- Your Highlight on page 8 | Location 110-112 | Added on Monday, March 4, 2024 10:20:00 PM\r\n" +
+			"\r\n" +
+			"The first book.\r\n" +
+			"==========\r\n",
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan, err := service.PlanClippings(ctx, s, clippings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	added := make(map[string]string, len(plan.Books))
+	for _, book := range plan.Books {
+		for _, h := range book.Added {
+			added[h.Text] = book.Slug
+		}
+	}
+
+	if added["The sequel."] != "dune-messiah" || added["The first book."] != "dune" {
+		t.Errorf("expected each highlight on its own book, got %v", added)
+	}
+}
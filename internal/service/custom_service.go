@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"timterests/internal/model"
+	"timterests/internal/storage"
+)
+
+// ListCustom retrieves every document of a schema-declared type, optionally
+// filtering by tag, in the order the schema's list layout asks for. Pass
+// tag="" or tag="all" to retrieve them all.
+func ListCustom(ctx context.Context, s storage.Backend, schema *model.DocumentSchema, tag string) ([]model.CustomDocument, error) {
+	var docs []model.CustomDocument
+
+	keys, err := documentKeys(ctx, s, schema.Name+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		doc, err := GetCustom(ctx, s, schema, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if tag == "" || tag == "all" || slices.Contains(doc.Tags, tag) {
+			docs = append(docs, *doc)
+		}
+	}
+
+	sortCustom(docs, schema)
+
+	return docs, nil
+}
+
+// GetCustom retrieves a single document of a schema-declared type by its
+// storage key, resolving the image its card is drawn with.
+func GetCustom(ctx context.Context, s storage.Backend, schema *model.DocumentSchema, key string) (*model.CustomDocument, error) {
+	doc, err := getDoc[model.CustomDocument](ctx, s, key)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Schema = schema
+
+	image := doc.Value(schema.Card.Image)
+	if schema.Card.Image != "" && image != "" {
+		imagePath, err := s.GetImage(ctx, image)
+		if err != nil {
+			log.Printf("Failed to download image: %v", err)
+
+			return nil, fmt.Errorf("failed to resolve image %q: %w", image, err)
+		}
+
+		// The decoded document may be shared through the Index, so the
+		// resolved path goes into a copy of its fields.
+		doc.Fields = maps.Clone(doc.Fields)
+		doc.Fields[schema.Card.Image] = imagePath
+	}
+
+	return doc, nil
+}
+
+// GetCustomBySlug retrieves the document of a schema-declared type addressed by
+// slug, as used in its permalink.
+func GetCustomBySlug(ctx context.Context, s storage.Backend, schema *model.DocumentSchema, slug string) (*model.CustomDocument, error) {
+	key, err := documentKey(schema.Name+"/", slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetCustom(ctx, s, schema, key)
+}
+
+// sortCustom orders docs by the schema's sort field, the title by default.
+// Number and date fields compare as such, everything else as text.
+func sortCustom(docs []model.CustomDocument, schema *model.DocumentSchema) {
+	field := schema.List.SortBy
+	if field == "" {
+		field = "title"
+	}
+
+	declared, _ := schema.Field(field)
+
+	less := func(a, b *model.CustomDocument) bool {
+		if declared.Type == model.FieldDate {
+			return model.Date(a.Value(field)).Before(model.Date(b.Value(field)))
+		}
+
+		if declared.Type == model.FieldNumber {
+			x, errX := strconv.ParseFloat(a.Value(field), 64)
+			y, errY := strconv.ParseFloat(b.Value(field), 64)
+
+			if errX == nil && errY == nil {
+				return x < y
+			}
+		}
+
+		return a.Value(field) < b.Value(field)
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		if schema.List.Descending {
+			return less(&docs[j], &docs[i])
+		}
+
+		return less(&docs[i], &docs[j])
+	})
+}
@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+func TestCustomDocuments(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+
+	schemas, err := model.ParseSchemas([]byte(`
+types:
+  - name: recipes
+    fields:
+      - {name: servings, type: number}
+    list:
+      sortBy: servings
+      descending: true
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	schema := &schemas[0]
+
+	for slug, meta := range map[string]string{
+		"pancakes": "title: Pancakes\nservings: 4\ntags: [breakfast]\n",
+		"stew":     "title: Stew\nservings: 10\ntags: [dinner]\n",
+		"toast":    "title: Toast\nservings: 2\ntags: [breakfast]\n",
+	} {
+		err = storage.WriteDocument(ctx, s, "recipes/"+slug+".yaml", []byte(meta), []byte("Body\n"))
+		if err != nil {
+			t.Fatalf("failed to seed %s: %v", slug, err)
+		}
+	}
+
+	t.Run("sorts numerically by the schema's field", func(t *testing.T) {
+		docs, err := service.ListCustom(ctx, s, schema, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var titles []string
+		for _, doc := range docs {
+			titles = append(titles, doc.Title)
+		}
+
+		want := []string{"Stew", "Pancakes", "Toast"}
+		if len(titles) != len(want) {
+			t.Fatalf("expected %v, got %v", want, titles)
+		}
+
+		for i := range want {
+			if titles[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, titles)
+			}
+		}
+	})
+
+	t.Run("filters by tag", func(t *testing.T) {
+		docs, err := service.ListCustom(ctx, s, schema, "breakfast")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(docs) != 2 {
+			t.Errorf("expected 2 breakfast recipes, got %d", len(docs))
+		}
+	})
+
+	t.Run("gets one by slug", func(t *testing.T) {
+		doc, err := service.GetCustomBySlug(ctx, s, schema, "stew")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if doc.Schema != schema || doc.Value("servings") != "10" {
+			t.Errorf("unexpected document %+v", doc)
+		}
+
+		_, err = service.GetCustomBySlug(ctx, s, schema, "missing")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected fs.ErrNotExist, got %v", err)
+		}
+	})
+}
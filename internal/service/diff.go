@@ -0,0 +1,117 @@
+package service
+
+import "strings"
+
+// DiffOp says how a line changed between two versions of a text.
+type DiffOp string
+
+// Diff operations, as used for the row's CSS class.
+const (
+	DiffEqual   DiffOp = "equal"
+	DiffRemoved DiffOp = "removed"
+	DiffAdded   DiffOp = "added"
+	DiffChanged DiffOp = "changed"
+)
+
+// DiffRow is one line of a side-by-side diff. A line number of zero means that
+// side has no line in this row.
+type DiffRow struct {
+	Op    DiffOp
+	OldNo int
+	NewNo int
+	Old   string
+	New   string
+}
+
+// DiffLines compares two texts line by line and returns rows ready to show side
+// by side. Runs of removed lines followed by added lines are paired up as
+// changes, so an edited line sits next to what it replaced.
+func DiffLines(oldText, newText string) []DiffRow {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// oldLines[i:] and newLines[j:].
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var (
+		rows    []DiffRow
+		removed []DiffRow
+		added   []DiffRow
+	)
+
+	flush := func() {
+		paired := min(len(removed), len(added))
+
+		for k := range paired {
+			rows = append(rows, DiffRow{
+				Op:    DiffChanged,
+				OldNo: removed[k].OldNo,
+				Old:   removed[k].Old,
+				NewNo: added[k].NewNo,
+				New:   added[k].New,
+			})
+		}
+
+		rows = append(rows, removed[paired:]...)
+		rows = append(rows, added[paired:]...)
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			flush()
+
+			rows = append(rows, DiffRow{Op: DiffEqual, OldNo: i + 1, Old: oldLines[i], NewNo: j + 1, New: newLines[j]})
+			i++
+			j++
+		case j < len(newLines) && (i == len(oldLines) || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, DiffRow{Op: DiffAdded, NewNo: j + 1, New: newLines[j]})
+			j++
+		default:
+			removed = append(removed, DiffRow{Op: DiffRemoved, OldNo: i + 1, Old: oldLines[i]})
+			i++
+		}
+	}
+
+	flush()
+
+	return rows
+}
+
+// DiffChanges reports whether any row of a diff is not equal.
+func DiffChanges(rows []DiffRow) bool {
+	for _, row := range rows {
+		if row.Op != DiffEqual {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitLines splits text into lines, ignoring a trailing newline so a file
+// that ends with one does not grow an empty last line.
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
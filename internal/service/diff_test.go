@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"testing"
+	"timterests/internal/service"
+)
+
+func TestDiffLines(t *testing.T) {
+	t.Run("identical texts have only equal rows", func(t *testing.T) {
+		rows := service.DiffLines("a\nb\n", "a\nb\n")
+
+		if len(rows) != 2 || service.DiffChanges(rows) {
+			t.Errorf("expected two equal rows, got %+v", rows)
+		}
+	})
+
+	t.Run("an edited line is paired with what it replaced", func(t *testing.T) {
+		rows := service.DiffLines("title: Old\ndate: 2026-01-01\n", "title: New\ndate: 2026-01-01\n")
+
+		if len(rows) != 2 {
+			t.Fatalf("expected 2 rows, got %+v", rows)
+		}
+
+		want := service.DiffRow{Op: service.DiffChanged, OldNo: 1, Old: "title: Old", NewNo: 1, New: "title: New"}
+		if rows[0] != want {
+			t.Errorf("expected %+v, got %+v", want, rows[0])
+		}
+
+		if rows[1].Op != service.DiffEqual {
+			t.Errorf("expected the date to be unchanged, got %+v", rows[1])
+		}
+	})
+
+	t.Run("insertions and removals keep their own side", func(t *testing.T) {
+		rows := service.DiffLines("a\nb\nc", "a\nc\nd")
+
+		var ops []service.DiffOp
+		for _, row := range rows {
+			ops = append(ops, row.Op)
+		}
+
+		want := []service.DiffOp{service.DiffEqual, service.DiffRemoved, service.DiffEqual, service.DiffAdded}
+		if len(ops) != len(want) {
+			t.Fatalf("expected %v, got %v", want, ops)
+		}
+
+		for i := range want {
+			if ops[i] != want[i] {
+				t.Errorf("row %d: expected %s, got %s", i, want[i], ops[i])
+			}
+		}
+	})
+}
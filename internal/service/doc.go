@@ -2,26 +2,122 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 	"timterests/internal/model"
 	"timterests/internal/storage"
 )
 
+// Storage prefixes for each built-in document type.
+const (
+	ArticlesPrefix    = "articles/"
+	ProjectsPrefix    = "projects/"
+	ReadingListPrefix = "reading-list/"
+	LettersPrefix     = "letters/"
+)
+
+// CachePrefix holds artifacts drawn from documents, such as social cards.
+// Writing one changes no document, so it leaves the search index and link
+// graph in place.
+const CachePrefix = "cache/"
+
+// ErrInvalidSlug is returned for a slug that could name something outside its
+// content directory.
+var ErrInvalidSlug = errors.New("invalid document slug")
+
 // getDoc initialises a zero-value T, sets its metadata, fetches and prepares
 // the file from storage, and returns a pointer to the result.
+//
+// The ID is the slug of key, so it stays the same however many other documents
+// are added or removed. Behind an Index the decoded document is reused until
+// the key changes.
 func getDoc[T any, PT interface {
 	*T
 	model.MetaSetter
-}](ctx context.Context, s storage.Storage, key string, id int) (*T, error) {
-	var doc T
+}](ctx context.Context, s storage.Backend, key string) (*T, error) {
+	var (
+		doc T
+		gen uint64
+	)
 
-	PT(&doc).SetMeta(strconv.Itoa(id), key)
+	idx, indexed := s.(*Index)
+	if indexed {
+		var cached any
 
-	err := s.GetPreparedFile(ctx, key, PT(&doc))
+		cached, gen = idx.document(key)
+		if d, ok := cached.(T); ok {
+			return &d, nil
+		}
+	}
+
+	PT(&doc).SetMeta(storage.SlugFromKey(key), key)
+
+	err := storage.GetPreparedFile(ctx, s, key, PT(&doc))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get prepared file: %w", err)
 	}
 
+	if indexed {
+		idx.storeDocument(key, doc, gen)
+	}
+
 	return &doc, nil
 }
+
+// Published returns the documents the public may see at now, keeping their
+// order. Drafts and documents scheduled for later are dropped.
+func Published[T any, PT interface {
+	*T
+	model.Publishable
+}](docs []T, now time.Time) []T {
+	visible := make([]T, 0, len(docs))
+
+	for i := range docs {
+		if PT(&docs[i]).IsPublished(now) {
+			visible = append(visible, docs[i])
+		}
+	}
+
+	return visible
+}
+
+// documentKey returns the metadata key for slug under prefix. Slugs arrive in
+// URLs, so anything that is not a plain filename is refused.
+func documentKey(prefix, slug string) (string, error) {
+	if slug == "" || slug == "." || slug == ".." || strings.ContainsAny(slug, `/\`) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSlug, slug)
+	}
+
+	return prefix + slug + ".yaml", nil
+}
+
+// documentKeys lists the metadata keys of the documents under prefix, in
+// storage listing order, whichever layout each is stored in.
+func documentKeys(ctx context.Context, s storage.Backend, prefix string) ([]string, error) {
+	return storage.DocumentKeys(ctx, s, prefix)
+}
+
+// LegacySlug resolves an ID from the old ?id= URLs to a slug. Those IDs were
+// positions in the storage listing, so the answer is only as good as the
+// listing is unchanged since the link was shared. An ID that is not a number is
+// taken to be a slug already.
+func LegacySlug(ctx context.Context, s storage.Backend, prefix, id string) (string, error) {
+	position, err := strconv.Atoi(id)
+	if err != nil {
+		return id, nil
+	}
+
+	keys, err := documentKeys(ctx, s, prefix)
+	if err != nil {
+		return "", err
+	}
+
+	if position < 0 || position >= len(keys) {
+		return "", fmt.Errorf("no document at position %d under %s", position, prefix)
+	}
+
+	return storage.SlugFromKey(keys[position]), nil
+}
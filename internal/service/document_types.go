@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+
+	"timterests/internal/model"
+	"timterests/internal/storage"
+)
+
+// reservedPrefixes are the storage prefixes already in use, which a schema type
+// cannot share: those of the built-in types and of what is kept beside them.
+// Names the site's routes use are turned away when the routes are built.
+var reservedPrefixes = []string{
+	ArticlesPrefix, ProjectsPrefix, ReadingListPrefix, LettersPrefix,
+	CachePrefix, storage.TrashPrefix, storage.HistoryPrefix,
+	"about/", "auth/",
+}
+
+// IsReservedTypeName reports whether name is already in use as a storage
+// prefix, so a schema type cannot be called it.
+func IsReservedTypeName(name string) bool {
+	return slices.Contains(reservedPrefixes, name+"/")
+}
+
+// LoadDocumentTypes reads the custom document types declared in the schema
+// file at path. An empty path declares none. Types named after a storage prefix
+// already in use are logged and left out, so one bad entry does not lose the
+// others.
+func LoadDocumentTypes(path string) ([]model.DocumentSchema, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading document types: %w", err)
+	}
+
+	schemas, err := model.ParseSchemas(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing document types: %w", err)
+	}
+
+	usable := make([]model.DocumentSchema, 0, len(schemas))
+
+	for _, schema := range schemas {
+		if IsReservedTypeName(schema.Name) {
+			log.Printf("ignoring document type %q in %s: the name is already in use", schema.Name, path)
+
+			continue
+		}
+
+		usable = append(usable, schema)
+	}
+
+	return usable, nil
+}
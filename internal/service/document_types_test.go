@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"timterests/internal/service"
+)
+
+func TestLoadDocumentTypes(t *testing.T) {
+	t.Run("no file declares no types", func(t *testing.T) {
+		schemas, err := service.LoadDocumentTypes("")
+		if err != nil || schemas != nil {
+			t.Errorf("expected no types, got %v (%v)", schemas, err)
+		}
+	})
+
+	t.Run("leaves out types named after something in use", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "document-types.yaml")
+
+		err := os.WriteFile(path, []byte("types:\n  - name: recipes\n  - name: trash\n"), 0o600)
+		if err != nil {
+			t.Fatalf("failed to write document types: %v", err)
+		}
+
+		schemas, err := service.LoadDocumentTypes(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var names []string
+		for _, schema := range schemas {
+			names = append(names, schema.Name)
+		}
+
+		if !slices.Equal(names, []string{"recipes"}) {
+			t.Errorf("expected only recipes, got %v", names)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := service.LoadDocumentTypes(filepath.Join(t.TempDir(), "missing.yaml"))
+		if err == nil {
+			t.Error("expected an error for a missing file")
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "document-types.yaml")
+
+		err := os.WriteFile(path, []byte("types:\n  - name: Not Valid\n"), 0o600)
+		if err != nil {
+			t.Fatalf("failed to write document types: %v", err)
+		}
+
+		_, err = service.LoadDocumentTypes(path)
+		if err == nil {
+			t.Error("expected an error for an invalid type name")
+		}
+	})
+}
@@ -0,0 +1,9 @@
+package service
+
+// Generation returns how many times the index has seen a document change.
+func (x *Index) Generation() uint64 {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return x.gen
+}
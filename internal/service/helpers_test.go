@@ -1,24 +1,21 @@
 package service_test
 
 import (
-	"context"
+	"os"
 	"path/filepath"
 	"testing"
 	"timterests/internal/storage"
 )
 
-// testSetup initialises a Storage instance pointing at the shared testdata directory.
-func testSetup(t *testing.T, ctx context.Context) *storage.Storage {
+// testSetup returns an in-memory copy of the shared testdata fixtures, so a
+// test can never modify the files on disk.
+func testSetup(t *testing.T) storage.Backend {
 	t.Helper()
-	t.Setenv("USE_S3", "false")
 
-	s, err := storage.NewStorage(ctx)
+	s, err := storage.NewMemoryBackendFromFS(os.DirFS(filepath.Join("..", "..", "storage", "testdata")))
 	if err != nil {
 		t.Fatalf("failed to initialize storage: %v", err)
 	}
 
-	// Point at the existing testdata fixtures used by the web package tests.
-	s.BaseDir = filepath.Join(s.BaseDir, "testdata")
-
 	return s
 }
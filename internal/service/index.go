@@ -0,0 +1,371 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"timterests/internal/storage"
+)
+
+// DefaultRescanInterval is how often Run checks storage for changes made outside
+// the app when no interval is configured.
+const DefaultRescanInterval = 5 * time.Minute
+
+// Index is a storage.Backend that serves reads from memory. Listings, file
+// contents, resolved images and decoded documents are loaded on first use and
+// kept until the app writes or deletes the key. Rescan catches changes made
+// behind the app's back, such as another instance or a manual S3 upload.
+//
+// Services use it like any other backend; getDoc additionally reuses decoded
+// documents so a listing does not re-parse every YAML file.
+type Index struct {
+	storage.Backend
+
+	mu       sync.RWMutex
+	gen      uint64
+	listings map[string][]storage.Object
+	files    map[string][]byte
+	images   map[string]string
+	docs     map[string]any
+
+	search    *searchIndex
+	searchGen uint64
+
+	links    *linkGraph
+	linksGen uint64
+}
+
+// NewIndex returns an empty index over b.
+func NewIndex(b storage.Backend) *Index {
+	return &Index{
+		Backend:  b,
+		listings: make(map[string][]storage.Object),
+		files:    make(map[string][]byte),
+		images:   make(map[string]string),
+		docs:     make(map[string]any),
+	}
+}
+
+// ListObjects implements storage.Backend.
+func (x *Index) ListObjects(ctx context.Context, prefix string) ([]storage.Object, error) {
+	dir := listingKey(prefix)
+
+	x.mu.RLock()
+	objects, ok := x.listings[dir]
+	gen := x.gen
+	x.mu.RUnlock()
+
+	if ok {
+		return slices.Clone(objects), nil
+	}
+
+	objects, err := x.Backend.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	x.mu.Lock()
+	if x.gen == gen {
+		x.listings[dir] = objects
+	}
+	x.mu.Unlock()
+
+	return slices.Clone(objects), nil
+}
+
+// GetFile implements storage.Backend.
+func (x *Index) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	x.mu.RLock()
+	content, ok := x.files[key]
+	gen := x.gen
+	x.mu.RUnlock()
+
+	if ok {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	file, err := x.Backend.GetFile(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err = io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+
+	x.mu.Lock()
+	if x.gen == gen {
+		x.files[key] = content
+	}
+	x.mu.Unlock()
+
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+// GetImage implements storage.Backend. On S3 every call is a download, so the
+// resolved path is remembered like any other read.
+func (x *Index) GetImage(ctx context.Context, key string) (string, error) {
+	x.mu.RLock()
+	imagePath, ok := x.images[key]
+	gen := x.gen
+	x.mu.RUnlock()
+
+	if ok {
+		return imagePath, nil
+	}
+
+	imagePath, err := x.Backend.GetImage(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	x.mu.Lock()
+	if x.gen == gen {
+		x.images[key] = imagePath
+	}
+	x.mu.Unlock()
+
+	return imagePath, nil
+}
+
+// WriteFile implements storage.Backend.
+func (x *Index) WriteFile(ctx context.Context, key string, content []byte) error {
+	defer x.invalidate(key)
+
+	return x.Backend.WriteFile(ctx, key, content)
+}
+
+// DeleteFile implements storage.Backend.
+func (x *Index) DeleteFile(ctx context.Context, key string) error {
+	defer x.invalidate(key)
+
+	return x.Backend.DeleteFile(ctx, key)
+}
+
+// Rescan re-lists every prefix the index has seen and drops anything whose
+// size or modification time no longer matches, or that has disappeared.
+func (x *Index) Rescan(ctx context.Context) error {
+	x.mu.RLock()
+	dirs := make([]string, 0, len(x.listings))
+	for dir := range x.listings {
+		dirs = append(dirs, dir)
+	}
+	x.mu.RUnlock()
+
+	for _, dir := range dirs {
+		prefix := dir
+		if prefix != "" {
+			prefix += "/"
+		}
+
+		x.mu.RLock()
+		gen := x.gen
+		x.mu.RUnlock()
+
+		fresh, err := x.Backend.ListObjects(ctx, prefix)
+		if err != nil {
+			return fmt.Errorf("rescanning %s: %w", dir, err)
+		}
+
+		x.mu.Lock()
+		stale := changedKeys(x.listings[dir], fresh)
+
+		// A write through the index since the listing was taken means fresh
+		// may already be behind, so leave the next read to list again.
+		if x.gen == gen {
+			x.listings[dir] = fresh
+		} else {
+			delete(x.listings, dir)
+		}
+
+		for _, key := range stale {
+			x.forget(key)
+		}
+		x.mu.Unlock()
+
+		if len(stale) > 0 {
+			log.Printf("index: %d changed object(s) under %s", len(stale), dir)
+		}
+	}
+
+	// Files read by key without ever listing their directory, such as images,
+	// cannot be compared, so they are simply fetched again.
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for key := range x.files {
+		if !x.listed(key) {
+			x.forget(key)
+		}
+	}
+
+	for key := range x.images {
+		if !x.listed(key) {
+			x.forget(key)
+		}
+	}
+
+	return nil
+}
+
+// Run calls Rescan every interval until ctx is done.
+func (x *Index) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := x.Rescan(ctx)
+			if err != nil {
+				log.Printf("index: rescan failed: %v", err)
+			}
+		}
+	}
+}
+
+// document returns the decoded document cached for key, if any, along with the
+// generation to pass to storeDocument.
+func (x *Index) document(key string) (any, uint64) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return x.docs[key], x.gen
+}
+
+// storeDocument caches a decoded document unless something was invalidated
+// since gen was read, in which case doc may already be out of date.
+func (x *Index) storeDocument(key string, doc any, gen uint64) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.gen == gen {
+		x.docs[key] = doc
+	}
+}
+
+// searchIndex returns the cached search index if nothing has changed since it
+// was built, along with the generation to pass to storeSearchIndex.
+func (x *Index) searchIndex() (*searchIndex, uint64) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if x.search != nil && x.searchGen == x.gen {
+		return x.search, x.gen
+	}
+
+	return nil, x.gen
+}
+
+// storeSearchIndex caches si as built at generation gen.
+func (x *Index) storeSearchIndex(si *searchIndex, gen uint64) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.gen == gen {
+		x.search = si
+		x.searchGen = gen
+	}
+}
+
+// linkGraph returns the cached link graph if nothing has changed since it was
+// built, along with the generation to pass to storeLinkGraph.
+func (x *Index) linkGraph() (*linkGraph, uint64) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if x.links != nil && x.linksGen == x.gen {
+		return x.links, x.gen
+	}
+
+	return nil, x.gen
+}
+
+// storeLinkGraph caches g as built at generation gen.
+func (x *Index) storeLinkGraph(g *linkGraph, gen uint64) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.gen == gen {
+		x.links = g
+		x.linksGen = gen
+	}
+}
+
+// invalidate drops everything derived from key, including the listing of its
+// directory, so the next read goes back to storage.
+func (x *Index) invalidate(key string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.forget(key)
+	delete(x.listings, listingKey(path.Dir(key)))
+}
+
+// forget drops the cached content of key. The caller must hold x.mu.
+func (x *Index) forget(key string) {
+	if !strings.HasPrefix(key, CachePrefix) {
+		x.gen++
+	}
+
+	delete(x.files, key)
+	delete(x.images, key)
+	delete(x.docs, key)
+
+	// A document is decoded from its .yaml, but drop it when the body changes
+	// too: the two are always written together and read as one.
+	base, isBody := strings.CutSuffix(key, ".md")
+	if isBody {
+		delete(x.docs, base+".yaml")
+	}
+}
+
+// listed reports whether the directory holding key has a cached listing, which
+// is what lets Rescan notice the key changing. The caller must hold x.mu.
+func (x *Index) listed(key string) bool {
+	_, ok := x.listings[listingKey(path.Dir(key))]
+
+	return ok
+}
+
+// listingKey normalises a prefix so "articles" and "articles/" share an entry.
+func listingKey(prefix string) string {
+	return strings.TrimSuffix(prefix, "/")
+}
+
+// changedKeys returns the keys that were added, removed or modified between two
+// listings of the same prefix.
+func changedKeys(old, fresh []storage.Object) []string {
+	previous := make(map[string]storage.Object, len(old))
+	for _, obj := range old {
+		previous[obj.Key] = obj
+	}
+
+	var changed []string
+
+	for _, obj := range fresh {
+		before, ok := previous[obj.Key]
+		if !ok || before.Size != obj.Size || !before.LastModified.Equal(obj.LastModified) {
+			changed = append(changed, obj.Key)
+		}
+
+		delete(previous, obj.Key)
+	}
+
+	for key := range previous {
+		changed = append(changed, key)
+	}
+
+	return changed
+}
@@ -0,0 +1,175 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// seedArticle writes both halves of an article straight to b.
+func seedArticle(t *testing.T, b storage.Backend, slug, title string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	err := b.WriteFile(ctx, "articles/"+slug+".yaml", []byte("title: "+title+"\ndate: 2024-01-01\n"))
+	if err != nil {
+		t.Fatalf("failed to seed yaml: %v", err)
+	}
+
+	err = b.WriteFile(ctx, "articles/"+slug+".md", []byte("# "+title+"\n"))
+	if err != nil {
+		t.Fatalf("failed to seed body: %v", err)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("serves reads from memory until the key changes", func(t *testing.T) {
+		backend := storage.NewMemoryBackend()
+		seedArticle(t, backend, "post", "Original")
+
+		idx := service.NewIndex(backend)
+
+		article, err := service.GetArticleBySlug(ctx, idx, "post")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if article.Title != "Original" {
+			t.Fatalf("expected title 'Original', got %q", article.Title)
+		}
+
+		// Changed behind the index's back, so the cached copy is still served.
+		seedArticle(t, backend, "post", "Changed outside")
+
+		article, err = service.GetArticleBySlug(ctx, idx, "post")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if article.Title != "Original" {
+			t.Errorf("expected the cached title 'Original', got %q", article.Title)
+		}
+	})
+
+	t.Run("writes through the index are visible immediately", func(t *testing.T) {
+		backend := storage.NewMemoryBackend()
+		seedArticle(t, backend, "post", "Original")
+
+		idx := service.NewIndex(backend)
+
+		_, err := service.ListArticles(ctx, idx, "all")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		seedArticle(t, idx, "post", "Edited")
+		seedArticle(t, idx, "another", "Another")
+
+		articles, err := service.ListArticles(ctx, idx, "all")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		titles := map[string]bool{}
+		for _, a := range articles {
+			titles[a.Title] = true
+		}
+
+		if len(articles) != 2 || !titles["Edited"] || !titles["Another"] {
+			t.Errorf("expected 'Edited' and 'Another', got %+v", titles)
+		}
+	})
+
+	t.Run("deleting through the index drops the document", func(t *testing.T) {
+		backend := storage.NewMemoryBackend()
+		seedArticle(t, backend, "post", "Doomed")
+
+		idx := service.NewIndex(backend)
+
+		_, err := service.ListArticles(ctx, idx, "all")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = storage.DeleteDocument(ctx, idx, "articles/post.yaml")
+		if err != nil {
+			t.Fatalf("failed to delete: %v", err)
+		}
+
+		articles, err := service.ListArticles(ctx, idx, "all")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(articles) != 0 {
+			t.Errorf("expected no articles after delete, got %d", len(articles))
+		}
+
+		_, err = service.GetArticleBySlug(ctx, idx, "post")
+		if err == nil {
+			t.Error("expected an error fetching a deleted article, got nil")
+		}
+	})
+
+	t.Run("rescan picks up changes made outside the app", func(t *testing.T) {
+		backend := storage.NewMemoryBackend()
+		seedArticle(t, backend, "post", "Original")
+
+		idx := service.NewIndex(backend)
+
+		_, err := service.ListArticles(ctx, idx, "all")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		seedArticle(t, backend, "post", "Changed outside")
+		seedArticle(t, backend, "added", "Added outside")
+
+		err = idx.Rescan(ctx)
+		if err != nil {
+			t.Fatalf("rescan failed: %v", err)
+		}
+
+		articles, err := service.ListArticles(ctx, idx, "all")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		titles := map[string]bool{}
+		for _, a := range articles {
+			titles[a.Title] = true
+		}
+
+		if !titles["Changed outside"] || !titles["Added outside"] {
+			t.Errorf("expected both outside changes after rescan, got %+v", titles)
+		}
+	})
+}
+
+// Caching a drawn card must not throw away the search index and link graph,
+// or every card a crawler fetches would rebuild them.
+func TestIndexCacheWritesKeepDerivedData(t *testing.T) {
+	ctx := context.Background()
+	idx := service.NewIndex(storage.NewMemoryBackend())
+
+	gen := idx.Generation()
+
+	err := idx.WriteFile(ctx, service.CachePrefix+"og/articles/post.png", []byte("png"))
+	if err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if idx.Generation() != gen {
+		t.Error("expected a cache write to leave the generation alone")
+	}
+
+	seedArticle(t, idx, "post", "Post")
+
+	if idx.Generation() == gen {
+		t.Error("expected a document write to move the generation on")
+	}
+}
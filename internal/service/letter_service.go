@@ -2,72 +2,51 @@ package service
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"slices"
 	"sort"
-	"strings"
 	"timterests/internal/model"
 	"timterests/internal/storage"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
 )
 
 // ListLetters retrieves all letters from storage, optionally filtering by tag.
 // Pass tag="" or tag="all" to retrieve all letters.
-func ListLetters(ctx context.Context, s storage.Storage, tag string) ([]model.Letter, error) {
-	prefix := "letters/"
-
-	letterFiles, err := s.ListObjects(ctx, prefix)
+func ListLetters(ctx context.Context, s storage.Backend, tag string) ([]model.Letter, error) {
+	keys, err := documentKeys(ctx, s, LettersPrefix)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list objects: %w", err)
-	}
-
-	mdKeys := make(map[string]bool, len(letterFiles))
-	for _, obj := range letterFiles {
-		key := aws.ToString(obj.Key)
-		if strings.HasSuffix(key, ".md") {
-			mdKeys[key] = true
-		}
+		return nil, err
 	}
 
-	letters := make([]model.Letter, 0, len(letterFiles))
-
-	docIdx := 0
-
-	for _, obj := range letterFiles {
-		key := aws.ToString(obj.Key)
-
-		if key == prefix || !strings.HasSuffix(key, ".yaml") {
-			continue
-		}
-
-		if !mdKeys[strings.TrimSuffix(key, ".yaml")+".md"] {
-			log.Printf("ListLetters: skipping %s — no paired .md body file", key)
-
-			continue
-		}
+	letters := make([]model.Letter, 0, len(keys))
 
-		letter, err := GetLetter(ctx, s, key, docIdx)
+	for _, key := range keys {
+		letter, err := GetLetter(ctx, s, key)
 		if err != nil {
 			return nil, err
 		}
 
-		docIdx++
-
 		if tag == "all" || tag == "" || slices.Contains(letter.Tags, tag) {
 			letters = append(letters, *letter)
 		}
 	}
 
 	sort.Slice(letters, func(i, j int) bool {
-		return letters[i].Date > letters[j].Date
+		return letters[i].Date.Compare(letters[j].Date) > 0
 	})
 
 	return letters, nil
 }
 
-// GetLetter retrieves a single letter by its storage key and numeric ID.
-func GetLetter(ctx context.Context, s storage.Storage, key string, id int) (*model.Letter, error) {
-	return getDoc[model.Letter](ctx, s, key, id)
+// GetLetter retrieves a single letter by its storage key.
+func GetLetter(ctx context.Context, s storage.Backend, key string) (*model.Letter, error) {
+	return getDoc[model.Letter](ctx, s, key)
+}
+
+// GetLetterBySlug retrieves the letter addressed by slug, as used in its permalink.
+func GetLetterBySlug(ctx context.Context, s storage.Backend, slug string) (*model.Letter, error) {
+	key, err := documentKey(LettersPrefix, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetLetter(ctx, s, key)
 }
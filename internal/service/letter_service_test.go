@@ -9,10 +9,10 @@ import (
 
 func TestListLetters(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("returns all letters when tag is empty", func(t *testing.T) {
-		letters, err := service.ListLetters(ctx, *s, "")
+		letters, err := service.ListLetters(ctx, s, "")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -23,7 +23,7 @@ func TestListLetters(t *testing.T) {
 	})
 
 	t.Run("returns all letters when tag is 'all'", func(t *testing.T) {
-		letters, err := service.ListLetters(ctx, *s, "all")
+		letters, err := service.ListLetters(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -34,7 +34,7 @@ func TestListLetters(t *testing.T) {
 	})
 
 	t.Run("filters letters by tag", func(t *testing.T) {
-		letters, err := service.ListLetters(ctx, *s, "Tag1")
+		letters, err := service.ListLetters(ctx, s, "Tag1")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -51,7 +51,7 @@ func TestListLetters(t *testing.T) {
 	})
 
 	t.Run("returns empty slice for non-existent tag", func(t *testing.T) {
-		letters, err := service.ListLetters(ctx, *s, "does-not-exist")
+		letters, err := service.ListLetters(ctx, s, "does-not-exist")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -62,7 +62,7 @@ func TestListLetters(t *testing.T) {
 	})
 
 	t.Run("letters are sorted by date descending", func(t *testing.T) {
-		letters, err := service.ListLetters(ctx, *s, "all")
+		letters, err := service.ListLetters(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -77,16 +77,16 @@ func TestListLetters(t *testing.T) {
 
 func TestGetLetter(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
-	t.Run("retrieves letter by key and id", func(t *testing.T) {
-		letter, err := service.GetLetter(ctx, *s, "letters/test-letter.yaml", 0)
+	t.Run("retrieves letter by key", func(t *testing.T) {
+		letter, err := service.GetLetter(ctx, s, "letters/test-letter.yaml")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		if letter.ID != "0" {
-			t.Errorf("expected ID '0', got %q", letter.ID)
+		if letter.ID != "test-letter" {
+			t.Errorf("expected ID 'test-letter', got %q", letter.ID)
 		}
 
 		if letter.Title != "Test Letter" {
@@ -95,7 +95,7 @@ func TestGetLetter(t *testing.T) {
 	})
 
 	t.Run("returns error for non-existent key", func(t *testing.T) {
-		_, err := service.GetLetter(ctx, *s, "letters/does-not-exist.yaml", 0)
+		_, err := service.GetLetter(ctx, s, "letters/does-not-exist.yaml")
 		if err == nil {
 			t.Error("expected error for non-existent file, got nil")
 		}
@@ -6,57 +6,29 @@ import (
 	"fmt"
 	"log"
 	"slices"
-	"strings"
+	"time"
 	"timterests/internal/model"
 	"timterests/internal/storage"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
 )
 
 // ListProjects retrieves all projects from storage, optionally filtering by tag.
 // Pass tag="" or tag="all" to retrieve all projects.
-func ListProjects(ctx context.Context, s storage.Storage, tag string) ([]model.Project, error) {
+func ListProjects(ctx context.Context, s storage.Backend, tag string) ([]model.Project, error) {
 	var projects []model.Project
 
-	prefix := "projects/"
-
-	projectFiles, err := s.ListObjects(ctx, prefix)
+	keys, err := documentKeys(ctx, s, ProjectsPrefix)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list objects: %w", err)
-	}
-
-	mdKeys := make(map[string]bool, len(projectFiles))
-	for _, obj := range projectFiles {
-		key := aws.ToString(obj.Key)
-		if strings.HasSuffix(key, ".md") {
-			mdKeys[key] = true
-		}
+		return nil, err
 	}
 
-	docIdx := 0
-
-	for _, obj := range projectFiles {
-		key := aws.ToString(obj.Key)
-
-		if key == prefix || !strings.HasSuffix(key, ".yaml") {
-			continue
-		}
-
-		if !mdKeys[strings.TrimSuffix(key, ".yaml")+".md"] {
-			log.Printf("ListProjects: skipping %s — no paired .md body file", key)
-
-			continue
-		}
-
-		project, err := GetProject(ctx, s, key, docIdx)
+	for _, key := range keys {
+		project, err := GetProject(ctx, s, key)
 		if err != nil {
 			log.Printf("Failed to get project: %v", err)
 
 			return nil, err
 		}
 
-		docIdx++
-
 		if tag == "" || tag == "all" || slices.Contains(project.Tags, tag) {
 			projects = append(projects, *project)
 		}
@@ -65,10 +37,10 @@ func ListProjects(ctx context.Context, s storage.Storage, tag string) ([]model.P
 	return projects, nil
 }
 
-// GetProject retrieves a single project by its storage key and numeric ID,
+// GetProject retrieves a single project by its storage key,
 // including downloading and resolving its associated image.
-func GetProject(ctx context.Context, s storage.Storage, key string, id int) (*model.Project, error) {
-	project, err := getDoc[model.Project](ctx, s, key, id)
+func GetProject(ctx context.Context, s storage.Backend, key string) (*model.Project, error) {
+	project, err := getDoc[model.Project](ctx, s, key)
 	if err != nil {
 		return nil, err
 	}
@@ -87,14 +59,26 @@ func GetProject(ctx context.Context, s storage.Storage, key string, id int) (*mo
 	return project, nil
 }
 
-// GetFeaturedProject retrieves the project whose title matches featuredProjectTitle.
-// Returns an error if no match is found.
-func GetFeaturedProject(ctx context.Context, s storage.Storage, featuredProjectTitle string) (*model.Project, error) {
+// GetProjectBySlug retrieves the project addressed by slug, as used in its permalink.
+func GetProjectBySlug(ctx context.Context, s storage.Backend, slug string) (*model.Project, error) {
+	key, err := documentKey(ProjectsPrefix, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetProject(ctx, s, key)
+}
+
+// GetFeaturedProject retrieves the published project whose title matches
+// featuredProjectTitle. Returns an error if no match is found.
+func GetFeaturedProject(ctx context.Context, s storage.Backend, featuredProjectTitle string) (*model.Project, error) {
 	projects, err := ListProjects(ctx, s, "all")
 	if err != nil {
 		return nil, err
 	}
 
+	projects = Published(projects, time.Now())
+
 	if len(projects) == 0 {
 		return nil, errors.New("no projects found")
 	}
@@ -9,10 +9,10 @@ import (
 
 func TestListProjects(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("returns all projects when tag is empty", func(t *testing.T) {
-		projects, err := service.ListProjects(ctx, *s, "")
+		projects, err := service.ListProjects(ctx, s, "")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -23,7 +23,7 @@ func TestListProjects(t *testing.T) {
 	})
 
 	t.Run("returns all projects when tag is 'all'", func(t *testing.T) {
-		projects, err := service.ListProjects(ctx, *s, "all")
+		projects, err := service.ListProjects(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -34,7 +34,7 @@ func TestListProjects(t *testing.T) {
 	})
 
 	t.Run("filters projects by tag", func(t *testing.T) {
-		projects, err := service.ListProjects(ctx, *s, "Golang")
+		projects, err := service.ListProjects(ctx, s, "Golang")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -51,7 +51,7 @@ func TestListProjects(t *testing.T) {
 	})
 
 	t.Run("returns empty slice for non-existent tag", func(t *testing.T) {
-		projects, err := service.ListProjects(ctx, *s, "does-not-exist")
+		projects, err := service.ListProjects(ctx, s, "does-not-exist")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -64,16 +64,16 @@ func TestListProjects(t *testing.T) {
 
 func TestGetProject(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
-	t.Run("retrieves project by key and id", func(t *testing.T) {
-		project, err := service.GetProject(ctx, *s, "projects/test-project.yaml", 0)
+	t.Run("retrieves project by key", func(t *testing.T) {
+		project, err := service.GetProject(ctx, s, "projects/test-project.yaml")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		if project.ID != "0" {
-			t.Errorf("expected ID '0', got %q", project.ID)
+		if project.ID != "test-project" {
+			t.Errorf("expected ID 'test-project', got %q", project.ID)
 		}
 
 		if project.Title != "Test Project" {
@@ -82,14 +82,14 @@ func TestGetProject(t *testing.T) {
 	})
 
 	t.Run("returns error for non-existent key", func(t *testing.T) {
-		_, err := service.GetProject(ctx, *s, "projects/does-not-exist.yaml", 0)
+		_, err := service.GetProject(ctx, s, "projects/does-not-exist.yaml")
 		if err == nil {
 			t.Error("expected error for non-existent file, got nil")
 		}
 	})
 
 	t.Run("succeeds when project has no image path", func(t *testing.T) {
-		project, err := service.GetProject(ctx, *s, "projects/no-image-project.yaml", 0)
+		project, err := service.GetProject(ctx, s, "projects/no-image-project.yaml")
 		if err != nil {
 			t.Fatalf("expected no error for project without image, got: %v", err)
 		}
@@ -106,10 +106,10 @@ func TestGetProject(t *testing.T) {
 
 func TestGetFeaturedProject(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("returns project matching title", func(t *testing.T) {
-		project, err := service.GetFeaturedProject(ctx, *s, "Test Project")
+		project, err := service.GetFeaturedProject(ctx, s, "Test Project")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -120,7 +120,7 @@ func TestGetFeaturedProject(t *testing.T) {
 	})
 
 	t.Run("returns error when title does not match any project", func(t *testing.T) {
-		_, err := service.GetFeaturedProject(ctx, *s, "Does Not Exist")
+		_, err := service.GetFeaturedProject(ctx, s, "Does Not Exist")
 		if err == nil {
 			t.Error("expected error for non-existent featured project, got nil")
 		}
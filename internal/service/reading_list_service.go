@@ -5,55 +5,28 @@ import (
 	"fmt"
 	"log"
 	"slices"
+	"sort"
 	"strings"
 	"timterests/internal/model"
 	"timterests/internal/storage"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
 )
 
 // ListBooks retrieves all books from the reading list in storage,
 // optionally filtering by tag. Pass tag="" or tag="all" to retrieve all books.
-func ListBooks(ctx context.Context, s storage.Storage, tag string) ([]model.ReadingList, error) {
+func ListBooks(ctx context.Context, s storage.Backend, tag string) ([]model.ReadingList, error) {
 	var readingList []model.ReadingList
 
-	prefix := "reading-list/"
-
-	files, err := s.ListObjects(ctx, prefix)
+	keys, err := documentKeys(ctx, s, ReadingListPrefix)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list objects: %w", err)
-	}
-
-	mdKeys := make(map[string]bool, len(files))
-	for _, obj := range files {
-		key := aws.ToString(obj.Key)
-		if strings.HasSuffix(key, ".md") {
-			mdKeys[key] = true
-		}
+		return nil, err
 	}
 
-	docIdx := 0
-
-	for _, obj := range files {
-		key := aws.ToString(obj.Key)
-
-		if key == prefix || !strings.HasSuffix(key, ".yaml") {
-			continue
-		}
-
-		if !mdKeys[strings.TrimSuffix(key, ".yaml")+".md"] {
-			log.Printf("ListBooks: skipping %s — no paired .md body file", key)
-
-			continue
-		}
-
-		book, err := GetBook(ctx, s, key, docIdx)
+	for _, key := range keys {
+		book, err := GetBook(ctx, s, key)
 		if err != nil {
 			return nil, err
 		}
 
-		docIdx++
-
 		if tag == "all" || tag == "" || slices.Contains(book.Tags, tag) {
 			readingList = append(readingList, *book)
 		}
@@ -62,10 +35,10 @@ func ListBooks(ctx context.Context, s storage.Storage, tag string) ([]model.Read
 	return readingList, nil
 }
 
-// GetBook retrieves a single book by its storage key and numeric ID,
+// GetBook retrieves a single book by its storage key,
 // including downloading and resolving its associated cover image.
-func GetBook(ctx context.Context, s storage.Storage, key string, id int) (*model.ReadingList, error) {
-	book, err := getDoc[model.ReadingList](ctx, s, key, id)
+func GetBook(ctx context.Context, s storage.Backend, key string) (*model.ReadingList, error) {
+	book, err := getDoc[model.ReadingList](ctx, s, key)
 	if err != nil {
 		return nil, err
 	}
@@ -83,3 +56,72 @@ func GetBook(ctx context.Context, s storage.Storage, key string, id int) (*model
 
 	return book, nil
 }
+
+// GetBookBySlug retrieves the book addressed by slug, as used in its permalink.
+func GetBookBySlug(ctx context.Context, s storage.Backend, slug string) (*model.ReadingList, error) {
+	key, err := documentKey(ReadingListPrefix, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetBook(ctx, s, key)
+}
+
+// Orders the reading list can be sorted in. The default keeps storage order.
+const (
+	SortBooksTitle    = "title"
+	SortBooksRating   = "rating"   // highest first
+	SortBooksFinished = "finished" // most recently finished first
+	SortBooksStarted  = "started"  // most recently started first
+	SortBooksProgress = "progress" // furthest through first
+)
+
+// FilterBooksByState keeps the books in the given reading state. Pass state=""
+// or state="all" to keep them all.
+func FilterBooksByState(books []model.ReadingList, state string) []model.ReadingList {
+	if state == "" || state == "all" {
+		return books
+	}
+
+	var filtered []model.ReadingList
+
+	for i := range books {
+		if books[i].State() == state {
+			filtered = append(filtered, books[i])
+		}
+	}
+
+	return filtered
+}
+
+// SortBooks orders books in place by one of the SortBooks orders. Ties, and an
+// unknown order, keep the order books arrived in.
+func SortBooks(books []model.ReadingList, by string) {
+	var key func(b *model.ReadingList) model.Date
+
+	switch by {
+	case SortBooksTitle:
+		sort.SliceStable(books, func(i, j int) bool {
+			return strings.ToLower(books[i].Title) < strings.ToLower(books[j].Title)
+		})
+
+		return
+	case SortBooksRating:
+		sort.SliceStable(books, func(i, j int) bool { return books[i].Rating > books[j].Rating })
+
+		return
+	case SortBooksProgress:
+		sort.SliceStable(books, func(i, j int) bool { return books[i].PercentRead() > books[j].PercentRead() })
+
+		return
+	case SortBooksFinished:
+		key = func(b *model.ReadingList) model.Date { return b.Finished }
+	case SortBooksStarted:
+		key = func(b *model.ReadingList) model.Date { return b.Started }
+	default:
+		return
+	}
+
+	// An empty date falls to the end.
+	sort.SliceStable(books, func(i, j int) bool { return key(&books[i]).Compare(key(&books[j])) > 0 })
+}
@@ -9,10 +9,10 @@ import (
 
 func TestListBooks(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
 	t.Run("returns all books when tag is empty", func(t *testing.T) {
-		books, err := service.ListBooks(ctx, *s, "")
+		books, err := service.ListBooks(ctx, s, "")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -23,7 +23,7 @@ func TestListBooks(t *testing.T) {
 	})
 
 	t.Run("returns all books when tag is 'all'", func(t *testing.T) {
-		books, err := service.ListBooks(ctx, *s, "all")
+		books, err := service.ListBooks(ctx, s, "all")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -34,7 +34,7 @@ func TestListBooks(t *testing.T) {
 	})
 
 	t.Run("filters books by tag", func(t *testing.T) {
-		books, err := service.ListBooks(ctx, *s, "Testing")
+		books, err := service.ListBooks(ctx, s, "Testing")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -51,7 +51,7 @@ func TestListBooks(t *testing.T) {
 	})
 
 	t.Run("returns empty slice for non-existent tag", func(t *testing.T) {
-		books, err := service.ListBooks(ctx, *s, "does-not-exist")
+		books, err := service.ListBooks(ctx, s, "does-not-exist")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -64,16 +64,16 @@ func TestListBooks(t *testing.T) {
 
 func TestGetBook(t *testing.T) {
 	ctx := context.Background()
-	s := testSetup(t, ctx)
+	s := testSetup(t)
 
-	t.Run("retrieves book by key and id", func(t *testing.T) {
-		book, err := service.GetBook(ctx, *s, "reading-list/test-book.yaml", 0)
+	t.Run("retrieves book by key", func(t *testing.T) {
+		book, err := service.GetBook(ctx, s, "reading-list/test-book.yaml")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		if book.ID != "0" {
-			t.Errorf("expected ID '0', got %q", book.ID)
+		if book.ID != "test-book" {
+			t.Errorf("expected ID 'test-book', got %q", book.ID)
 		}
 
 		if book.Title != "Test Book" {
@@ -86,14 +86,14 @@ func TestGetBook(t *testing.T) {
 	})
 
 	t.Run("returns error for non-existent key", func(t *testing.T) {
-		_, err := service.GetBook(ctx, *s, "reading-list/does-not-exist.yaml", 0)
+		_, err := service.GetBook(ctx, s, "reading-list/does-not-exist.yaml")
 		if err == nil {
 			t.Error("expected error for non-existent file, got nil")
 		}
 	})
 
 	t.Run("succeeds when book has no image path", func(t *testing.T) {
-		book, err := service.GetBook(ctx, *s, "reading-list/no-image-book.yaml", 0)
+		book, err := service.GetBook(ctx, s, "reading-list/no-image-book.yaml")
 		if err != nil {
 			t.Fatalf("expected no error for book without image, got: %v", err)
 		}
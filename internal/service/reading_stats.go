@@ -0,0 +1,106 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"timterests/internal/model"
+)
+
+// maxTopTags is how many tags each year of reading stats lists.
+const maxTopTags = 3
+
+// TagCount is how many books in a year carried a tag.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// YearStats summarises the books finished in one year.
+type YearStats struct {
+	Year    int
+	Books   int
+	Pages   int
+	Rereads int
+	// AverageRating is over the rated books only; 0 when none were rated.
+	AverageRating float64
+	TopTags       []TagCount
+}
+
+// ReadingStats groups the finished books by the year they were finished, most
+// recent year first. Books without a finish date have no year and are left
+// out.
+func ReadingStats(books []model.ReadingList) []YearStats {
+	type tally struct {
+		stats       YearStats
+		ratingTotal int
+		rated       int
+		tags        map[string]*TagCount
+	}
+
+	years := make(map[int]*tally)
+
+	for i := range books {
+		year := books[i].FinishedYear()
+		if year == 0 {
+			continue
+		}
+
+		t, ok := years[year]
+		if !ok {
+			t = &tally{stats: YearStats{Year: year}, tags: make(map[string]*TagCount)}
+			years[year] = t
+		}
+
+		t.stats.Books++
+		t.stats.Pages += books[i].Pages
+		t.stats.Rereads += books[i].Rereads
+
+		if books[i].Rating > 0 {
+			t.ratingTotal += books[i].Rating
+			t.rated++
+		}
+
+		// Tags are counted case-insensitively under the first spelling seen.
+		for _, tag := range books[i].Tags {
+			key := strings.ToLower(strings.TrimSpace(tag))
+			if key == "" {
+				continue
+			}
+
+			count, ok := t.tags[key]
+			if !ok {
+				count = &TagCount{Tag: strings.TrimSpace(tag)}
+				t.tags[key] = count
+			}
+
+			count.Count++
+		}
+	}
+
+	stats := make([]YearStats, 0, len(years))
+
+	for _, t := range years {
+		if t.rated > 0 {
+			t.stats.AverageRating = float64(t.ratingTotal) / float64(t.rated)
+		}
+
+		for _, count := range t.tags {
+			t.stats.TopTags = append(t.stats.TopTags, *count)
+		}
+
+		slices.SortFunc(t.stats.TopTags, func(a, b TagCount) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Tag, b.Tag))
+		})
+
+		if len(t.stats.TopTags) > maxTopTags {
+			t.stats.TopTags = t.stats.TopTags[:maxTopTags]
+		}
+
+		stats = append(stats, t.stats)
+	}
+
+	slices.SortFunc(stats, func(a, b YearStats) int { return cmp.Compare(b.Year, a.Year) })
+
+	return stats
+}
@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"testing"
+	"timterests/internal/model"
+	"timterests/internal/service"
+)
+
+func readingBooks() []model.ReadingList {
+	book := func(title, state, finished string, pages, rating int, tags ...string) model.ReadingList {
+		b := model.ReadingList{ReadingStatus: state, Finished: model.Date(finished), Pages: pages, Rating: rating}
+		b.Title = title
+		b.Tags = tags
+
+		return b
+	}
+
+	return []model.ReadingList{
+		book("Dune", model.ReadingFinished, "2025-05-01", 600, 5, "Fiction", "SciFi"),
+		book("Go", model.ReadingFinished, "2026-01-10", 300, 4, "Go", "programming"),
+		book("Rust", model.ReadingReading, "", 500, 0, "programming"),
+		book("Clean", model.ReadingFinished, "2026-03-02", 400, 0, "Programming"),
+		book("Later", model.ReadingWantToRead, "", 200, 0, "Fiction"),
+	}
+}
+
+func TestFilterAndSortBooks(t *testing.T) {
+	t.Run("filters by reading state", func(t *testing.T) {
+		books := service.FilterBooksByState(readingBooks(), model.ReadingFinished)
+		if len(books) != 3 {
+			t.Errorf("expected 3 finished books, got %d", len(books))
+		}
+
+		if len(service.FilterBooksByState(readingBooks(), "all")) != 5 {
+			t.Error("expected all to keep every book")
+		}
+	})
+
+	titles := func(books []model.ReadingList) []string {
+		var out []string
+		for _, b := range books {
+			out = append(out, b.Title)
+		}
+
+		return out
+	}
+
+	tests := []struct {
+		by   string
+		want []string
+	}{
+		{service.SortBooksRating, []string{"Dune", "Go", "Rust", "Clean", "Later"}},
+		{service.SortBooksFinished, []string{"Clean", "Go", "Dune", "Rust", "Later"}},
+		{service.SortBooksTitle, []string{"Clean", "Dune", "Go", "Later", "Rust"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("sorts by "+tt.by, func(t *testing.T) {
+			books := readingBooks()
+			service.SortBooks(books, tt.by)
+
+			got := titles(books)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestReadingStats(t *testing.T) {
+	stats := service.ReadingStats(readingBooks())
+
+	if len(stats) != 2 || stats[0].Year != 2026 || stats[1].Year != 2025 {
+		t.Fatalf("expected 2026 then 2025, got %+v", stats)
+	}
+
+	year := stats[0]
+	if year.Books != 2 || year.Pages != 700 {
+		t.Errorf("expected 2 books and 700 pages in 2026, got %d and %d", year.Books, year.Pages)
+	}
+
+	// Only the rated book counts towards the average.
+	if year.AverageRating != 4 {
+		t.Errorf("expected an average rating of 4, got %v", year.AverageRating)
+	}
+
+	if len(year.TopTags) == 0 || year.TopTags[0].Tag != "programming" || year.TopTags[0].Count != 2 {
+		t.Errorf("expected programming twice, ignoring case, got %+v", year.TopTags)
+	}
+}
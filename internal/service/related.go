@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"timterests/internal/model"
+	"timterests/internal/storage"
+	"unicode/utf8"
+)
+
+// MaxRelated caps how many related documents are suggested.
+const MaxRelated = 3
+
+const (
+	// relatedTagWeight puts tags ahead of body text: a shared tag is a
+	// deliberate choice, shared words may be coincidence.
+	relatedTagWeight = 2
+	// relatedMinScore keeps out documents that only brush against each other.
+	relatedMinScore = 0.1
+	// relatedMinTermLength skips short words, which say little about a topic.
+	relatedMinTermLength = 3
+)
+
+// RelatedDocument is a document suggested as reading after another.
+type RelatedDocument struct {
+	DocType string
+	Slug    string
+	Score   float64
+}
+
+// termVector maps a document's body terms to TF-IDF weights, scaled to unit
+// length so documents of any size compare fairly.
+type termVector map[string]float64
+
+// RelatedDocuments suggests what to read after doc, across every public
+// document type. Documents score by the tags they share with doc and by how
+// alike their bodies are, best first.
+//
+// A document that lists "type/slug" keys under related gets exactly those, in
+// its order, instead of the computed picks. Letters and unpublished documents
+// are never suggested.
+func RelatedDocuments(
+	ctx context.Context,
+	s storage.Backend,
+	docType string,
+	doc model.Document,
+	now time.Time,
+) ([]RelatedDocument, error) {
+	si, err := getSearchIndex(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := func(d searchDoc) bool {
+		return d.docType != "letters" && d.meta.IsPublished(now) && (d.docType != docType || d.slug != doc.ID)
+	}
+
+	if len(doc.Related) > 0 {
+		return si.listed(doc.Related, visible), nil
+	}
+
+	var own termVector
+
+	for i, d := range si.docs {
+		if d.docType == docType && d.slug == doc.ID {
+			own = si.vectors[i]
+
+			break
+		}
+	}
+
+	var related []RelatedDocument
+
+	for i, d := range si.docs {
+		if !visible(d) {
+			continue
+		}
+
+		score := relatedTagWeight*tagSimilarity(doc.Tags, d.tags) + own.cosine(si.vectors[i])
+		if score < relatedMinScore {
+			continue
+		}
+
+		related = append(related, RelatedDocument{DocType: d.docType, Slug: d.slug, Score: score})
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+
+		return related[i].DocType+"/"+related[i].Slug < related[j].DocType+"/"+related[j].Slug
+	})
+
+	if len(related) > MaxRelated {
+		related = related[:MaxRelated]
+	}
+
+	return related, nil
+}
+
+// listed resolves related keys such as "articles/my-post" to the documents
+// they name. Keys naming nothing that may be shown are skipped.
+func (si *searchIndex) listed(keys []string, visible func(searchDoc) bool) []RelatedDocument {
+	related := make([]RelatedDocument, 0, len(keys))
+
+	for _, key := range keys {
+		docType, slug, found := strings.Cut(strings.TrimSuffix(strings.TrimSpace(key), ".yaml"), "/")
+		if !found {
+			continue
+		}
+
+		for _, d := range si.docs {
+			if d.docType == docType && d.slug == slug && visible(d) {
+				related = append(related, RelatedDocument{DocType: docType, Slug: slug})
+
+				break
+			}
+		}
+	}
+
+	return related
+}
+
+// tagSimilarity is the cosine similarity of two tag sets: 1 when they are the
+// same, 0 when they share nothing.
+func tagSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+
+	for _, tag := range a {
+		for _, other := range b {
+			if strings.EqualFold(tag, other) {
+				shared++
+
+				break
+			}
+		}
+	}
+
+	return float64(shared) / math.Sqrt(float64(len(a)*len(b)))
+}
+
+// bodyVectors weighs the body terms of every document. A term in every
+// document weighs nothing, so common words drop out without a stop list.
+func bodyVectors(docs []searchDoc) []termVector {
+	counts := make([]map[string]int, len(docs))
+	frequency := make(map[string]int)
+
+	for i, doc := range docs {
+		counts[i] = make(map[string]int)
+
+		for _, term := range tokenize(doc.body) {
+			if utf8.RuneCountInString(term) < relatedMinTermLength {
+				continue
+			}
+
+			if counts[i][term] == 0 {
+				frequency[term]++
+			}
+
+			counts[i][term]++
+		}
+	}
+
+	vectors := make([]termVector, len(docs))
+
+	for i, terms := range counts {
+		vector := make(termVector, len(terms))
+		norm := 0.0
+
+		for term, count := range terms {
+			weight := (1 + math.Log(float64(count))) * math.Log(float64(len(docs))/float64(frequency[term]))
+			if weight == 0 {
+				continue
+			}
+
+			vector[term] = weight
+			norm += weight * weight
+		}
+
+		norm = math.Sqrt(norm)
+		for term := range vector {
+			vector[term] /= norm
+		}
+
+		vectors[i] = vector
+	}
+
+	return vectors
+}
+
+// cosine returns the cosine similarity of two unit vectors.
+func (v termVector) cosine(other termVector) float64 {
+	if len(other) < len(v) {
+		v, other = other, v
+	}
+
+	sum := 0.0
+	for term, weight := range v {
+		sum += weight * other[term]
+	}
+
+	return sum
+}
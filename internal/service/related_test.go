@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+func TestRelatedDocuments(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := func(t *testing.T, s storage.Backend, key, meta, body string) {
+		t.Helper()
+
+		err := storage.WriteDocument(ctx, s, key, []byte(meta), []byte(body))
+		if err != nil {
+			t.Fatalf("failed to seed %s: %v", key, err)
+		}
+	}
+
+	related := func(t *testing.T, s storage.Backend, slug string) []string {
+		t.Helper()
+
+		article, err := service.GetArticleBySlug(ctx, s, slug)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		docs, err := service.RelatedDocuments(ctx, s, "articles", article.Document, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		keys := make([]string, 0, len(docs))
+		for _, d := range docs {
+			keys = append(keys, d.DocType+"/"+d.Slug)
+		}
+
+		return keys
+	}
+
+	t.Run("ranks shared tags and similar text first", func(t *testing.T) {
+		s := testSetup(t)
+
+		seed(t, s, "articles/gardening.yaml", "title: Gardening\ndate: \"2026-01-01\"\ntags: [plants]\n",
+			"Tomatoes and compost and seedlings in the greenhouse.")
+		seed(t, s, "articles/more-gardening.yaml", "title: More Gardening\ndate: \"2026-01-02\"\ntags: [plants]\n",
+			"Seedlings want compost before the tomatoes go out.")
+		seed(t, s, "articles/greenhouse.yaml", "title: Greenhouse\ndate: \"2026-01-03\"\n",
+			"Building a greenhouse for tomatoes and seedlings.")
+		seed(t, s, "articles/hidden.yaml", "title: Hidden\ndate: \"2026-01-04\"\ntags: [plants]\nstatus: draft\n",
+			"Tomatoes and compost and seedlings.")
+
+		keys := related(t, s, "gardening")
+		if len(keys) < 2 || keys[0] != "articles/more-gardening" || keys[1] != "articles/greenhouse" {
+			t.Errorf("expected more-gardening then greenhouse first, got %v", keys)
+		}
+
+		for _, key := range keys {
+			if key == "articles/gardening" || key == "articles/hidden" || key == "letters/test-letter" {
+				t.Errorf("did not expect %s to be suggested", key)
+			}
+		}
+	})
+
+	t.Run("suggests other document types", func(t *testing.T) {
+		s := testSetup(t)
+
+		keys := related(t, s, "test-article")
+		if len(keys) == 0 || len(keys) > service.MaxRelated {
+			t.Fatalf("expected between 1 and %d suggestions, got %v", service.MaxRelated, keys)
+		}
+
+		// test-article is the only article, so everything suggested is
+		// another type.
+		for _, key := range keys {
+			if strings.HasPrefix(key, "articles/") || key == "letters/test-letter" {
+				t.Errorf("did not expect %s to be suggested", key)
+			}
+		}
+	})
+
+	t.Run("related keys override the computed picks", func(t *testing.T) {
+		s := testSetup(t)
+
+		seed(t, s, "articles/picked.yaml",
+			"title: Picked\ndate: \"2026-01-01\"\ntags: [tag1]\n"+
+				"related:\n  - reading-list/test-book\n  - articles/missing\n  - letters/test-letter\n  - projects/timterests\n",
+			"Body")
+
+		keys := related(t, s, "picked")
+
+		want := []string{"reading-list/test-book", "projects/timterests"}
+		if len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] {
+			t.Errorf("expected %v, got %v", want, keys)
+		}
+	})
+}
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"timterests/internal/model"
+	"timterests/internal/storage"
+	"unicode"
+)
+
+// MaxSearchResults caps how many results Search returns.
+const MaxSearchResults = 50
+
+// snippetRadius is roughly how many characters of context a snippet keeps on
+// each side of the first match.
+const snippetRadius = 80
+
+// Field weights: a match in the title says far more about a document than the
+// same word somewhere in its body.
+var searchFieldWeights = map[string]float64{
+	"title":    5,
+	"tags":     4,
+	"subtitle": 3,
+	"preview":  2,
+	"body":     1,
+}
+
+// Fragment is a piece of text that either matched the query or did not, so
+// templates can highlight matches without building HTML from user input.
+type Fragment struct {
+	Text  string
+	Match bool
+}
+
+// SearchResult is one document matching a query.
+type SearchResult struct {
+	DocType string
+	Slug    string
+	Title   []Fragment
+	Snippet []Fragment
+	Tags    []string
+	Score   float64
+}
+
+// searchDoc is the text of one document as the search index sees it. meta is
+// kept so visibility can be decided per query: a scheduled document becomes
+// public without anything being rebuilt.
+type searchDoc struct {
+	meta     model.Document
+	docType  string
+	slug     string
+	title    string
+	subtitle string
+	preview  string
+	tags     []string
+	body     string
+}
+
+// posting records how often a term appears in a document, weighted by field.
+type posting struct {
+	doc    int
+	weight float64
+}
+
+// searchIndex is an inverted index from terms to the documents containing them.
+// terms is kept sorted so a query word can match as a prefix while typing.
+type searchIndex struct {
+	docs     []searchDoc
+	postings map[string][]posting
+	terms    []string
+	// vectors holds each document's body terms for finding related documents,
+	// in the same order as docs.
+	vectors []termVector
+}
+
+// Search returns the documents matching every word of query, best first.
+// Letters and unpublished documents are private, so they are only searched
+// when includePrivate is set.
+func Search(ctx context.Context, s storage.Backend, query string, includePrivate bool) ([]SearchResult, error) {
+	words := tokenize(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	si, err := getSearchIndex(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := si.match(words)
+	now := time.Now()
+
+	results := make([]SearchResult, 0, len(scores))
+
+	for docIdx, score := range scores {
+		doc := si.docs[docIdx]
+		if !includePrivate && (doc.docType == "letters" || !doc.meta.IsPublished(now)) {
+			continue
+		}
+
+		results = append(results, SearchResult{
+			DocType: doc.docType,
+			Slug:    doc.slug,
+			Title:   highlight(doc.title, words),
+			Snippet: snippet(doc, words),
+			Tags:    doc.tags,
+			Score:   score,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return results[i].Slug < results[j].Slug
+	})
+
+	if len(results) > MaxSearchResults {
+		results = results[:MaxSearchResults]
+	}
+
+	return results, nil
+}
+
+// getSearchIndex returns the search index for s. Behind an Index it is built
+// once and reused until any document changes.
+func getSearchIndex(ctx context.Context, s storage.Backend) (*searchIndex, error) {
+	idx, indexed := s.(*Index)
+	if !indexed {
+		return buildSearchIndex(ctx, s)
+	}
+
+	si, gen := idx.searchIndex()
+	if si != nil {
+		return si, nil
+	}
+
+	si, err := buildSearchIndex(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.storeSearchIndex(si, gen)
+
+	return si, nil
+}
+
+// buildSearchIndex reads every document of every type and indexes its text.
+func buildSearchIndex(ctx context.Context, s storage.Backend) (*searchIndex, error) {
+	var docs []searchDoc
+
+	articles, err := ListArticles(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range articles {
+		docs = append(docs, newSearchDoc("articles", a.Document))
+	}
+
+	projects, err := ListProjects(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range projects {
+		docs = append(docs, newSearchDoc("projects", p.Document))
+	}
+
+	books, err := ListBooks(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range books {
+		docs = append(docs, newSearchDoc("reading-list", b.Document))
+	}
+
+	letters, err := ListLetters(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range letters {
+		docs = append(docs, newSearchDoc("letters", l.Document))
+	}
+
+	// Bodies are read after listing so a missing body only costs that
+	// document its body text, not its place in the index.
+	for i := range docs {
+		key := docs[i].docType + "/" + docs[i].slug + ".yaml"
+
+		body, err := storage.GetDocumentBody(ctx, s, key)
+		if err == nil {
+			docs[i].body = html.UnescapeString(storage.RemoveHTMLTags(body))
+		}
+	}
+
+	return newSearchIndex(docs), nil
+}
+
+func newSearchDoc(docType string, doc model.Document) searchDoc {
+	return searchDoc{
+		meta:     doc,
+		docType:  docType,
+		slug:     doc.ID,
+		title:    doc.Title,
+		subtitle: doc.Subtitle,
+		preview:  doc.Preview,
+		tags:     doc.Tags,
+	}
+}
+
+// newSearchIndex builds the inverted index over docs.
+func newSearchIndex(docs []searchDoc) *searchIndex {
+	si := &searchIndex{docs: docs, postings: make(map[string][]posting)}
+
+	for docIdx, doc := range docs {
+		weights := make(map[string]float64)
+
+		fields := map[string]string{
+			"title":    doc.title,
+			"subtitle": doc.subtitle,
+			"preview":  doc.preview,
+			"tags":     strings.Join(doc.tags, " "),
+			"body":     doc.body,
+		}
+
+		for field, text := range fields {
+			for _, term := range tokenize(text) {
+				weights[term] += searchFieldWeights[field]
+			}
+		}
+
+		for term, weight := range weights {
+			si.postings[term] = append(si.postings[term], posting{doc: docIdx, weight: weight})
+		}
+	}
+
+	si.terms = make([]string, 0, len(si.postings))
+	for term := range si.postings {
+		si.terms = append(si.terms, term)
+	}
+
+	sort.Strings(si.terms)
+
+	si.vectors = bodyVectors(docs)
+
+	return si
+}
+
+// match scores the documents containing every query word. A word matches any
+// indexed term it is a prefix of, so results appear while a word is typed.
+func (si *searchIndex) match(words []string) map[int]float64 {
+	var scores map[int]float64
+
+	for _, word := range words {
+		wordScores := make(map[int]float64)
+
+		start := sort.SearchStrings(si.terms, word)
+		for _, term := range si.terms[start:] {
+			if !strings.HasPrefix(term, word) {
+				break
+			}
+
+			postings := si.postings[term]
+			idf := math.Log(1 + float64(len(si.docs))/float64(len(postings)))
+
+			for _, p := range postings {
+				wordScores[p.doc] += p.weight * idf
+			}
+		}
+
+		if scores == nil {
+			scores = wordScores
+
+			continue
+		}
+
+		for doc := range scores {
+			score, ok := wordScores[doc]
+			if !ok {
+				delete(scores, doc)
+
+				continue
+			}
+
+			scores[doc] += score
+		}
+	}
+
+	return scores
+}
+
+// tokenize lowercases text and splits it into words of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// snippet picks a passage around the first match in the body, falling back to
+// the preview, and highlights every matching word in it.
+func snippet(doc searchDoc, words []string) []Fragment {
+	for _, text := range []string{doc.body, doc.preview, doc.subtitle} {
+		start, ok := firstMatch(text, words)
+		if !ok {
+			continue
+		}
+
+		return highlight(window(text, start), words)
+	}
+
+	return highlight(window(doc.preview, 0), words)
+}
+
+// firstMatch returns the byte offset of the first word in text that starts with
+// one of words.
+func firstMatch(text string, words []string) (int, bool) {
+	offset := 0
+
+	for _, field := range splitWords(text) {
+		if !field.separator && matchesAny(field.text, words) {
+			return offset, true
+		}
+
+		offset += len(field.text)
+	}
+
+	return 0, false
+}
+
+// window trims text to about snippetRadius characters either side of start,
+// cutting at spaces and marking the cuts with ellipses.
+func window(text string, start int) string {
+	from := max(start-snippetRadius, 0)
+	to := min(start+snippetRadius*2, len(text))
+
+	if from > 0 {
+		space := strings.IndexByte(text[from:start], ' ')
+		if space >= 0 {
+			from += space + 1
+		}
+	}
+
+	if to < len(text) {
+		space := strings.LastIndexByte(text[start:to], ' ')
+		if space > 0 {
+			to = start + space
+		}
+	}
+
+	out := strings.ToValidUTF8(text[from:to], "")
+
+	if from > 0 {
+		out = "…" + out
+	}
+
+	if to < len(text) {
+		out += "…"
+	}
+
+	return out
+}
+
+// highlight splits text into fragments, marking words that match the query.
+func highlight(text string, words []string) []Fragment {
+	var fragments []Fragment
+
+	for _, field := range splitWords(text) {
+		match := !field.separator && matchesAny(field.text, words)
+
+		last := len(fragments) - 1
+		if last >= 0 && !match && !fragments[last].Match {
+			fragments[last].Text += field.text
+
+			continue
+		}
+
+		fragments = append(fragments, Fragment{Text: field.text, Match: match})
+	}
+
+	return fragments
+}
+
+type wordField struct {
+	text      string
+	separator bool
+}
+
+// splitWords splits text into alternating runs of word and non-word characters,
+// using the same definition of a word as tokenize.
+func splitWords(text string) []wordField {
+	var (
+		fields   []wordField
+		start    int
+		prevWord bool
+	)
+
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsNumber(r)
+
+		if i > 0 && word != prevWord {
+			fields = append(fields, wordField{text: text[start:i], separator: !prevWord})
+			start = i
+		}
+
+		prevWord = word
+	}
+
+	if start < len(text) {
+		fields = append(fields, wordField{text: text[start:], separator: !prevWord})
+	}
+
+	return fields
+}
+
+func matchesAny(word string, words []string) bool {
+	lower := strings.ToLower(word)
+
+	for _, w := range words {
+		if strings.HasPrefix(lower, w) {
+			return true
+		}
+	}
+
+	return false
+}
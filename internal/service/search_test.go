@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"timterests/internal/service"
+)
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+
+	t.Run("finds documents of every public type", func(t *testing.T) {
+		results, err := service.Search(ctx, s, "test", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		types := map[string]bool{}
+		for _, r := range results {
+			types[r.DocType] = true
+		}
+
+		for _, docType := range []string{"articles", "projects", "reading-list"} {
+			if !types[docType] {
+				t.Errorf("expected a %s result, got %+v", docType, types)
+			}
+		}
+	})
+
+	t.Run("hides letters unless asked", func(t *testing.T) {
+		results, err := service.Search(ctx, s, "letter", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, r := range results {
+			if r.DocType == "letters" {
+				t.Errorf("expected no letters, got %q", r.Slug)
+			}
+		}
+
+		results, err = service.Search(ctx, s, "letter", true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(results) == 0 || results[0].Slug != "test-letter" {
+			t.Errorf("expected test-letter first when letters are included, got %+v", results)
+		}
+	})
+
+	t.Run("requires every word to match", func(t *testing.T) {
+		results, err := service.Search(ctx, s, "golang docker", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(results) != 1 || results[0].Slug != "test-project" {
+			t.Errorf("expected only test-project, got %+v", results)
+		}
+	})
+
+	t.Run("matches a word prefix while typing", func(t *testing.T) {
+		results, err := service.Search(ctx, s, "struct", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(results) != 1 || results[0].Slug != "test-book" {
+			t.Errorf("expected test-book from its 'Data Structures' tag, got %+v", results)
+		}
+	})
+
+	t.Run("ranks title matches above body matches", func(t *testing.T) {
+		results, err := service.Search(ctx, s, "article", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(results) == 0 || results[0].Slug != "test-article" {
+			t.Errorf("expected test-article first, got %+v", results)
+		}
+	})
+
+	t.Run("highlights matching words in the snippet", func(t *testing.T) {
+		results, err := service.Search(ctx, s, "body", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(results) == 0 {
+			t.Fatal("expected a result for 'body'")
+		}
+
+		var marked []string
+
+		for _, f := range results[0].Snippet {
+			if f.Match {
+				marked = append(marked, f.Text)
+			}
+		}
+
+		if len(marked) == 0 || !strings.EqualFold(marked[0], "body") {
+			t.Errorf("expected 'body' to be highlighted, got %v", marked)
+		}
+	})
+
+	t.Run("returns nothing for an empty query", func(t *testing.T) {
+		results, err := service.Search(ctx, s, "  ", true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(results) != 0 {
+			t.Errorf("expected no results, got %d", len(results))
+		}
+	})
+}
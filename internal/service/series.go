@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+	"timterests/internal/model"
+	"timterests/internal/storage"
+)
+
+// ListSeries returns every series with at least one published article, in
+// order of name. Each series lists its published parts in reading order.
+func ListSeries(ctx context.Context, s storage.Backend, now time.Time) ([]model.Series, error) {
+	articles, err := ListArticles(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	return groupSeries(Published(articles, now)), nil
+}
+
+// GetSeries returns the series whose slug is slug. A series with no published
+// parts does not exist as far as the public is concerned.
+func GetSeries(ctx context.Context, s storage.Backend, slug string, now time.Time) (*model.Series, error) {
+	all, err := ListSeries(ctx, s, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range all {
+		if all[i].Slug == slug {
+			return &all[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("series %q: %w", slug, fs.ErrNotExist)
+}
+
+// SeriesNames lists the names of every series in use, drafts included, for the
+// writer to offer.
+func SeriesNames(ctx context.Context, s storage.Backend) ([]string, error) {
+	articles, err := ListArticles(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	all := groupSeries(articles)
+	names := make([]string, 0, len(all))
+
+	for _, series := range all {
+		names = append(names, series.Name)
+	}
+
+	return names, nil
+}
+
+// GetSeriesNav places article within its series: which part it is, how many
+// there are, and its neighbours. Only published articles count, besides the
+// article itself, so a draft previews where it will land without showing
+// readers other unpublished parts. It returns nil for an article that is not
+// in a series.
+func GetSeriesNav(ctx context.Context, s storage.Backend, article *model.Article, now time.Time) (*model.SeriesNav, error) {
+	slug := model.SeriesSlug(article.Series)
+	if slug == "" {
+		return nil, nil
+	}
+
+	articles, err := ListArticles(ctx, s, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]model.Article, 0, len(articles))
+
+	for i := range articles {
+		if articles[i].ID == article.ID {
+			continue
+		}
+
+		if model.SeriesSlug(articles[i].Series) == slug && articles[i].IsPublished(now) {
+			parts = append(parts, articles[i])
+		}
+	}
+
+	parts = append(parts, *article)
+	sortSeries(parts)
+
+	nav := &model.SeriesNav{Name: article.Series, Slug: slug, Total: len(parts)}
+
+	for i := range parts {
+		if parts[i].ID != article.ID {
+			continue
+		}
+
+		nav.Part = i + 1
+
+		if i > 0 {
+			nav.Prev = &parts[i-1]
+		}
+
+		if i < len(parts)-1 {
+			nav.Next = &parts[i+1]
+		}
+	}
+
+	return nav, nil
+}
+
+// groupSeries gathers articles into their series. Names are matched by slug,
+// so "Go Tips" and "go tips" are one series, shown under the spelling its
+// first part uses.
+func groupSeries(articles []model.Article) []model.Series {
+	bySlug := make(map[string]*model.Series)
+
+	for _, article := range articles {
+		slug := model.SeriesSlug(article.Series)
+		if slug == "" {
+			continue
+		}
+
+		series, ok := bySlug[slug]
+		if !ok {
+			series = &model.Series{Name: article.Series, Slug: slug}
+			bySlug[slug] = series
+		}
+
+		series.Articles = append(series.Articles, article)
+	}
+
+	all := make([]model.Series, 0, len(bySlug))
+
+	for _, series := range bySlug {
+		sortSeries(series.Articles)
+		series.Name = series.Articles[0].Series
+		all = append(all, *series)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+
+	return all
+}
+
+// sortSeries puts the parts of a series in reading order: numbered parts first
+// by seriesOrder, then the rest oldest first.
+func sortSeries(parts []model.Article) {
+	sort.SliceStable(parts, func(i, j int) bool {
+		a, b := parts[i].SeriesOrder, parts[j].SeriesOrder
+
+		switch {
+		case a > 0 && b > 0 && a != b:
+			return a < b
+		case a > 0 && b <= 0:
+			return true
+		case a <= 0 && b > 0:
+			return false
+		default:
+			return parts[i].Date.Before(parts[j].Date)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+	"timterests/internal/model"
+	"timterests/internal/service"
+	"timterests/internal/storage"
+)
+
+// seedSeries writes a single-file article dated date with the given series
+// metadata.
+func seedSeries(t *testing.T, s storage.Backend, slug, date, meta string) {
+	t.Helper()
+
+	content := "---\ntitle: " + slug + "\ndate: \"" + date + "\"\ntags:\n  - tag1\n" + meta + "---\nBody\n"
+
+	err := storage.WriteSingleFile(context.Background(), s, "articles/"+slug+".yaml", []byte(content))
+	if err != nil {
+		t.Fatalf("failed to seed %s: %v", slug, err)
+	}
+}
+
+func TestSeries(t *testing.T) {
+	ctx := context.Background()
+	s := testSetup(t)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	seedSeries(t, s, "part-two", "2026-01-01", "series: Go Tips\nseriesOrder: 2\n")
+	seedSeries(t, s, "part-one", "2026-01-01", "series: Go Tips\nseriesOrder: 1\n")
+	seedSeries(t, s, "epilogue", "2026-03-01", "series: go tips\n")
+	seedSeries(t, s, "draft-part", "2026-01-01", "series: Go Tips\nseriesOrder: 3\nstatus: draft\n")
+
+	t.Run("lists published parts in reading order", func(t *testing.T) {
+		series, err := service.GetSeries(ctx, s, "go-tips", now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if series.Name != "Go Tips" {
+			t.Errorf("expected name 'Go Tips', got %q", series.Name)
+		}
+
+		want := []string{"part-one", "part-two", "epilogue"}
+		if len(series.Articles) != len(want) {
+			t.Fatalf("expected %d parts, got %d", len(want), len(series.Articles))
+		}
+
+		for i, id := range want {
+			if series.Articles[i].ID != id {
+				t.Errorf("part %d: expected %q, got %q", i+1, id, series.Articles[i].ID)
+			}
+		}
+	})
+
+	t.Run("an unknown series does not exist", func(t *testing.T) {
+		_, err := service.GetSeries(ctx, s, "no-such-series", now)
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected fs.ErrNotExist, got %v", err)
+		}
+	})
+
+	t.Run("places an article with its neighbours", func(t *testing.T) {
+		article, err := service.GetArticleBySlug(ctx, s, "part-two")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		nav, err := service.GetSeriesNav(ctx, s, article, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if nav.Part != 2 || nav.Total != 3 {
+			t.Errorf("expected part 2 of 3, got %d of %d", nav.Part, nav.Total)
+		}
+
+		if nav.Prev == nil || nav.Prev.ID != "part-one" {
+			t.Errorf("expected previous part 'part-one', got %v", nav.Prev)
+		}
+
+		if nav.Next == nil || nav.Next.ID != "epilogue" {
+			t.Errorf("expected next part 'epilogue', got %v", nav.Next)
+		}
+	})
+
+	t.Run("a draft previews its own place only", func(t *testing.T) {
+		article, err := service.GetArticleBySlug(ctx, s, "draft-part")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		nav, err := service.GetSeriesNav(ctx, s, article, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if nav.Part != 3 || nav.Total != 4 || nav.Next == nil || nav.Next.ID != "epilogue" {
+			t.Errorf("expected part 3 of 4 before 'epilogue', got %+v", nav)
+		}
+	})
+
+	t.Run("an article outside a series has no navigation", func(t *testing.T) {
+		nav, err := service.GetSeriesNav(ctx, s, &model.Article{}, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if nav != nil {
+			t.Errorf("expected nil, got %+v", nav)
+		}
+	})
+
+	t.Run("offers series names including drafts", func(t *testing.T) {
+		names, err := service.SeriesNames(ctx, s)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(names) != 1 || names[0] != "Go Tips" {
+			t.Errorf("expected [Go Tips], got %v", names)
+		}
+	})
+}
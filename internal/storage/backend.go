@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Backend is the object store documents and images live in. Services and
+// handlers depend on this rather than on a particular store, so a new backend
+// is one more implementation instead of a branch in every method.
+//
+// Keys are slash-separated and relative, e.g. "articles/my-post.yaml". A key
+// that does not exist is reported as an error wrapping fs.ErrNotExist.
+type Backend interface {
+	// Name identifies the backend in the admin dashboard and logs.
+	Name() string
+	// ListObjects lists the files directly under prefix, newest first.
+	// Nested "subdirectories" are not included.
+	ListObjects(ctx context.Context, prefix string) ([]Object, error)
+	// GetFile opens the object at key. The caller closes it.
+	GetFile(ctx context.Context, key string) (io.ReadCloser, error)
+	// WriteFile stores content at key, replacing anything already there.
+	WriteFile(ctx context.Context, key string, content []byte) error
+	// DeleteFile removes the object at key. A missing key is not an error.
+	DeleteFile(ctx context.Context, key string) error
+	// GetImage makes the image at key servable and returns its URL path.
+	GetImage(ctx context.Context, key string) (string, error)
+	// Check reports whether the backend is reachable.
+	Check(ctx context.Context) error
+}
+
+// Object describes a stored file.
+type Object struct {
+	Key          string
+	LastModified time.Time
+	Size         int64
+}
+
+// Backend names accepted by STORAGE_BACKEND.
+const (
+	BackendLocal  = "local"
+	BackendS3     = "s3"
+	BackendMemory = "memory"
+)
+
+// NewBackend builds the backend named by STORAGE_BACKEND. USE_S3=true is still
+// honoured when STORAGE_BACKEND is unset, so existing deployments keep working.
+func NewBackend(ctx context.Context) (Backend, error) {
+	kind := os.Getenv("STORAGE_BACKEND")
+	if kind == "" {
+		kind = BackendLocal
+
+		if os.Getenv("USE_S3") == "true" {
+			kind = BackendS3
+		}
+	}
+
+	if kind == BackendMemory {
+		return NewMemoryBackend(), nil
+	}
+
+	baseDir, err := storageDir()
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case BackendLocal:
+		return NewLocalBackend(baseDir), nil
+	case BackendS3:
+		return newS3BackendFromEnv(ctx, baseDir)
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", kind)
+	}
+}
+
+// storageDir returns the "storage" directory at the project root. The local
+// backend keeps documents there, and the S3 backend uses it as its read cache.
+func storageDir() (string, error) {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	baseDir := filepath.Join(projectRoot, "storage")
+
+	_, err = os.Stat(baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("storage directory not found at %s", baseDir)
+		}
+
+		return "", fmt.Errorf("failed to check storage directory: %w", err)
+	}
+
+	return baseDir, nil
+}
+
+func newS3BackendFromEnv(ctx context.Context, cacheDir string) (*S3Backend, error) {
+	bucketName := os.Getenv("AWS_BUCKET_NAME")
+	region := os.Getenv("AWS_REGION")
+
+	if bucketName == "" || region == "" {
+		return nil, errors.New("AWS_BUCKET_NAME or AWS_REGION is not set in the environment variables")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load SDK config, %w", err)
+	}
+
+	return NewS3Backend(s3.NewFromConfig(cfg), bucketName, cacheDir), nil
+}
+
+// HealthResult holds the structured health check response.
+type HealthResult struct {
+	Status    string            `json:"status"`
+	Timestamp string            `json:"ts"`
+	Checks    map[string]string `json:"checks"`
+}
+
+// Healthy returns true when overall status is "ok".
+func (h HealthResult) Healthy() bool {
+	return h.Status == "ok"
+}
+
+// Health checks storage connectivity.
+func Health(ctx context.Context, b Backend) HealthResult {
+	checks := make(map[string]string)
+	status := "ok"
+
+	checks["storage"] = "ok"
+
+	err := b.Check(ctx)
+	if err != nil {
+		checks["storage"] = fmt.Sprintf("error: %v", err)
+		status = "degraded"
+	}
+
+	return HealthResult{
+		Status:    status,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Checks:    checks,
+	}
+}
+
+// sortNewestFirst orders objects by modification time, most recent first. Every
+// backend lists in this order so callers see the same thing whichever is active.
+func sortNewestFirst(objects []Object) {
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].LastModified.After(objects[j].LastModified)
+	})
+}
+
+// imageWebPath is the URL an image is served from once it is available locally.
+func imageWebPath(key string) string {
+	return "/storage/" + key
+}
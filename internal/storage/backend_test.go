@@ -0,0 +1,139 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"timterests/internal/storage"
+)
+
+func TestNewBackend(t *testing.T) {
+	t.Run("memory backend selected by STORAGE_BACKEND", func(t *testing.T) {
+		t.Setenv("STORAGE_BACKEND", "memory")
+
+		b, err := storage.NewBackend(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if b.Name() != "Memory" {
+			t.Errorf("expected the memory backend, got %q", b.Name())
+		}
+	})
+
+	t.Run("rejects an unknown backend", func(t *testing.T) {
+		t.Setenv("STORAGE_BACKEND", "floppy")
+
+		_, err := storage.NewBackend(t.Context())
+		if err == nil {
+			t.Error("expected an error for an unknown backend")
+		}
+	})
+}
+
+func TestMemoryBackend(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("round-trips a written file", func(t *testing.T) {
+		t.Parallel()
+
+		m := storage.NewMemoryBackend()
+
+		err := m.WriteFile(ctx, "articles/post.yaml", []byte("title: Post"))
+		if err != nil {
+			t.Fatalf("WriteFile error: %v", err)
+		}
+
+		file, err := m.GetFile(ctx, "articles/post.yaml")
+		if err != nil {
+			t.Fatalf("GetFile error: %v", err)
+		}
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+
+		if string(content) != "title: Post" {
+			t.Errorf("expected 'title: Post', got %q", content)
+		}
+	})
+
+	// Must match the local backend, which only lists files directly in the
+	// directory, or services would see different documents per backend.
+	t.Run("lists only direct children of the prefix", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := storage.NewMemoryBackendFromFS(fstest.MapFS{
+			"articles/a.yaml":        {Data: []byte("title: A")},
+			"articles/nested/b.yaml": {Data: []byte("title: B")},
+			"projects/c.yaml":        {Data: []byte("title: C")},
+		})
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+
+		for _, prefix := range []string{"articles", "articles/"} {
+			objects, err := m.ListObjects(ctx, prefix)
+			if err != nil {
+				t.Fatalf("ListObjects error: %v", err)
+			}
+
+			if len(objects) != 1 || objects[0].Key != "articles/a.yaml" {
+				t.Errorf("prefix %q: expected only articles/a.yaml, got %+v", prefix, objects)
+			}
+		}
+	})
+
+	t.Run("reports a missing key as fs.ErrNotExist", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := storage.NewMemoryBackend().GetFile(ctx, "articles/missing.yaml")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected fs.ErrNotExist, got %v", err)
+		}
+	})
+
+	t.Run("deletes, tolerating a missing key", func(t *testing.T) {
+		t.Parallel()
+
+		m := storage.NewMemoryBackend()
+
+		err := m.WriteFile(ctx, "articles/gone.yaml", []byte("x"))
+		if err != nil {
+			t.Fatalf("WriteFile error: %v", err)
+		}
+
+		for range 2 {
+			err = m.DeleteFile(ctx, "articles/gone.yaml")
+			if err != nil {
+				t.Fatalf("DeleteFile error: %v", err)
+			}
+		}
+
+		_, err = m.GetFile(ctx, "articles/gone.yaml")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected the file to be gone, got %v", err)
+		}
+	})
+
+	t.Run("rejects keys the local backend would", func(t *testing.T) {
+		t.Parallel()
+
+		m := storage.NewMemoryBackend()
+
+		for _, key := range []string{"../escape.yaml", "/etc/passwd", ""} {
+			err := m.WriteFile(ctx, key, []byte("x"))
+			if err == nil {
+				t.Errorf("expected key %q to be rejected", key)
+			}
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"strings"
+
+	"timterests/internal/model"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Every heading gets an ID from its text, e.g. "Getting Started" becomes
+// "getting-started", with a numeric suffix for repeats. The ID only changes when
+// the heading's text does, so links to a section keep working across edits. An
+// author can pin one with an attribute: ## Getting Started {#start}.
+
+// headingsKey carries the headings found while parsing out to the caller.
+var headingsKey = parser.NewContextKey()
+
+// headingAnchors appends a permalink anchor to each heading and records the
+// headings, in document order, for a table of contents.
+type headingAnchors struct{}
+
+// Transform implements parser.ASTTransformer.
+func (headingAnchors) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var headings []model.Heading
+
+	source := reader.Source()
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+
+		value, found := heading.AttributeString("id")
+		id, isBytes := value.([]byte)
+
+		if !found || !isBytes || len(id) == 0 {
+			return ast.WalkSkipChildren, nil
+		}
+
+		headings = append(headings, model.Heading{
+			Level: heading.Level,
+			ID:    string(id),
+			Text:  strings.TrimSpace(nodeText(heading, source)),
+		})
+
+		anchor := ast.NewLink()
+		anchor.Destination = append([]byte("#"), id...)
+		anchor.Title = []byte("Link to this section")
+		// The "#" comes from CSS, so the anchor adds nothing to the text that
+		// search and reading time see.
+		anchor.SetAttributeString("class", []byte("heading-anchor"))
+		heading.AppendChild(heading, anchor)
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	pc.Set(headingsKey, headings)
+}
+
+// nodeText returns the plain text inside n, without any inline markup.
+func nodeText(n ast.Node, source []byte) string {
+	var b strings.Builder
+
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch t := child.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(source))
+
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(t.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return b.String()
+}
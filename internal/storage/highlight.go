@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+)
+
+// Fenced code blocks are highlighted on the server. The language comes from the
+// fence's info string, and attributes after it turn on line numbers, pick the
+// first number, or mark lines, e.g.
+//
+//	```go {linenos=true, linenostart=10, hl_lines=[2, "4-6"]}
+//
+// A block with no language, or one chroma does not know, stays plain.
+//
+// Tokens are marked with CSS classes rather than inline colours, so HighlightCSS
+// can give them a light and a dark palette that follow the dark-mode switch.
+
+const (
+	highlightLightStyle = "github"
+	highlightDarkStyle  = "github-dark"
+)
+
+// highlightExtension returns the goldmark extension that highlights code blocks.
+func highlightExtension() goldmark.Extender {
+	return highlighting.NewHighlighting(
+		highlighting.WithFormatOptions(
+			chromahtml.WithClasses(true),
+		),
+	)
+}
+
+// HighlightCSS returns the stylesheet for highlighted code: the light palette by
+// default and the dark one under the .dark class the switch puts on <html>.
+var HighlightCSS = sync.OnceValues(func() (string, error) {
+	var buf bytes.Buffer
+
+	err := writeHighlightCSS(&buf, highlightLightStyle, "")
+	if err != nil {
+		return "", err
+	}
+
+	err = writeHighlightCSS(&buf, highlightDarkStyle, ".dark ")
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+})
+
+// writeHighlightCSS writes the rules for one chroma style with every selector
+// prefixed by scope. Chroma's page background rule is left out; the site sets
+// its own.
+func writeHighlightCSS(buf *bytes.Buffer, style, scope string) error {
+	var css bytes.Buffer
+
+	err := chromahtml.New(chromahtml.WithClasses(true)).WriteCSS(&css, styles.Get(style))
+	if err != nil {
+		return fmt.Errorf("writing %s highlight styles: %w", style, err)
+	}
+
+	// Each rule is one line of the form "/* Name */ .selector { ... }".
+	lines := bufio.NewScanner(&css)
+	for lines.Scan() {
+		comment, rule, found := strings.Cut(lines.Text(), "*/ ")
+		if !found || strings.HasPrefix(rule, ".bg ") {
+			continue
+		}
+
+		buf.WriteString(comment + "*/ " + scope + rule + "\n")
+	}
+
+	return lines.Err()
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryPrefix is where previous revisions of documents are kept. Each
+// document gets its own directory, e.g. history/articles/my-post/, holding the
+// files of each revision as they were stored, so the layout works the same on
+// disk and in S3.
+const HistoryPrefix = "history/"
+
+// revisionIDLayout is a UTC timestamp that sorts as a string and is safe in a
+// filename. Nanoseconds keep two saves in the same second apart.
+const revisionIDLayout = "20060102T150405.000000000Z"
+
+// ErrRevisionNotFound is returned for a revision ID with no stored files.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Revision is a stored copy of a document as it was before a write replaced it.
+type Revision struct {
+	ID      string
+	Created time.Time
+	Size    int64
+}
+
+// WriteDocument saves a document's metadata and body, first keeping whatever
+// it replaces as a revision. An existing single-file document stays a single
+// file; anything else is written as a pair. Every save of a document should
+// come through here so nothing is overwritten without a copy.
+func WriteDocument(ctx context.Context, b Backend, yamlKey string, yamlBytes, mdBytes []byte) error {
+	single, err := IsSingleFile(ctx, b, yamlKey)
+	if err != nil {
+		return err
+	}
+
+	if single {
+		return writeRaw(ctx, b, yamlKey, nil, JoinFrontMatter(yamlBytes, mdBytes))
+	}
+
+	return writeRaw(ctx, b, yamlKey, yamlBytes, mdBytes)
+}
+
+// SaveRevision copies the stored files of a document into its history
+// directory, as they are, and returns the new revision's ID. A document that
+// does not exist yet has nothing to keep, so the ID is empty and the error nil.
+func SaveRevision(ctx context.Context, b Backend, yamlKey string) (string, error) {
+	id := time.Now().UTC().Format(revisionIDLayout)
+
+	saved, err := copyRaw(ctx, b, yamlKey, historyDir(yamlKey)+id+".yaml")
+	if err != nil || !saved {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// ListRevisions returns the stored revisions of a document, newest first.
+func ListRevisions(ctx context.Context, b Backend, yamlKey string) ([]Revision, error) {
+	objects, err := b.ListObjects(ctx, historyDir(yamlKey))
+	if err != nil {
+		return nil, fmt.Errorf("listing revisions of %s: %w", yamlKey, err)
+	}
+
+	byID := make(map[string]*Revision)
+
+	for _, obj := range objects {
+		name := path.Base(obj.Key)
+		id := strings.TrimSuffix(name, path.Ext(name))
+
+		created, err := time.Parse(revisionIDLayout, id)
+		if err != nil {
+			continue
+		}
+
+		rev, ok := byID[id]
+		if !ok {
+			rev = &Revision{ID: id, Created: created}
+			byID[id] = rev
+		}
+
+		rev.Size += obj.Size
+	}
+
+	revisions := make([]Revision, 0, len(byID))
+	for _, rev := range byID {
+		revisions = append(revisions, *rev)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].ID > revisions[j].ID
+	})
+
+	return revisions, nil
+}
+
+// GetRevision returns the metadata and body a document had at revision id.
+func GetRevision(ctx context.Context, b Backend, yamlKey, id string) ([]byte, []byte, error) {
+	_, err := time.Parse(revisionIDLayout, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %q", ErrRevisionNotFound, id)
+	}
+
+	yamlBytes, mdBytes, err := ReadDocument(ctx, b, historyDir(yamlKey)+id+".yaml")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if yamlBytes == nil && mdBytes == nil {
+		return nil, nil, fmt.Errorf("%w: %q", ErrRevisionNotFound, id)
+	}
+
+	return yamlBytes, mdBytes, nil
+}
+
+// RestoreRevision puts a document back to how it was at revision id, in the
+// layout it had then. The version being replaced becomes a revision itself, so
+// a restore can be undone.
+func RestoreRevision(ctx context.Context, b Backend, yamlKey, id string) error {
+	_, err := time.Parse(revisionIDLayout, id)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrRevisionNotFound, id)
+	}
+
+	yamlBytes, mdBytes, err := readRaw(ctx, b, historyDir(yamlKey)+id+".yaml")
+	if err != nil {
+		return err
+	}
+
+	if yamlBytes == nil && mdBytes == nil {
+		return fmt.Errorf("%w: %q", ErrRevisionNotFound, id)
+	}
+
+	return writeRaw(ctx, b, yamlKey, yamlBytes, mdBytes)
+}
+
+// copyRaw copies whichever files of the document at yamlKey exist to the same
+// names under toKey, reporting whether there was anything to copy.
+func copyRaw(ctx context.Context, b Backend, yamlKey, toKey string) (bool, error) {
+	yamlBytes, mdBytes, err := readRaw(ctx, b, yamlKey)
+	if err != nil {
+		return false, err
+	}
+
+	if yamlBytes == nil && mdBytes == nil {
+		return false, nil
+	}
+
+	for key, content := range map[string][]byte{toKey: yamlBytes, BodyKey(toKey): mdBytes} {
+		if content == nil {
+			continue
+		}
+
+		err = b.WriteFile(ctx, key, content)
+		if err != nil {
+			return false, fmt.Errorf("copying %s: %w", yamlKey, err)
+		}
+	}
+
+	return true, nil
+}
+
+// historyDir returns the directory holding the revisions of a document, e.g.
+// "articles/my-post.yaml" becomes "history/articles/my-post/".
+func historyDir(yamlKey string) string {
+	return HistoryPrefix + strings.TrimSuffix(yamlKey, ".yaml") + "/"
+}
@@ -0,0 +1,134 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"timterests/internal/storage"
+)
+
+func TestDocumentHistory(t *testing.T) {
+	ctx := context.Background()
+	key := "articles/post.yaml"
+
+	t.Run("a first write keeps no revision", func(t *testing.T) {
+		b := storage.NewMemoryBackend()
+
+		err := storage.WriteDocument(ctx, b, key, []byte("title: One\n"), []byte("one"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		revisions, err := storage.ListRevisions(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(revisions) != 0 {
+			t.Errorf("expected no revisions, got %d", len(revisions))
+		}
+	})
+
+	t.Run("each overwrite keeps the previous pair, newest first", func(t *testing.T) {
+		b := storage.NewMemoryBackend()
+
+		for _, body := range []string{"one", "two", "three"} {
+			err := storage.WriteDocument(ctx, b, key, []byte("title: "+body+"\n"), []byte(body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		revisions, err := storage.ListRevisions(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(revisions) != 2 {
+			t.Fatalf("expected 2 revisions, got %d", len(revisions))
+		}
+
+		meta, body, err := storage.GetRevision(ctx, b, key, revisions[0].ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(meta) != "title: two\n" || string(body) != "two" {
+			t.Errorf("expected the newest revision to be 'two', got %q / %q", meta, body)
+		}
+	})
+
+	t.Run("restore brings a revision back and keeps the replaced version", func(t *testing.T) {
+		b := storage.NewMemoryBackend()
+
+		for _, body := range []string{"one", "two"} {
+			err := storage.WriteDocument(ctx, b, key, []byte("title: "+body+"\n"), []byte(body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		revisions, err := storage.ListRevisions(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = storage.RestoreRevision(ctx, b, key, revisions[0].ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, body, err := storage.ReadDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(body) != "one" {
+			t.Errorf("expected the restored body 'one', got %q", body)
+		}
+
+		revisions, err = storage.ListRevisions(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(revisions) != 2 {
+			t.Errorf("expected the replaced version to be kept, got %d revisions", len(revisions))
+		}
+	})
+
+	t.Run("deleting keeps the last version", func(t *testing.T) {
+		b := storage.NewMemoryBackend()
+
+		err := storage.WriteDocument(ctx, b, key, []byte("title: Gone\n"), []byte("gone"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = storage.DeleteDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		revisions, err := storage.ListRevisions(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(revisions) != 1 {
+			t.Errorf("expected 1 revision after delete, got %d", len(revisions))
+		}
+	})
+
+	t.Run("unknown revisions are reported as not found", func(t *testing.T) {
+		b := storage.NewMemoryBackend()
+
+		for _, id := range []string{"20200101T000000.000000000Z", "../../secrets"} {
+			_, _, err := storage.GetRevision(ctx, b, key, id)
+			if !errors.Is(err, storage.ErrRevisionNotFound) {
+				t.Errorf("expected ErrRevisionNotFound for %q, got %v", id, err)
+			}
+		}
+	})
+}
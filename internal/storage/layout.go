@@ -0,0 +1,298 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"strings"
+)
+
+// A document is stored in one of two layouts:
+//
+//   - a pair: slug.yaml holds the metadata and slug.md the body;
+//   - a single file: slug.md starts with the metadata as YAML front matter
+//     between "---" lines, followed by the body.
+//
+// Either way a document is addressed by its metadata key, "type/slug.yaml",
+// even when no such file exists. The functions here resolve which layout is in
+// use, so nothing above storage needs to know.
+
+// frontMatterDelimiter opens and closes the front matter block.
+const frontMatterDelimiter = "---"
+
+// ErrNoFrontMatter is returned for a single-file document that does not open
+// with a front matter block.
+var ErrNoFrontMatter = errors.New("no YAML front matter between --- lines")
+
+// MetadataKey returns the key a document is addressed by for any of its files,
+// e.g. "articles/my-post.md" becomes "articles/my-post.yaml".
+func MetadataKey(key string) string {
+	base, found := strings.CutSuffix(key, ".md")
+	if found {
+		return base + ".yaml"
+	}
+
+	return key
+}
+
+// BodyKey returns the key of a document's Markdown file.
+func BodyKey(yamlKey string) string {
+	return strings.TrimSuffix(yamlKey, ".yaml") + ".md"
+}
+
+// SplitFrontMatter separates YAML front matter from the Markdown after it. ok
+// is false when content does not open with a front matter block.
+func SplitFrontMatter(content []byte) ([]byte, []byte, bool) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	rest, found := strings.CutPrefix(text, frontMatterDelimiter+"\n")
+	if !found {
+		return nil, nil, false
+	}
+
+	// The block may be empty, in which case the closing line comes first.
+	if strings.HasPrefix(rest, frontMatterDelimiter+"\n") || rest == frontMatterDelimiter {
+		return []byte{}, []byte(strings.TrimPrefix(rest[len(frontMatterDelimiter):], "\n")), true
+	}
+
+	end := strings.Index(rest, "\n"+frontMatterDelimiter+"\n")
+	if end < 0 {
+		if !strings.HasSuffix(rest, "\n"+frontMatterDelimiter) {
+			return nil, nil, false
+		}
+
+		return []byte(rest[:len(rest)-len(frontMatterDelimiter)]), []byte{}, true
+	}
+
+	meta := rest[:end+1]
+	body := rest[end+len(frontMatterDelimiter)+2:]
+
+	return []byte(meta), []byte(body), true
+}
+
+// JoinFrontMatter builds a single-file document from metadata and body.
+func JoinFrontMatter(meta, body []byte) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(meta)
+
+	if len(meta) > 0 && !bytes.HasSuffix(meta, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(body)
+
+	return buf.Bytes()
+}
+
+// ReadDocument returns the metadata and body of a document in either layout. A
+// missing half comes back nil rather than as an error, so a half-written pair
+// can still be read, restored or removed.
+func ReadDocument(ctx context.Context, b Backend, yamlKey string) ([]byte, []byte, error) {
+	yamlBytes, mdBytes, err := readRaw(ctx, b, yamlKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if yamlBytes == nil && mdBytes != nil {
+		meta, body, ok := SplitFrontMatter(mdBytes)
+		if ok {
+			return meta, body, nil
+		}
+	}
+
+	return yamlBytes, mdBytes, nil
+}
+
+// IsSingleFile reports whether the document at yamlKey is stored as one .md
+// file with front matter.
+func IsSingleFile(ctx context.Context, b Backend, yamlKey string) (bool, error) {
+	yamlBytes, mdBytes, err := readRaw(ctx, b, yamlKey)
+	if err != nil {
+		return false, err
+	}
+
+	return singleFile(yamlBytes, mdBytes), nil
+}
+
+// DocumentKeys lists the metadata keys of the complete documents under prefix,
+// in storage listing order. A pair counts once its .md exists; a .md on its own
+// counts when it carries front matter. Anything else cannot be rendered and is
+// skipped.
+func DocumentKeys(ctx context.Context, b Backend, prefix string) ([]string, error) {
+	files, err := b.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects with prefix %q: %w", prefix, err)
+	}
+
+	present := make(map[string]bool, len(files))
+	for _, obj := range files {
+		present[obj.Key] = true
+	}
+
+	keys := make([]string, 0, len(files)/2)
+
+	for _, obj := range files {
+		key := obj.Key
+
+		switch {
+		case key == prefix:
+			continue
+		case strings.HasSuffix(key, ".yaml"):
+			if !present[BodyKey(key)] {
+				log.Printf("DocumentKeys: skipping %s — no paired .md body file", key)
+
+				continue
+			}
+
+			keys = append(keys, key)
+		case strings.HasSuffix(key, ".md"):
+			yamlKey := MetadataKey(key)
+			if present[yamlKey] {
+				continue
+			}
+
+			single, err := IsSingleFile(ctx, b, yamlKey)
+			if err != nil {
+				return nil, err
+			}
+
+			if !single {
+				log.Printf("DocumentKeys: skipping %s — no .yaml and no front matter", key)
+
+				continue
+			}
+
+			keys = append(keys, yamlKey)
+		}
+	}
+
+	return keys, nil
+}
+
+// WriteSingleFile saves a document as one .md with front matter, replacing
+// whatever layout it had. The current version is kept as a revision.
+func WriteSingleFile(ctx context.Context, b Backend, yamlKey string, content []byte) error {
+	_, _, ok := SplitFrontMatter(content)
+	if !ok {
+		return fmt.Errorf("writing %s: %w", yamlKey, ErrNoFrontMatter)
+	}
+
+	return writeRaw(ctx, b, yamlKey, nil, content)
+}
+
+// ConvertDocument rewrites a document into the single-file layout, or into a
+// pair when toSingleFile is false. It reports whether anything changed; a
+// document already in the wanted layout is left alone.
+func ConvertDocument(ctx context.Context, b Backend, yamlKey string, toSingleFile bool) (bool, error) {
+	yamlBytes, mdBytes, err := readRaw(ctx, b, yamlKey)
+	if err != nil {
+		return false, err
+	}
+
+	if yamlBytes == nil && mdBytes == nil {
+		return false, fmt.Errorf("converting %s: %w", yamlKey, fs.ErrNotExist)
+	}
+
+	if singleFile(yamlBytes, mdBytes) == toSingleFile {
+		return false, nil
+	}
+
+	meta, body, err := ReadDocument(ctx, b, yamlKey)
+	if err != nil {
+		return false, err
+	}
+
+	if toSingleFile {
+		return true, writeRaw(ctx, b, yamlKey, nil, JoinFrontMatter(meta, body))
+	}
+
+	return true, writeRaw(ctx, b, yamlKey, meta, body)
+}
+
+// singleFile reports whether raw files make up a single-file document.
+func singleFile(yamlBytes, mdBytes []byte) bool {
+	if yamlBytes != nil || mdBytes == nil {
+		return false
+	}
+
+	_, _, ok := SplitFrontMatter(mdBytes)
+
+	return ok
+}
+
+// readRaw returns the stored .yaml and .md of a document exactly as they are,
+// nil for a file that does not exist.
+func readRaw(ctx context.Context, b Backend, yamlKey string) ([]byte, []byte, error) {
+	yamlBytes, err := readIfExists(ctx, b, yamlKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mdBytes, err := readIfExists(ctx, b, BodyKey(yamlKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return yamlBytes, mdBytes, nil
+}
+
+// writeRaw stores the files of a document exactly as given, first keeping the
+// current version as a revision. A nil yamlBytes means the single-file layout,
+// so any .yaml left from a pair is removed rather than left to shadow it.
+func writeRaw(ctx context.Context, b Backend, yamlKey string, yamlBytes, mdBytes []byte) error {
+	_, err := SaveRevision(ctx, b, yamlKey)
+	if err != nil {
+		return err
+	}
+
+	if yamlBytes != nil {
+		err = b.WriteFile(ctx, yamlKey, yamlBytes)
+		if err != nil {
+			return fmt.Errorf("writing %s: %w", yamlKey, err)
+		}
+	}
+
+	mdKey := BodyKey(yamlKey)
+
+	err = b.WriteFile(ctx, mdKey, mdBytes)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", mdKey, err)
+	}
+
+	if yamlBytes == nil {
+		err = b.DeleteFile(ctx, yamlKey)
+		if err != nil {
+			return fmt.Errorf("removing %s: %w", yamlKey, err)
+		}
+	}
+
+	return nil
+}
+
+// readIfExists reads key, returning nil content rather than an error when the
+// key does not exist.
+func readIfExists(ctx context.Context, b Backend, key string) ([]byte, error) {
+	file, err := b.GetFile(ctx, key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", key, err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", key, err)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,199 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"timterests/internal/storage"
+)
+
+const singleFileDoc = "---\ntitle: One File\ndate: 2026-01-01\n---\n# One File\n\nBody.\n"
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantMeta string
+		wantBody string
+		wantOK   bool
+	}{
+		{"front matter and body", singleFileDoc, "title: One File\ndate: 2026-01-01\n", "# One File\n\nBody.\n", true},
+		{"windows line endings", "---\r\ntitle: A\r\n---\r\nBody", "title: A\n", "Body", true},
+		{"empty block", "---\n---\nBody", "", "Body", true},
+		{"no body", "---\ntitle: A\n---", "title: A\n", "", true},
+		{"no front matter", "# Just Markdown\n", "", "", false},
+		{"unclosed block", "---\ntitle: A\nBody", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, body, ok := storage.SplitFrontMatter([]byte(tt.content))
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+
+			if string(meta) != tt.wantMeta || string(body) != tt.wantBody {
+				t.Errorf("expected %q / %q, got %q / %q", tt.wantMeta, tt.wantBody, meta, body)
+			}
+		})
+	}
+}
+
+func TestJoinFrontMatterRoundTrip(t *testing.T) {
+	meta, body, ok := storage.SplitFrontMatter(storage.JoinFrontMatter([]byte("title: A"), []byte("Body\n")))
+	if !ok {
+		t.Fatal("expected the joined document to have front matter")
+	}
+
+	if string(meta) != "title: A\n" || string(body) != "Body\n" {
+		t.Errorf("unexpected round trip: %q / %q", meta, body)
+	}
+}
+
+func TestSingleFileDocuments(t *testing.T) {
+	ctx := context.Background()
+	key := "articles/one-file.yaml"
+
+	seed := func(t *testing.T) storage.Backend {
+		t.Helper()
+
+		b := storage.NewMemoryBackend()
+
+		err := storage.WriteSingleFile(ctx, b, key, []byte(singleFileDoc))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+
+		return b
+	}
+
+	t.Run("is listed alongside pairs", func(t *testing.T) {
+		b := seed(t)
+
+		err := storage.WriteDocument(ctx, b, "articles/pair.yaml", []byte("title: Pair\n"), []byte("Pair"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = b.WriteFile(ctx, "articles/plain.md", []byte("# No front matter\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		keys, err := storage.DocumentKeys(ctx, b, "articles/")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		slices.Sort(keys)
+
+		want := []string{"articles/one-file.yaml", "articles/pair.yaml"}
+		if !slices.Equal(keys, want) {
+			t.Errorf("expected %v, got %v", want, keys)
+		}
+	})
+
+	t.Run("reads metadata and body from the front matter", func(t *testing.T) {
+		b := seed(t)
+
+		var doc struct {
+			Title string `yaml:"title"`
+		}
+
+		err := storage.GetPreparedFile(ctx, b, key, &doc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if doc.Title != "One File" {
+			t.Errorf("expected title 'One File', got %q", doc.Title)
+		}
+
+		body, err := storage.GetDocumentBodyRaw(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if body != "# One File\n\nBody.\n" {
+			t.Errorf("expected the body without front matter, got %q", body)
+		}
+	})
+
+	t.Run("keeps its layout when written", func(t *testing.T) {
+		b := seed(t)
+
+		err := storage.WriteDocument(ctx, b, key, []byte("title: Edited\n"), []byte("New body\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		single, err := storage.IsSingleFile(ctx, b, key)
+		if err != nil || !single {
+			t.Fatalf("expected the document to stay a single file (%v)", err)
+		}
+
+		meta, body, err := storage.ReadDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(meta) != "title: Edited\n" || string(body) != "New body\n" {
+			t.Errorf("unexpected content %q / %q", meta, body)
+		}
+	})
+
+	t.Run("rejects content without front matter", func(t *testing.T) {
+		err := storage.WriteSingleFile(ctx, storage.NewMemoryBackend(), key, []byte("# Plain\n"))
+		if !errors.Is(err, storage.ErrNoFrontMatter) {
+			t.Errorf("expected ErrNoFrontMatter, got %v", err)
+		}
+	})
+}
+
+func TestConvertDocument(t *testing.T) {
+	ctx := context.Background()
+	key := "articles/post.yaml"
+
+	b := storage.NewMemoryBackend()
+
+	err := storage.WriteDocument(ctx, b, key, []byte("title: Post\n"), []byte("Body\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	changed, err := storage.ConvertDocument(ctx, b, key, true)
+	if err != nil || !changed {
+		t.Fatalf("expected a conversion to a single file, got %v (%v)", changed, err)
+	}
+
+	_, err = b.GetFile(ctx, key)
+	if err == nil {
+		t.Error("expected the .yaml to be removed")
+	}
+
+	changed, err = storage.ConvertDocument(ctx, b, key, true)
+	if err != nil || changed {
+		t.Errorf("expected converting again to do nothing, got %v (%v)", changed, err)
+	}
+
+	changed, err = storage.ConvertDocument(ctx, b, key, false)
+	if err != nil || !changed {
+		t.Fatalf("expected a conversion back to a pair, got %v (%v)", changed, err)
+	}
+
+	meta, body, err := storage.ReadDocument(ctx, b, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(meta) != "title: Post\n" || string(body) != "Body\n" {
+		t.Errorf("expected the original content back, got %q / %q", meta, body)
+	}
+
+	revisions, err := storage.ListRevisions(ctx, b, key)
+	if err != nil || len(revisions) != 2 {
+		t.Errorf("expected each conversion to keep a revision, got %d (%v)", len(revisions), err)
+	}
+}
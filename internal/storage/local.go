@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalBackend stores objects as files under BaseDir.
+type LocalBackend struct {
+	BaseDir string
+}
+
+// NewLocalBackend returns a backend rooted at baseDir.
+func NewLocalBackend(baseDir string) *LocalBackend {
+	return &LocalBackend{BaseDir: baseDir}
+}
+
+// Name implements Backend.
+func (l *LocalBackend) Name() string {
+	return "Local"
+}
+
+// ListObjects lists objects previously pulled from S3 or stored manually.
+// This doesn't recursively retrieve directories, only the files from the passed in directory.
+// Subdirectories are ignored.
+func (l *LocalBackend) ListObjects(_ context.Context, prefix string) ([]Object, error) {
+	fullPath, err := LocalPath(l.BaseDir, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("getting local path: %w", err)
+	}
+
+	// A directory nothing has been written to yet simply holds no objects;
+	// listing it must not create it.
+	entries, err := os.ReadDir(fullPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Object{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading local storage directory: %w", err)
+	}
+
+	objects := make([]Object, 0, len(entries))
+	for _, entry := range entries {
+		// Use IsDir() to filter out directories
+		if entry.IsDir() {
+			continue
+		}
+
+		// File info for size and mod time
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("getting file info: %w", err)
+		}
+
+		keyPath, err := LocalPath(prefix, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("getting local path: %w", err)
+		}
+
+		objects = append(objects, Object{
+			Key:          filepath.ToSlash(keyPath),
+			LastModified: fileInfo.ModTime(),
+			Size:         fileInfo.Size(),
+		})
+	}
+
+	sortNewestFirst(objects)
+
+	return objects, nil
+}
+
+// GetFile implements Backend.
+func (l *LocalBackend) GetFile(_ context.Context, key string) (io.ReadCloser, error) {
+	localPath, err := LocalPath(l.BaseDir, key)
+	if err != nil {
+		return nil, fmt.Errorf("getting local path: %w", err)
+	}
+
+	// #nosec G304 -- localPath is validated by LocalPath to prevent path traversal
+	file, err := os.Open(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return file, nil
+}
+
+// WriteFile implements Backend.
+func (l *LocalBackend) WriteFile(_ context.Context, key string, content []byte) error {
+	path, err := LocalPath(l.BaseDir, key)
+	if err != nil {
+		return fmt.Errorf("getting local path: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0750)
+	if err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	err = os.WriteFile(path, content, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// DeleteFile implements Backend.
+func (l *LocalBackend) DeleteFile(_ context.Context, key string) error {
+	path, err := LocalPath(l.BaseDir, key)
+	if err != nil {
+		return fmt.Errorf("getting local path: %w", err)
+	}
+
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// GetImage returns the URL path the image is served from. Local files are
+// already servable, so nothing is copied.
+func (l *LocalBackend) GetImage(_ context.Context, key string) (string, error) {
+	_, err := LocalPath(l.BaseDir, key)
+	if err != nil {
+		return "", err
+	}
+
+	return imageWebPath(key), nil
+}
+
+// Check implements Backend.
+func (l *LocalBackend) Check(_ context.Context) error {
+	_, err := os.Stat(l.BaseDir)
+	if os.IsNotExist(err) {
+		return errors.New("local storage directory missing")
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"timterests/internal/model"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// markdownExtensions are the optional goldmark extensions a site can turn on by
+// name in MARKDOWN_EXTENSIONS. Highlighting and heading anchors are always on.
+var markdownExtensions = map[string]goldmark.Extender{
+	"table":          extension.Table,
+	"tasklist":       extension.TaskList,
+	"strikethrough":  extension.Strikethrough,
+	"linkify":        extension.Linkify,
+	"footnote":       extension.Footnote,
+	"definitionlist": extension.DefinitionList,
+	"typographer":    extension.Typographer,
+	// gfm is shorthand for table, tasklist, strikethrough and linkify.
+	"gfm": extension.GFM,
+}
+
+// nodeClasses are the CSS classes rendered elements get, by node kind. A class
+// written in the Markdown itself, e.g. ## Heading {.wide}, is kept alongside.
+var nodeClasses = map[ast.NodeKind]string{
+	ast.KindParagraph: "content-text",
+	ast.KindListItem:  "content-text",
+}
+
+// headingClasses are the CSS classes headings get, by level.
+var headingClasses = map[int]string{
+	1: "category-title",
+	2: "category-subtitle",
+}
+
+// MarkdownRenderer converts Markdown to the site's HTML. Raw HTML in the
+// Markdown is passed through and then sanitized with the renderer's policy.
+type MarkdownRenderer struct {
+	md     goldmark.Markdown
+	policy SanitizePolicy
+}
+
+// NewMarkdownRenderer builds a renderer that sanitizes with policy and has the
+// named optional extensions, e.g. "table" or "footnote". An unknown name is an
+// error.
+func NewMarkdownRenderer(policy SanitizePolicy, extensions ...string) (*MarkdownRenderer, error) {
+	extenders := []goldmark.Extender{highlightExtension()}
+
+	for _, name := range extensions {
+		ext, ok := markdownExtensions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown markdown extension %q: want one of %s", name, strings.Join(MarkdownExtensionNames(), ", "))
+		}
+
+		extenders = append(extenders, ext)
+	}
+
+	md := goldmark.New(
+		goldmark.WithExtensions(extenders...),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithAttribute(),
+			parser.WithInlineParsers(
+				util.Prioritized(wikiLinkParser{}, 199),
+			),
+			parser.WithASTTransformers(
+				util.Prioritized(wikiLinks{}, 50),
+				util.Prioritized(headingAnchors{}, 100),
+				util.Prioritized(classNames{}, 200),
+			),
+		),
+		goldmark.WithRendererOptions(
+			html.WithHardWraps(),
+			html.WithUnsafe(),
+			renderer.WithNodeRenderers(
+				util.Prioritized(wikiLinkRenderer{}, 500),
+			),
+		),
+	)
+
+	return &MarkdownRenderer{md: md, policy: policy}, nil
+}
+
+// Render converts content to sanitized HTML and returns the headings it found,
+// in order, for a table of contents. name identifies the document when logging
+// what the sanitizer stripped. Wiki links are left unresolved; see RenderLinked.
+func (r *MarkdownRenderer) Render(name string, content []byte) (string, []model.Heading, error) {
+	return r.RenderLinked(name, content, nil)
+}
+
+// RenderLinked is Render with wiki links resolved by resolve.
+func (r *MarkdownRenderer) RenderLinked(name string, content []byte, resolve WikiResolver) (string, []model.Heading, error) {
+	raw, headings, err := r.convert(content, resolve)
+	if err != nil {
+		return "", nil, err
+	}
+
+	body, stripped := r.policy.Sanitize(raw)
+	if len(stripped) > 0 {
+		log.Printf("sanitize: stripped from %s: %s", name, strings.Join(stripped, ", "))
+	}
+
+	return body, headings, nil
+}
+
+// convert runs goldmark, before any sanitizing.
+func (r *MarkdownRenderer) convert(content []byte, resolve WikiResolver) (string, []model.Heading, error) {
+	var buf bytes.Buffer
+
+	pc := parser.NewContext()
+	if resolve != nil {
+		pc.Set(wikiResolverKey, resolve)
+	}
+
+	err := r.md.Convert(content, &buf, parser.WithContext(pc))
+	if err != nil {
+		log.Printf("failed to convert markdown to HTML: %v", err)
+
+		return "", nil, fmt.Errorf("conversion error: %w", err)
+	}
+
+	headings, _ := pc.Get(headingsKey).([]model.Heading)
+
+	return buf.String(), headings, nil
+}
+
+// MarkdownExtensionNames lists the extension names NewMarkdownRenderer accepts.
+func MarkdownExtensionNames() []string {
+	names := make([]string, 0, len(markdownExtensions))
+	for name := range markdownExtensions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// siteRenderer is the renderer configured by MARKDOWN_EXTENSIONS, a comma
+// separated list of extension names. Unknown names are logged and skipped so a
+// typo does not take the site down.
+var siteRenderer = sync.OnceValue(func() *MarkdownRenderer {
+	var names []string
+
+	for name := range strings.SplitSeq(os.Getenv("MARKDOWN_EXTENSIONS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		if !slices.Contains(MarkdownExtensionNames(), name) {
+			log.Printf("ignoring MARKDOWN_EXTENSIONS entry %q: want one of %s", name, strings.Join(MarkdownExtensionNames(), ", "))
+
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	r, err := NewMarkdownRenderer(SitePolicy(), names...)
+	if err != nil {
+		// Every name was checked above, so this cannot happen.
+		panic(err)
+	}
+
+	return r
+})
+
+// MarkdownToHTML converts raw markdown bytes to styled, sanitized HTML, with
+// fenced code blocks syntax-highlighted and an anchor on every heading.
+func MarkdownToHTML(content []byte) (string, error) {
+	body, _, err := RenderMarkdown("markdown", content)
+
+	return body, err
+}
+
+// RenderMarkdown converts markdown like MarkdownToHTML and also returns the
+// headings it found, in order, for a table of contents. name identifies the
+// document in the sanitizer's log.
+func RenderMarkdown(name string, content []byte) (string, []model.Heading, error) {
+	return siteRenderer().Render(name, content)
+}
+
+// StrippedFromMarkdown reports what the sanitizer will strip from content when
+// it is shown, so an author can be told at upload rather than find out later.
+func StrippedFromMarkdown(content []byte) ([]string, error) {
+	r := siteRenderer()
+
+	raw, _, err := r.convert(content, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	_, stripped := r.policy.Sanitize(raw)
+
+	return stripped, nil
+}
+
+// classNames gives elements their CSS classes from nodeClasses and
+// headingClasses.
+type classNames struct{}
+
+// Transform implements parser.ASTTransformer.
+func (classNames) Transform(doc *ast.Document, _ text.Reader, _ parser.Context) {
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		class := nodeClasses[n.Kind()]
+
+		heading, ok := n.(*ast.Heading)
+		if ok {
+			class = headingClasses[heading.Level]
+		}
+
+		if class != "" {
+			addClass(n, class)
+		}
+
+		return ast.WalkContinue, nil
+	})
+}
+
+// addClass adds class to n ahead of any class it already has.
+func addClass(n ast.Node, class string) {
+	existing, found := n.AttributeString("class")
+	if found {
+		b, ok := existing.([]byte)
+		if ok && len(b) > 0 {
+			class += " " + string(b)
+		}
+	}
+
+	n.SetAttributeString("class", []byte(class))
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryBackend keeps objects in a map. Nothing touches disk, so tests can write
+// and delete freely, and a throwaway copy of the fixtures is one call away.
+type MemoryBackend struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	content  []byte
+	modified time.Time
+}
+
+// NewMemoryBackend returns an empty in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{objects: make(map[string]memoryObject)}
+}
+
+// NewMemoryBackendFromFS returns an in-memory backend holding a copy of every
+// file in fsys, keyed by its path.
+func NewMemoryBackendFromFS(fsys fs.FS) (*MemoryBackend, error) {
+	m := NewMemoryBackend()
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("getting file info for %s: %w", name, err)
+		}
+
+		m.objects[name] = memoryObject{content: content, modified: info.ModTime()}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("seeding memory backend: %w", err)
+	}
+
+	return m, nil
+}
+
+// Name implements Backend.
+func (m *MemoryBackend) Name() string {
+	return "Memory"
+}
+
+// ListObjects implements Backend.
+func (m *MemoryBackend) ListObjects(_ context.Context, prefix string) ([]Object, error) {
+	dir := strings.TrimSuffix(prefix, "/")
+	if dir != "" {
+		dir += "/"
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var objects []Object
+
+	for key, obj := range m.objects {
+		rest, ok := strings.CutPrefix(key, dir)
+		if !ok || strings.Contains(rest, "/") {
+			continue
+		}
+
+		objects = append(objects, Object{
+			Key:          key,
+			LastModified: obj.modified,
+			Size:         int64(len(obj.content)),
+		})
+	}
+
+	sortNewestFirst(objects)
+
+	return objects, nil
+}
+
+// GetFile implements Backend.
+func (m *MemoryBackend) GetFile(_ context.Context, key string) (io.ReadCloser, error) {
+	key, err := memoryKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	obj, ok := m.objects[key]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("failed to open file %s: %w", key, fs.ErrNotExist)
+	}
+
+	return io.NopCloser(bytes.NewReader(obj.content)), nil
+}
+
+// WriteFile implements Backend.
+func (m *MemoryBackend) WriteFile(_ context.Context, key string, content []byte) error {
+	key, err := memoryKey(key)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.objects[key] = memoryObject{content: bytes.Clone(content), modified: time.Now()}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// DeleteFile implements Backend.
+func (m *MemoryBackend) DeleteFile(_ context.Context, key string) error {
+	key, err := memoryKey(key)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	delete(m.objects, key)
+	m.mu.Unlock()
+
+	return nil
+}
+
+// GetImage implements Backend.
+func (m *MemoryBackend) GetImage(_ context.Context, key string) (string, error) {
+	key, err := memoryKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return imageWebPath(key), nil
+}
+
+// Check implements Backend. Memory is always reachable.
+func (m *MemoryBackend) Check(_ context.Context) error {
+	return nil
+}
+
+// memoryKey applies the same rules LocalPath does, so a key the local backend
+// would reject is not quietly accepted in tests.
+func memoryKey(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." {
+		return "", fmt.Errorf("invalid filepath: %s", key)
+	}
+
+	return path.Clean(key), nil
+}
@@ -1,9 +1,6 @@
 package storage
 
 import (
-	"bytes"
-	"fmt"
-	"log"
 	"path"
 	"reflect"
 	"regexp"
@@ -11,37 +8,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/yuin/goldmark"
-	"github.com/yuin/goldmark/renderer/html"
 )
 
-// MarkdownToHTML converts raw markdown bytes to styled HTML.
-func MarkdownToHTML(content []byte) (string, error) {
-	var buf bytes.Buffer
-
-	md := goldmark.New(
-		goldmark.WithRendererOptions(
-			html.WithHardWraps(),
-		),
-	)
-
-	err := md.Convert(content, &buf)
-	if err != nil {
-		log.Printf("failed to convert markdown to HTML: %v", err)
-
-		return "", fmt.Errorf("conversion error: %w", err)
-	}
-
-	body := buf.String()
-	body = strings.ReplaceAll(body, "<p>", `<p class="content-text">`)
-	body = strings.ReplaceAll(body, "<h1>", `<h1 class="category-title">`)
-	body = strings.ReplaceAll(body, "<h2>", `<h2 class="category-subtitle">`)
-	body = strings.ReplaceAll(body, "<li>", `<li class="content-text">`)
-
-	return body, nil
-}
-
 // GetTags extracts tags from a struct value.
 func GetTags(v reflect.Value, tags []string) []string {
 	field := v.FieldByName("Tags")
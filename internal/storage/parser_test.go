@@ -35,7 +35,7 @@ func TestMarkdownToHTML(t *testing.T) {
 						<li class="content-text">Item 1</li>
 						<li class="content-text">Item 2</li>
 						</ul>
-						<h2 class="category-subtitle">Subtitle</h2>
+						<h2 id="subtitle" class="category-subtitle">Subtitle<a href="#subtitle" title="Link to this section" class="heading-anchor"></a></h2>
 						<p class="content-text"><a href="http://example.com">Link</a></p>`
 
 		// Normalize whitespace for comparison
@@ -67,6 +67,173 @@ func TestMarkdownToHTML(t *testing.T) {
 	})
 }
 
+func TestMarkdownToHTMLCodeBlocks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("highlights a fenced block by its language", func(t *testing.T) {
+		t.Parallel()
+
+		html, err := storage.MarkdownToHTML([]byte("```go\nfunc main() {}\n```"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !strings.Contains(html, `<pre class="chroma">`) || !strings.Contains(html, `<span class="kd">func</span>`) {
+			t.Errorf("expected highlighted Go, got %s", html)
+		}
+
+		if strings.Contains(html, "style=") {
+			t.Errorf("expected classes rather than inline styles, got %s", html)
+		}
+	})
+
+	t.Run("adds line numbers and marks highlighted lines", func(t *testing.T) {
+		t.Parallel()
+
+		input := []byte("```go {linenos=true, linenostart=7, hl_lines=[2]}\na := 1\nb := 2\nc := 3\n```")
+
+		html, err := storage.MarkdownToHTML(input)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !strings.Contains(html, `<span class="ln">7</span>`) {
+			t.Errorf("expected line numbers starting at 7, got %s", html)
+		}
+
+		if strings.Count(html, `class="line hl"`) != 1 || !strings.Contains(html, `<span class="line hl"><span class="ln">8</span>`) {
+			t.Errorf("expected only the second line highlighted, got %s", html)
+		}
+	})
+
+	t.Run("leaves a block without a language plain", func(t *testing.T) {
+		t.Parallel()
+
+		html, err := storage.MarkdownToHTML([]byte("```\n<p>not markup</p>\n```"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !strings.Contains(html, "<pre><code>&lt;p&gt;not markup&lt;/p&gt;") {
+			t.Errorf("expected an escaped plain block, got %s", html)
+		}
+	})
+}
+
+func TestRenderMarkdownHeadings(t *testing.T) {
+	t.Parallel()
+
+	input := []byte("# Title\n\n## Getting *Started*\n\ntext\n\n### Details {#pinned}\n\n## Getting Started\n")
+
+	html, headings, err := storage.RenderMarkdown("test", input)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []model.Heading{
+		{Level: 1, ID: "title", Text: "Title"},
+		{Level: 2, ID: "getting-started", Text: "Getting Started"},
+		{Level: 3, ID: "pinned", Text: "Details"},
+		{Level: 2, ID: "getting-started-1", Text: "Getting Started"},
+	}
+
+	if !reflect.DeepEqual(headings, want) {
+		t.Errorf("expected headings %+v, got %+v", want, headings)
+	}
+
+	if !strings.Contains(html, `<h3 id="pinned">Details<a href="#pinned" title="Link to this section" class="heading-anchor"></a></h3>`) {
+		t.Errorf("expected an anchored heading with the pinned ID, got %s", html)
+	}
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("classes are set on nodes, not by rewriting the output", func(t *testing.T) {
+		t.Parallel()
+
+		input := []byte("# Title {.wide}\n\nSee `<p>` and <p>raw</p>.\n\n```\n<p>\n<li>\n```\n")
+
+		html, err := storage.MarkdownToHTML(input)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !strings.Contains(html, `<h1 class="category-title wide" id="title">`) {
+			t.Errorf("expected the heading class to sit beside the authored one, got %s", html)
+		}
+
+		if !strings.Contains(html, "<code>&lt;p&gt;</code>") || !strings.Contains(html, "<pre><code>&lt;p&gt;\n&lt;li&gt;\n</code></pre>") {
+			t.Errorf("expected code to be left alone, got %s", html)
+		}
+
+		if strings.Count(html, `class="content-text"`) != 1 {
+			t.Errorf("expected exactly one classed paragraph, got %s", html)
+		}
+	})
+
+	t.Run("optional extensions are off by default", func(t *testing.T) {
+		t.Parallel()
+
+		html, err := storage.MarkdownToHTML([]byte("~~gone~~"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if strings.Contains(html, "<del>") {
+			t.Errorf("expected no strikethrough without the extension, got %s", html)
+		}
+	})
+
+	t.Run("renders with the named extensions", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := storage.NewMarkdownRenderer(storage.SanitizePolicy{}, "table", "tasklist", "strikethrough", "footnote", "definitionlist", "typographer")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		input := []byte("| a |\n|---|\n| 1 |\n\n- [x] done\n\n~~gone~~ \"quoted\"[^1]\n\nTerm\n: Definition\n\n[^1]: Note.\n")
+
+		html, _, err := r.Render("test", input)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		for _, want := range []string{"<table>", `type="checkbox"`, "<del>gone</del>", "“quoted”", `class="footnote-ref"`, "<dt>Term</dt>"} {
+			if !strings.Contains(html, want) {
+				t.Errorf("expected %q in %s", want, html)
+			}
+		}
+	})
+
+	t.Run("rejects an unknown extension", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := storage.NewMarkdownRenderer(storage.SanitizePolicy{}, "tables")
+		if err == nil {
+			t.Error("expected an error for an unknown extension")
+		}
+	})
+}
+
+func TestHighlightCSS(t *testing.T) {
+	t.Parallel()
+
+	css, err := storage.HighlightCSS()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.Contains(css, ".chroma .kd {") || !strings.Contains(css, ".dark .chroma .kd {") {
+		t.Errorf("expected light and dark rules for keywords, got %s", css)
+	}
+
+	if strings.Contains(css, ".bg {") {
+		t.Error("expected chroma's page background rule to be dropped")
+	}
+}
+
 func TestGetTags(t *testing.T) {
 	t.Parallel()
 
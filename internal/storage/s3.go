@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Backend stores objects in an S3 bucket. Reads are pulled down into a local
+// cache directory first, which is also where /storage/ serves images from.
+type S3Backend struct {
+	Client     *s3.Client
+	BucketName string
+	cache      *LocalBackend
+}
+
+// NewS3Backend returns a backend for bucketName that caches reads in cacheDir.
+func NewS3Backend(client *s3.Client, bucketName, cacheDir string) *S3Backend {
+	return &S3Backend{
+		Client:     client,
+		BucketName: bucketName,
+		cache:      NewLocalBackend(cacheDir),
+	}
+}
+
+// Name implements Backend.
+func (b *S3Backend) Name() string {
+	return "S3"
+}
+
+// ListObjects lists the objects directly under prefix. S3 has no directories,
+// so a delimiter is what keeps nested keys out, matching the local backend.
+func (b *S3Backend) ListObjects(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:    aws.String(b.BucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}
+
+	objectPaginator := s3.NewListObjectsV2Paginator(b.Client, input)
+	for objectPaginator.HasMorePages() {
+		output, err := objectPaginator.NextPage(ctx)
+		if err != nil {
+			var noBucket *types.NoSuchBucket
+			if errors.As(err, &noBucket) {
+				log.Printf("Bucket %s does not exist.\n", b.BucketName)
+
+				return nil, noBucket
+			}
+
+			return nil, fmt.Errorf("listing S3 objects: %w", err)
+		}
+
+		for _, obj := range output.Contents {
+			objects = append(objects, Object{
+				Key:          aws.ToString(obj.Key),
+				LastModified: aws.ToTime(obj.LastModified),
+				Size:         aws.ToInt64(obj.Size),
+			})
+		}
+	}
+
+	sortNewestFirst(objects)
+
+	return objects, nil
+}
+
+// GetFile downloads the object into the cache and opens the cached copy.
+func (b *S3Backend) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	err := b.download(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.cache.GetFile(ctx, key)
+}
+
+// WriteFile stores content in the bucket. The cached copy is written too,
+// otherwise the new file would stay invisible until something else pulled it
+// down.
+func (b *S3Backend) WriteFile(ctx context.Context, key string, content []byte) error {
+	err := b.cache.WriteFile(ctx, key, content)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(b.BucketName),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(content),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s to S3: %w", key, err)
+	}
+
+	return nil
+}
+
+// DeleteFile removes the object from the bucket and the cache, otherwise a
+// deleted document keeps being served from disk.
+func (b *S3Backend) DeleteFile(ctx context.Context, key string) error {
+	_, err := b.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.BucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete %s from S3: %w", key, err)
+	}
+
+	return b.cache.DeleteFile(ctx, key)
+}
+
+// GetImage downloads the image into the cache so /storage/ can serve it.
+func (b *S3Backend) GetImage(ctx context.Context, key string) (string, error) {
+	_, err := LocalPath(b.cache.BaseDir, key)
+	if err != nil {
+		return "", err
+	}
+
+	err = b.download(ctx, key)
+	if err != nil {
+		log.Printf("Failed to download image: %v", err)
+
+		return "", err
+	}
+
+	return imageWebPath(key), nil
+}
+
+// Check implements Backend.
+func (b *S3Backend) Check(ctx context.Context) error {
+	_, err := b.Client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		log.Printf("health: S3 connection down: %v", err)
+
+		return fmt.Errorf("S3 connection down: %w", err)
+	}
+
+	return nil
+}
+
+// download copies an object from S3 into the local cache.
+func (b *S3Backend) download(ctx context.Context, key string) error {
+	fileName, err := LocalPath(b.cache.BaseDir, key)
+	if err != nil {
+		return fmt.Errorf("getting local path: %w", err)
+	}
+
+	result, err := b.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.BucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noKey *types.NoSuchKey
+		if errors.As(err, &noKey) {
+			log.Printf("Can't get object %s from bucket %s. No such key exists.\n", key, b.BucketName)
+
+			return fmt.Errorf("%s: %w", key, fs.ErrNotExist)
+		}
+
+		log.Printf("Couldn't get object %v:%v. Here's why: %v\n", b.BucketName, key, err)
+
+		return fmt.Errorf("getting %s from S3: %w", key, err)
+	}
+
+	defer func() {
+		err := result.Body.Close()
+		if err != nil {
+			log.Printf("Failed to close S3 object body: %v", err)
+		}
+	}()
+
+	err = os.MkdirAll(filepath.Dir(fileName), 0750)
+	if err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// #nosec G304 -- fileName is validated by LocalPath to prevent path traversal
+	file, err := os.Create(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, result.Body)
+	if err != nil {
+		return fmt.Errorf("failed to write to file: %w", err)
+	}
+
+	return nil
+}
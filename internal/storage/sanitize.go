@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Rendered bodies are sanitized against an allowlist before they reach a page,
+// since Markdown may carry raw HTML. Anything not listed here is removed:
+// elements that run code or load other content go with everything inside them,
+// other unknown elements are unwrapped so their text survives, and attributes
+// not listed for an element are dropped. URLs must be relative or use http,
+// https or mailto.
+
+// SanitizePolicy is the configurable part of the allowlist.
+type SanitizePolicy struct {
+	// EmbedHosts are the hosts an <iframe> may load from over https, e.g.
+	// "www.youtube-nocookie.com". With none, every iframe is stripped.
+	EmbedHosts []string
+}
+
+// globalAttributes are allowed on every element.
+var globalAttributes = []string{"id", "class", "title", "lang", "dir", "role"}
+
+// allowedElements maps each allowed element to the attributes it may carry on
+// top of globalAttributes.
+var allowedElements = map[string][]string{
+	"a": {"href", "rel"}, "abbr": nil, "b": nil, "blockquote": {"cite"}, "br": nil,
+	"caption": nil, "cite": nil, "code": nil, "col": {"span"}, "colgroup": {"span"},
+	"dd": nil, "del": nil, "details": {"open"}, "dfn": nil, "div": nil, "dl": nil,
+	"dt": nil, "em": nil, "figcaption": nil, "figure": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"hr": nil, "i": nil, "img": {"src", "alt", "width", "height", "loading"},
+	"input": {"type", "checked", "disabled"}, "ins": nil, "kbd": nil, "li": {"value"},
+	"mark": nil, "ol": {"start", "type", "reversed"}, "p": nil, "pre": nil, "q": {"cite"},
+	"s": nil, "samp": nil, "small": nil, "span": nil, "strong": nil, "sub": nil,
+	"summary": nil, "sup": nil, "table": nil, "tbody": nil,
+	"td": {"colspan", "rowspan", "style"}, "tfoot": nil,
+	"th": {"colspan", "rowspan", "scope", "style"}, "thead": nil, "tr": nil,
+	"u": nil, "ul": nil, "var": nil,
+	"audio":  {"src", "controls", "loop", "muted", "preload"},
+	"video":  {"src", "controls", "loop", "muted", "preload", "poster", "width", "height"},
+	"source": {"src", "type"},
+	"iframe": {"src", "width", "height", "allow", "allowfullscreen", "loading", "referrerpolicy"},
+}
+
+// droppedWithContent are removed along with everything inside them, since what
+// is inside is code, markup for another context, or a way to load content.
+var droppedWithContent = []string{
+	"script", "style", "iframe", "object", "embed", "applet", "noscript", "template",
+	"title", "meta", "link", "base", "svg", "math", "frame", "frameset",
+	"form", "textarea", "select", "button",
+}
+
+// urlAttributes hold URLs and are checked with safeURL.
+var urlAttributes = []string{"href", "src", "cite", "poster"}
+
+// alignStyle is the one inline style kept: table cell alignment, which goldmark
+// writes as a style attribute.
+var alignStyle = regexp.MustCompile(`^\s*text-align:\s*(left|right|center)\s*;?\s*$`)
+
+// embedSandbox is forced onto every allowed iframe so an embed cannot navigate
+// the page or open forms on it.
+const embedSandbox = "allow-scripts allow-same-origin allow-presentation allow-popups"
+
+// SitePolicy is the policy configured by EMBED_HOSTS, a comma separated list of
+// hosts iframes may load from.
+var SitePolicy = sync.OnceValue(func() SanitizePolicy {
+	var policy SanitizePolicy
+
+	for host := range strings.SplitSeq(os.Getenv("EMBED_HOSTS"), ",") {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			policy.EmbedHosts = append(policy.EmbedHosts, host)
+		}
+	}
+
+	return policy
+})
+
+// Sanitize returns body with everything outside the allowlist removed, and a
+// description of each kind of thing it removed with how often, e.g.
+// "<script> (2)" or "onclick on <a>".
+func (p SanitizePolicy) Sanitize(body string) (string, []string) {
+	container := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+
+	nodes, err := html.ParseFragment(strings.NewReader(body), container)
+	if err != nil {
+		return "", []string{"the whole body, which could not be parsed"}
+	}
+
+	for _, n := range nodes {
+		container.AppendChild(n)
+	}
+
+	s := sanitizer{policy: p, stripped: make(map[string]int)}
+	s.clean(container)
+
+	var out strings.Builder
+
+	for n := range container.ChildNodes() {
+		err = html.Render(&out, n)
+		if err != nil {
+			return "", []string{"the whole body, which could not be rendered"}
+		}
+	}
+
+	return out.String(), s.report()
+}
+
+// sanitizer walks one body, counting what it strips.
+type sanitizer struct {
+	policy   SanitizePolicy
+	stripped map[string]int
+}
+
+// clean sanitizes the children of parent.
+func (s *sanitizer) clean(parent *html.Node) {
+	for n := parent.FirstChild; n != nil; {
+		next := n.NextSibling
+
+		switch n.Type {
+		case html.ElementNode:
+			s.element(parent, n)
+		case html.TextNode:
+		default:
+			// Comments and doctypes never render anything useful.
+			parent.RemoveChild(n)
+		}
+
+		n = next
+	}
+}
+
+// element sanitizes one element: dropping it, unwrapping it, or keeping it with
+// its attributes filtered.
+func (s *sanitizer) element(parent, n *html.Node) {
+	allowed, known := allowedElements[n.Data]
+
+	dropped := slices.Contains(droppedWithContent, n.Data)
+	if n.Data == "iframe" && s.policy.allowsEmbed(attr(n, "src")) {
+		dropped = false
+	}
+
+	if n.Data == "input" && attr(n, "type") != "checkbox" {
+		dropped = true
+	}
+
+	switch {
+	case dropped:
+		s.strip(describeElement(n))
+		parent.RemoveChild(n)
+
+		return
+	case !known:
+		s.strip("<" + n.Data + ">")
+		s.clean(n)
+
+		for child := n.FirstChild; child != nil; child = n.FirstChild {
+			n.RemoveChild(child)
+			parent.InsertBefore(child, n)
+		}
+
+		parent.RemoveChild(n)
+
+		return
+	}
+
+	s.attributes(n, allowed)
+
+	if n.Data == "iframe" {
+		n.Attr = append(n.Attr, html.Attribute{Key: "sandbox", Val: embedSandbox})
+	}
+
+	s.clean(n)
+}
+
+// attributes drops the attributes of n that are not allowed, or whose value is
+// not safe.
+func (s *sanitizer) attributes(n *html.Node, allowed []string) {
+	kept := n.Attr[:0]
+
+	for _, a := range n.Attr {
+		switch {
+		case a.Namespace != "" || (!slices.Contains(globalAttributes, a.Key) && !slices.Contains(allowed, a.Key)):
+			s.strip(a.Key + " on <" + n.Data + ">")
+		case slices.Contains(urlAttributes, a.Key) && !safeURL(a.Val):
+			s.strip("unsafe " + a.Key + " on <" + n.Data + ">")
+		case a.Key == "style" && !alignStyle.MatchString(a.Val):
+			s.strip("style on <" + n.Data + ">")
+		default:
+			kept = append(kept, a)
+		}
+	}
+
+	n.Attr = kept
+}
+
+func (s *sanitizer) strip(what string) {
+	s.stripped[what]++
+}
+
+// report lists what was stripped, sorted, with a count where it was more than
+// once.
+func (s *sanitizer) report() []string {
+	report := make([]string, 0, len(s.stripped))
+
+	for what, count := range s.stripped {
+		if count > 1 {
+			what = fmt.Sprintf("%s (%d)", what, count)
+		}
+
+		report = append(report, what)
+	}
+
+	sort.Strings(report)
+
+	return report
+}
+
+// allowsEmbed reports whether an iframe may load src.
+func (p SanitizePolicy) allowsEmbed(src string) bool {
+	u, err := url.Parse(strings.TrimSpace(src))
+	if err != nil || u.Scheme != "https" {
+		return false
+	}
+
+	return slices.Contains(p.EmbedHosts, strings.ToLower(u.Hostname()))
+}
+
+// safeURL reports whether raw is relative or uses a scheme that cannot run code.
+// The parser has already decoded entities, and url.Parse rejects the control
+// characters that browsers would ignore, so "java&#9;script:" cannot slip by.
+func safeURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
+
+// describeElement names a stripped element, with where it pointed for one
+// that loads content.
+func describeElement(n *html.Node) string {
+	src := attr(n, "src")
+	if src == "" {
+		return "<" + n.Data + ">"
+	}
+
+	u, err := url.Parse(strings.TrimSpace(src))
+	if err != nil || u.Host == "" {
+		return "<" + n.Data + ">"
+	}
+
+	return "<" + n.Data + "> from " + u.Host
+}
+
+// attr returns the value of n's attribute key, or "".
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,105 @@
+package storage_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"timterests/internal/storage"
+)
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	policy := storage.SanitizePolicy{EmbedHosts: []string{"www.youtube-nocookie.com"}}
+
+	tests := []struct {
+		name     string
+		input    string
+		want     string
+		stripped []string
+	}{
+		{
+			"keeps allowed markup",
+			`<p class="content-text">Hi <a href="/about" title="About">there</a></p>`,
+			`<p class="content-text">Hi <a href="/about" title="About">there</a></p>`,
+			nil,
+		},
+		{
+			"drops scripts with their content",
+			`<p>a</p><script>alert(1)</script><script src="https://evil.example/x.js"></script>`,
+			`<p>a</p>`,
+			[]string{"<script>", "<script> from evil.example"},
+		},
+		{
+			"drops event handlers and inline styles",
+			`<img src="/a.png" onerror="alert(1)" style="position:fixed">`,
+			`<img src="/a.png"/>`,
+			[]string{"onerror on <img>", "style on <img>"},
+		},
+		{
+			"drops script URLs, however they are spelled",
+			`<a href="javascript:alert(1)">a</a><a href="JaVaScRiPt:alert(1)">b</a><a href="java&#9;script:alert(1)">c</a>`,
+			`<a>a</a><a>b</a><a>c</a>`,
+			[]string{"unsafe href on <a> (3)"},
+		},
+		{
+			"unwraps unknown elements and keeps their text",
+			`<center><b>bold</b> text</center>`,
+			`<b>bold</b> text`,
+			[]string{"<center>"},
+		},
+		{
+			"keeps an iframe from an allowed host, sandboxed",
+			`<iframe src="https://www.youtube-nocookie.com/embed/x" onload="x()"></iframe>`,
+			`<iframe src="https://www.youtube-nocookie.com/embed/x" sandbox="allow-scripts allow-same-origin allow-presentation allow-popups"></iframe>`,
+			[]string{"onload on <iframe>"},
+		},
+		{
+			"drops an iframe from any other host",
+			`<iframe src="https://evil.example/"></iframe><iframe src="http://www.youtube-nocookie.com/embed/x"></iframe>`,
+			``,
+			[]string{"<iframe> from evil.example", "<iframe> from www.youtube-nocookie.com"},
+		},
+		{
+			"keeps task list checkboxes and cell alignment",
+			`<input checked="" disabled="" type="checkbox"><input type="text"><table><tbody><tr><td style="text-align:right">1</td></tr></tbody></table>`,
+			`<input checked="" disabled="" type="checkbox"/><table><tbody><tr><td style="text-align:right">1</td></tr></tbody></table>`,
+			[]string{"<input>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, stripped := policy.Sanitize(tt.input)
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+
+			if !slices.Equal(stripped, tt.stripped) {
+				t.Errorf("expected stripped %q, got %q", tt.stripped, stripped)
+			}
+		})
+	}
+}
+
+func TestMarkdownToHTMLSanitizes(t *testing.T) {
+	t.Parallel()
+
+	html, err := storage.MarkdownToHTML([]byte("Hello <span onclick=\"x()\">there</span>\n\n<script>alert(1)</script>\n\n[link](javascript:alert(1))"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, unwanted := range []string{"onclick", "<script", "javascript:"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("expected %q to be stripped, got %s", unwanted, html)
+		}
+	}
+
+	if !strings.Contains(html, "<span>there</span>") {
+		t.Errorf("expected allowed raw HTML to survive, got %s", html)
+	}
+}
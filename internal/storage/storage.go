@@ -1,279 +1,38 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"html/template"
 	"io"
+	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"timterests/internal/model"
+
 	"gopkg.in/yaml.v2"
 )
 
-// Storage provides storage operations with support for S3 and local filesystem.
-type Storage struct {
-	UseS3      bool
-	BucketName string
-	BaseDir    string // Directory for local storage, defaults to "storage"
-	S3Client   *s3.Client
-}
-
-// NewStorage initializes a new Storage instance.
-func NewStorage(ctx context.Context) (*Storage, error) {
-	// The USE_S3 environment variable determines whether to use S3 or local storage.
-	useS3 := os.Getenv("USE_S3") == "true"
-	bucketName := os.Getenv("AWS_BUCKET_NAME")
-	region := os.Getenv("AWS_REGION")
-
-	var baseDir string
-
-	// Find project root by looking for go.mod
-	projectRoot, err := findProjectRoot()
-	if err != nil {
-		return nil, fmt.Errorf("failed to find project root: %w", err)
-	}
-
-	baseDir = filepath.Join(projectRoot, "storage")
-
-	// Verify storage directory exists
-	_, err = os.Stat(baseDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("storage directory not found at %s", baseDir)
-		}
-
-		return nil, fmt.Errorf("failed to check storage directory: %w", err)
-	}
-
-	if useS3 {
-		if bucketName == "" || region == "" {
-			return nil, errors.New("AWS_BUCKET_NAME or AWS_REGION is not set in the environment variables")
-		}
-
-		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
-		if err != nil {
-			return nil, fmt.Errorf("unable to load SDK config, %w", err)
-		}
-
-		client := s3.NewFromConfig(cfg)
-
-		return &Storage{
-			UseS3:      true,
-			BucketName: bucketName,
-			BaseDir:    baseDir,
-			S3Client:   client,
-		}, nil
-	}
-
-	return &Storage{
-		UseS3:      false,
-		BucketName: "",
-		BaseDir:    baseDir,
-		S3Client:   nil,
-	}, nil
-}
-
-// ListObjects lists the objects in the storage.
-func (s *Storage) ListObjects(ctx context.Context, prefix string) ([]types.Object, error) {
-	if s.UseS3 {
-		return s.listS3Objects(ctx, prefix)
-	}
-
-	return s.listLocalObjects(prefix)
-}
-
-func (s *Storage) listS3Objects(ctx context.Context, prefix string) ([]types.Object, error) {
-	var (
-		err     error
-		output  *s3.ListObjectsV2Output
-		objects []types.Object
-	)
-
-	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(s.BucketName),
-		Prefix: aws.String(prefix),
-	}
-
-	objectPaginator := s3.NewListObjectsV2Paginator(s.S3Client, input)
-	for objectPaginator.HasMorePages() {
-		output, err = objectPaginator.NextPage(ctx)
-		if err != nil {
-			var noBucket *types.NoSuchBucket
-			if errors.As(err, &noBucket) {
-				log.Printf("Bucket %s does not exist.\n", s.BucketName)
-
-				return nil, noBucket
-			}
-
-			return nil, fmt.Errorf("listing S3 objects: %w", err)
-		}
-
-		objects = append(objects, output.Contents...)
-	}
-
-	// Default sort objects by date, from most current to least current
-	sort.Slice(objects, func(i, j int) bool {
-		return objects[i].LastModified.After(*objects[j].LastModified)
-	})
-
-	return objects, nil
-}
-
-// listLocalObjects lists objects previously pulled from S3 or stored manually.
-// This doesn't recursively retrieve directories, only the files from the passed in directory.
-// Subdirectories are ignored.
-func (s *Storage) listLocalObjects(prefix string) ([]types.Object, error) {
-	fullPath, err := LocalPath(s.BaseDir, prefix)
-	if err != nil {
-		return nil, fmt.Errorf("getting local path: %w", err)
-	}
-
-	_, err = os.Stat(fullPath)
-	if os.IsNotExist(err) {
-		err = os.MkdirAll(fullPath, 0750)
-		if err != nil {
-			return nil, fmt.Errorf("creating local storage directory: %w", err)
-		}
-	} else if err != nil {
-		return nil, fmt.Errorf("checking local storage directory: %w", err)
-	}
-
-	entries, err := os.ReadDir(fullPath)
-	if err != nil {
-		return nil, fmt.Errorf("reading local storage directory: %w", err)
-	}
-
-	objects := make([]types.Object, 0, len(entries))
-	for _, entry := range entries {
-		// Use IsDir() to filter out directories
-		if entry.IsDir() {
-			continue
-		}
-
-		// File info for size and mod time
-		fileInfo, err := entry.Info()
-		if err != nil {
-			return nil, fmt.Errorf("getting file info: %w", err)
-		}
-
-		keyPath, err := LocalPath(prefix, entry.Name())
-		if err != nil {
-			return nil, fmt.Errorf("getting local path: %w", err)
-		}
-
-		objects = append(objects, types.Object{
-			Key:          aws.String(keyPath),
-			LastModified: aws.Time(fileInfo.ModTime()),
-			Size:         aws.Int64(fileInfo.Size()),
-		})
-	}
-
-	// Sort by date desc
-	sort.Slice(objects, func(i, j int) bool {
-		return objects[i].LastModified.After(*objects[j].LastModified)
-	})
-
-	return objects, nil
-}
-
-// DownloadS3File downloads a file from S3 to local storage.
-func (s *Storage) DownloadS3File(ctx context.Context, objectKey string) error {
-	if !s.UseS3 {
-		// In local mode, no action needed as files are already local
-		return nil
-	}
-
-	result, err := s.S3Client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(s.BucketName),
-		Key:    aws.String(objectKey),
-	})
-	if err != nil {
-		var noKey *types.NoSuchKey
-		if errors.As(err, &noKey) {
-			log.Printf("Can't get object %s from bucket %s. No such key exists.\n", objectKey, s.BucketName)
-
-			err = noKey
-		} else {
-			log.Printf("Couldn't get object %v:%v. Here's why: %v\n", s.BucketName, objectKey, err)
-		}
-
-		return err
-	}
-
-	defer func() {
-		err := result.Body.Close()
-		if err != nil {
-			log.Printf("Failed to close S3 object body: %v", err)
+// GetPreparedFile retrieves a file and decodes it. For a document's metadata
+// key the front matter of a single-file document is decoded instead when there
+// is no .yaml.
+func GetPreparedFile(ctx context.Context, b Backend, key string, document any) error {
+	file, err := b.GetFile(ctx, key)
+	if errors.Is(err, fs.ErrNotExist) && strings.HasSuffix(key, ".yaml") {
+		meta, ok := frontMatterOf(ctx, b, key)
+		if ok {
+			file = io.NopCloser(bytes.NewReader(meta))
+			err = nil
 		}
-	}()
-
-	fileName, err := LocalPath(s.BaseDir, objectKey)
-	if err != nil {
-		return fmt.Errorf("getting local path: %w", err)
-	}
-
-	err = os.MkdirAll(filepath.Dir(fileName), 0750)
-	if err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	// #nosec G304 -- fileName is validated by LocalPath to prevent path traversal
-	file, err := os.Create(fileName)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
-
-	_, err = io.Copy(file, result.Body)
-	if err != nil {
-		return fmt.Errorf("failed to write to file: %w", err)
-	}
-
-	return nil
-}
-
-func (s *Storage) UploadFileToS3(ctx context.Context, objectKey string) error {
-	if !s.UseS3 {
-		return errors.New("storage is configured to be local, not configured to use S3")
-	}
-
-	fileName, err := LocalPath(s.BaseDir, objectKey)
-	if err != nil {
-		return fmt.Errorf("getting local path: %w", err)
-	}
-
-	// #nosec G304 -- fileName is validated by LocalPath to prevent path traversal
-	file, err := os.Open(fileName)
-	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
 	}
-	defer file.Close()
-
-	_, err = s.S3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(s.BucketName),
-		Key:    aws.String(objectKey),
-		Body:   file,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to upload to S3: %w", err)
-	}
-
-	return nil
-}
 
-// GetPreparedFile retrieves a file and decodes it.
-func (s *Storage) GetPreparedFile(ctx context.Context, key string, document any) error {
-	file, err := s.GetFile(ctx, key)
 	if err != nil {
 		return err
 	}
@@ -287,109 +46,17 @@ func (s *Storage) GetPreparedFile(ctx context.Context, key string, document any)
 	return nil
 }
 
-// GetFile ensures the file is available locally at localPath.
-func (s *Storage) GetFile(ctx context.Context, key string) (*os.File, error) {
-	localPath, err := LocalPath(s.BaseDir, key)
-	if err != nil {
-		return nil, fmt.Errorf("getting local path: %w", err)
-	}
-
-	err = os.MkdirAll(filepath.Dir(localPath), 0750)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	if s.UseS3 {
-		err := s.DownloadS3File(ctx, key)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	// #nosec G304 -- localPath is validated by LocalPath to prevent path traversal
-	file, err := os.Open(localPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-
-	return file, nil
-}
-
-// GetImage downloads an image and returns its local path (or URL path).
-func (s *Storage) GetImage(ctx context.Context, imageName string) (string, error) {
-	// imageName is expected to include the subdirectory
-	localImagePath, err := LocalPath(s.BaseDir, imageName)
-	if err != nil {
-		return "", err
-	}
-
-	if s.UseS3 {
-		err := s.DownloadS3File(ctx, imageName)
-		if err != nil {
-			log.Printf("Failed to download image: %v", err)
-
-			return localImagePath, err
-		}
-	}
-
-	// Return the URL path for accessing the image
-	webPath := "/storage/" + imageName
-
-	return webPath, nil
-}
-
-// HealthResult holds the structured health check response.
-type HealthResult struct {
-	Status    string            `json:"status"`
-	Timestamp string            `json:"ts"`
-	Checks    map[string]string `json:"checks"`
-}
-
-// Healthy returns true when overall status is "ok".
-func (h HealthResult) Healthy() bool {
-	return h.Status == "ok"
-}
-
-// Health checks storage connectivity.
-func (s *Storage) Health() HealthResult {
-	checks := make(map[string]string)
-	healthy := true
-
-	checks["storage"] = s.checkStorage()
-	if checks["storage"] != "ok" {
-		healthy = false
-	}
-
-	status := "ok"
-	if !healthy {
-		status = "degraded"
-	}
-
-	return HealthResult{
-		Status:    status,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		Checks:    checks,
-	}
-}
-
-func (s *Storage) checkStorage() string {
-	if s.UseS3 {
-		_, err := s.S3Client.ListBuckets(context.Background(), &s3.ListBucketsInput{})
-		if err != nil {
-			log.Printf("health: S3 connection down: %v", err)
-
-			return fmt.Sprintf("error: %v", err)
-		}
-
-		return "ok"
+// frontMatterOf returns the front matter of the single-file document at
+// yamlKey, if that is how it is stored.
+func frontMatterOf(ctx context.Context, b Backend, yamlKey string) ([]byte, bool) {
+	mdBytes, err := readIfExists(ctx, b, BodyKey(yamlKey))
+	if err != nil || mdBytes == nil {
+		return nil, false
 	}
 
-	_, err := os.Stat(s.BaseDir)
-	if os.IsNotExist(err) {
-		return "error: local storage directory missing"
-	}
+	meta, _, ok := SplitFrontMatter(mdBytes)
 
-	return "ok"
+	return meta, ok
 }
 
 // findProjectRoot walks up the directory tree to find the project root based on go.mod.
@@ -449,76 +116,37 @@ func DecodeFile(file io.Reader, out any) error {
 	return nil
 }
 
-// GetDocumentBodyRaw reads the Markdown body file paired with yamlKey and returns raw markdown.
-// The body file is expected at the same path as yamlKey but with a .md extension.
-func (s *Storage) GetDocumentBodyRaw(ctx context.Context, yamlKey string) (string, error) {
-	mdKey := strings.TrimSuffix(yamlKey, ".yaml") + ".md"
-
-	file, err := s.GetFile(ctx, mdKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to get body file %s: %w", mdKey, err)
-	}
-	defer file.Close()
-
-	content, err := io.ReadAll(file)
+// GetDocumentBodyRaw returns the raw Markdown body of the document at yamlKey,
+// without the front matter of a single-file document.
+func GetDocumentBodyRaw(ctx context.Context, b Backend, yamlKey string) (string, error) {
+	_, body, err := ReadDocument(ctx, b, yamlKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to read body: %w", err)
 	}
 
-	return string(content), nil
-}
-
-// WriteFile stores raw bytes at key, writing locally and, in S3 mode, uploading
-// as well. The local copy is written either way because that directory doubles
-// as the read cache — skipping it would leave the new file invisible until
-// something else pulled it down.
-func (s *Storage) WriteFile(ctx context.Context, key string, content []byte) error {
-	path, err := LocalPath(s.BaseDir, key)
-	if err != nil {
-		return fmt.Errorf("getting local path: %w", err)
-	}
-
-	err = os.MkdirAll(filepath.Dir(path), 0750)
-	if err != nil {
-		return fmt.Errorf("failed to create directory for %s: %w", key, err)
-	}
-
-	err = os.WriteFile(path, content, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to write %s: %w", key, err)
-	}
-
-	if !s.UseS3 {
-		return nil
-	}
-
-	err = s.UploadFileToS3(ctx, key)
-	if err != nil {
-		return fmt.Errorf("failed to upload %s: %w", key, err)
+	if body == nil {
+		return "", fmt.Errorf("failed to get body file %s: %w", BodyKey(yamlKey), fs.ErrNotExist)
 	}
 
-	return nil
+	return string(body), nil
 }
 
 // DeleteDocument removes both halves of a document — the .yaml metadata and the
-// .md body — in whichever storage mode is active. In S3 mode the local cache
-// copies are removed too, otherwise the deleted document keeps being served from
-// disk.
+// .md body — from whichever backend is active.
 //
 // A missing file is not an error: the goal is that the document is gone, and a
-// half-written document must still be removable.
-func (s *Storage) DeleteDocument(ctx context.Context, yamlKey string) error {
+// half-written document must still be removable. The last version is kept as a
+// revision first, so its history outlives it.
+func DeleteDocument(ctx context.Context, b Backend, yamlKey string) error {
+	_, err := SaveRevision(ctx, b, yamlKey)
+	if err != nil {
+		return err
+	}
+
 	mdKey := strings.TrimSuffix(yamlKey, ".yaml") + ".md"
 
 	for _, key := range []string{yamlKey, mdKey} {
-		if s.UseS3 {
-			err := s.deleteS3Object(ctx, key)
-			if err != nil {
-				return err
-			}
-		}
-
-		err := s.deleteLocalFile(key)
+		err = b.DeleteFile(ctx, key)
 		if err != nil {
 			return err
 		}
@@ -527,54 +155,66 @@ func (s *Storage) DeleteDocument(ctx context.Context, yamlKey string) error {
 	return nil
 }
 
-func (s *Storage) deleteS3Object(ctx context.Context, key string) error {
-	_, err := s.S3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(s.BucketName),
-		Key:    aws.String(key),
-	})
+// GetDocumentBody reads the Markdown body of the document at yamlKey, in either layout, and returns it as HTML.
+func GetDocumentBody(ctx context.Context, b Backend, yamlKey string) (string, error) {
+	html, _, err := GetDocumentBodyWithHeadings(ctx, b, yamlKey)
+
+	return html, err
+}
+
+// GetDocumentBodyWithHeadings is GetDocumentBody that also returns the body's
+// headings, for pages that show a table of contents. Wiki links in the body are
+// resolved against the other documents in b.
+func GetDocumentBodyWithHeadings(ctx context.Context, b Backend, yamlKey string) (string, []model.Heading, error) {
+	content, err := GetDocumentBodyRaw(ctx, b, yamlKey)
 	if err != nil {
-		return fmt.Errorf("failed to delete %s from S3: %w", key, err)
+		return "", nil, err
 	}
 
-	return nil
+	return siteRenderer().RenderLinked(yamlKey, []byte(content), documentResolver(ctx, b, time.Now()))
 }
 
-func (s *Storage) deleteLocalFile(key string) error {
-	path, err := LocalPath(s.BaseDir, key)
+// SetDocumentFields rewrites the given top-level fields in a document's YAML
+// metadata, leaving every other field and the field order as they were. The
+// previous version is kept as a revision, as for any other write.
+func SetDocumentFields(ctx context.Context, b Backend, yamlKey string, fields map[string]any) error {
+	metaBytes, body, err := ReadDocument(ctx, b, yamlKey)
 	if err != nil {
-		return fmt.Errorf("getting local path: %w", err)
+		return err
 	}
 
-	err = os.Remove(path)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete %s: %w", key, err)
+	if metaBytes == nil {
+		return fmt.Errorf("failed to read %s: %w", yamlKey, fs.ErrNotExist)
 	}
 
-	return nil
-}
-
-// GetDocumentBody reads the Markdown body file paired with yamlKey, converts it to HTML, and returns it.
-// The body file is expected at the same path as yamlKey but with a .md extension.
-func (s *Storage) GetDocumentBody(ctx context.Context, yamlKey string) (string, error) {
-	mdKey := strings.TrimSuffix(yamlKey, ".yaml") + ".md"
+	var meta yaml.MapSlice
 
-	file, err := s.GetFile(ctx, mdKey)
+	err = yaml.Unmarshal(metaBytes, &meta)
 	if err != nil {
-		return "", fmt.Errorf("failed to get body file %s: %w", mdKey, err)
+		return fmt.Errorf("failed to decode %s: %w", yamlKey, err)
 	}
-	defer file.Close()
 
-	content, err := io.ReadAll(file)
-	if err != nil {
-		return "", fmt.Errorf("failed to read body: %w", err)
+	for name, value := range fields {
+		found := false
+
+		for i := range meta {
+			if meta[i].Key == name {
+				meta[i].Value = value
+				found = true
+			}
+		}
+
+		if !found {
+			meta = append(meta, yaml.MapItem{Key: name, Value: value})
+		}
 	}
 
-	html, err := MarkdownToHTML(content)
+	content, err := yaml.Marshal(meta)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("failed to marshal %s: %w", yamlKey, err)
 	}
 
-	return html, nil
+	return WriteDocument(ctx, b, yamlKey, content, body)
 }
 
 // FormatFileSize formats a byte count as a human-readable string.
@@ -586,16 +226,10 @@ func FormatFileSize(size int64) string {
 	return fmt.Sprintf("%.1f KB", float64(size)/1024)
 }
 
-// WriteMarkdownDocument writes a document as two separate files:
-// - yamlPath: YAML metadata file containing title, subtitle, tags, author, etc.
-// - mdPath: Markdown body file containing the document content.
-// The "body" key in formData is written to mdPath; all other keys are written as YAML to yamlPath.
-func WriteMarkdownDocument(yamlPath, mdPath string, formData map[string]any) error {
-	err := os.MkdirAll(filepath.Dir(yamlPath), 0750)
-	if err != nil {
-		return fmt.Errorf("failed to create directories for %s: %w", yamlPath, err)
-	}
-
+// MarshalMarkdownDocument renders form data as the two halves of a document:
+// YAML metadata containing title, subtitle, tags, author, etc., and a Markdown
+// body. The "body" key becomes the Markdown; all other keys become the YAML.
+func MarshalMarkdownDocument(formData map[string]any) ([]byte, []byte, error) {
 	var body string
 
 	if bodyVal, exists := formData["body"]; exists {
@@ -603,7 +237,7 @@ func WriteMarkdownDocument(yamlPath, mdPath string, formData map[string]any) err
 
 		body, ok = bodyVal.(string)
 		if !ok {
-			return fmt.Errorf("WriteMarkdownDocument: body must be a string, got %T", bodyVal)
+			return nil, nil, fmt.Errorf("MarshalMarkdownDocument: body must be a string, got %T", bodyVal)
 		}
 	}
 
@@ -616,40 +250,59 @@ func WriteMarkdownDocument(yamlPath, mdPath string, formData map[string]any) err
 
 	fm, err := yaml.Marshal(metaData)
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	// #nosec G304 -- yamlPath comes from internal code paths, validated by callers using LocalPath
-	yf, err := os.Create(yamlPath)
+	title, _ := formData["title"].(string)
+	subtitle, _ := formData["subtitle"].(string)
+
+	tmpl, err := template.New("").Parse("# {{.Title}}\n## {{.Subtitle}}\n\n{{.Body}}")
 	if err != nil {
-		return fmt.Errorf("failed to create yaml file: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse markdown template: %w", err)
 	}
-	defer yf.Close()
 
-	_, err = yf.Write(fm)
+	var md bytes.Buffer
+
+	err = tmpl.Execute(&md, struct{ Title, Subtitle, Body string }{title, subtitle, body})
 	if err != nil {
-		return fmt.Errorf("failed to write yaml file: %w", err)
+		return nil, nil, fmt.Errorf("failed to render markdown: %w", err)
 	}
 
-	// #nosec G304 -- mdPath comes from internal code paths, validated by callers using LocalPath
-	mf, err := os.Create(mdPath)
+	return fm, md.Bytes(), nil
+}
+
+// WriteMarkdownDocument writes a document as two separate files:
+// - yamlPath: YAML metadata file containing title, subtitle, tags, author, etc.
+// - mdPath: Markdown body file containing the document content.
+// See MarshalMarkdownDocument for how formData is split between them.
+func WriteMarkdownDocument(yamlPath, mdPath string, formData map[string]any) error {
+	fm, md, err := MarshalMarkdownDocument(formData)
 	if err != nil {
-		return fmt.Errorf("failed to create markdown file: %w", err)
+		return err
 	}
-	defer mf.Close()
 
-	title, _ := formData["title"].(string)
-	subtitle, _ := formData["subtitle"].(string)
+	err = os.MkdirAll(filepath.Dir(yamlPath), 0750)
+	if err != nil {
+		return fmt.Errorf("failed to create directories for %s: %w", yamlPath, err)
+	}
 
-	tmpl, err := template.New("").Parse("# {{.Title}}\n## {{.Subtitle}}\n\n{{.Body}}")
+	err = os.WriteFile(yamlPath, fm, 0600)
 	if err != nil {
-		return fmt.Errorf("failed to parse markdown template: %w", err)
+		return fmt.Errorf("failed to write yaml file: %w", err)
 	}
 
-	err = tmpl.Execute(mf, struct{ Title, Subtitle, Body string }{title, subtitle, body})
+	err = os.WriteFile(mdPath, md, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to write markdown file: %w", err)
 	}
 
 	return nil
 }
+
+// SlugFromKey returns the slug a document is addressed by: its filename without
+// the extension, e.g. "articles/my-post.yaml" becomes "my-post".
+func SlugFromKey(key string) string {
+	name := path.Base(key)
+
+	return strings.TrimSuffix(name, path.Ext(name))
+}
@@ -3,6 +3,7 @@ package storage_test
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,17 +19,14 @@ func TestStorage(t *testing.T) {
 	t.Parallel()
 
 	t.Run("create new storage instance", func(t *testing.T) {
-		s, err := storage.NewStorage(t.Context())
+		b, err := storage.NewBackend(t.Context())
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
 
-		if s.UseS3 != false {
-			t.Errorf("Expected UseS3 to be false, got %v", s.UseS3)
-		}
-
-		if s.BucketName != "" {
-			t.Errorf("Expected empty BucketName for local storage, got %v", s.BucketName)
+		s, ok := b.(*storage.LocalBackend)
+		if !ok {
+			t.Fatalf("Expected the local backend by default, got %T", b)
 		}
 		// For local storage, BaseDir should be set
 		if s.BaseDir == "" {
@@ -209,12 +207,9 @@ func TestStorage(t *testing.T) {
 }
 
 func TestHealthOK(t *testing.T) {
-	s := &storage.Storage{
-		UseS3:   false,
-		BaseDir: t.TempDir(),
-	}
+	s := storage.NewLocalBackend(t.TempDir())
 
-	result := s.Health()
+	result := storage.Health(t.Context(), s)
 
 	if result.Status != "ok" {
 		t.Errorf("expected status 'ok', got %q", result.Status)
@@ -257,12 +252,9 @@ func TestHealthOK(t *testing.T) {
 }
 
 func TestHealthDegradedStorageDown(t *testing.T) {
-	s := &storage.Storage{
-		UseS3:   false,
-		BaseDir: "/nonexistent/path/that/does/not/exist",
-	}
+	s := storage.NewLocalBackend("/nonexistent/path/that/does/not/exist")
 
-	result := s.Health()
+	result := storage.Health(t.Context(), s)
 
 	if result.Status != "degraded" {
 		t.Errorf("expected status 'degraded', got %q", result.Status)
@@ -404,7 +396,7 @@ func TestListObjectsLocal(t *testing.T) {
 			t.Fatalf("failed to set mod time for bravo: %v", err)
 		}
 
-		s := &storage.Storage{UseS3: false, BaseDir: baseDir}
+		s := storage.NewLocalBackend(baseDir)
 
 		objects, err := s.ListObjects(context.Background(), "articles")
 		if err != nil {
@@ -415,7 +407,7 @@ func TestListObjectsLocal(t *testing.T) {
 			t.Fatalf("expected 2 objects, got %d", len(objects))
 		}
 
-		if objects[0].LastModified.Before(*objects[1].LastModified) {
+		if objects[0].LastModified.Before(objects[1].LastModified) {
 			t.Errorf("expected descending order: first=%v should be after second=%v",
 				objects[0].LastModified, objects[1].LastModified)
 		}
@@ -437,7 +429,7 @@ func TestListObjectsLocal(t *testing.T) {
 			t.Fatalf("failed to write file: %v", err)
 		}
 
-		s := &storage.Storage{UseS3: false, BaseDir: baseDir}
+		s := storage.NewLocalBackend(baseDir)
 
 		objects, err := s.ListObjects(context.Background(), "projects")
 		if err != nil {
@@ -449,11 +441,11 @@ func TestListObjectsLocal(t *testing.T) {
 		}
 	})
 
-	t.Run("creates directory if missing", func(t *testing.T) {
+	t.Run("missing directory lists nothing and is not created", func(t *testing.T) {
 		t.Parallel()
 
 		baseDir := t.TempDir()
-		s := &storage.Storage{UseS3: false, BaseDir: baseDir}
+		s := storage.NewLocalBackend(baseDir)
 
 		objects, err := s.ListObjects(context.Background(), "new-type")
 		if err != nil {
@@ -461,16 +453,12 @@ func TestListObjectsLocal(t *testing.T) {
 		}
 
 		if len(objects) != 0 {
-			t.Errorf("expected 0 objects for newly created dir, got %d", len(objects))
+			t.Errorf("expected 0 objects for a missing dir, got %d", len(objects))
 		}
 
-		info, statErr := os.Stat(filepath.Join(baseDir, "new-type"))
-		if statErr != nil {
-			t.Fatalf("expected directory to be created: %v", statErr)
-		}
-
-		if !info.IsDir() {
-			t.Error("expected a directory, not a file")
+		_, statErr := os.Stat(filepath.Join(baseDir, "new-type"))
+		if !os.IsNotExist(statErr) {
+			t.Errorf("expected listing to leave the directory uncreated, got %v", statErr)
 		}
 	})
 }
@@ -494,7 +482,7 @@ func TestGetFileLocal(t *testing.T) {
 			t.Fatalf("failed to write file: %v", err)
 		}
 
-		s := &storage.Storage{UseS3: false, BaseDir: baseDir}
+		s := storage.NewLocalBackend(baseDir)
 
 		file, err := s.GetFile(context.Background(), "articles/test.yaml")
 		if err != nil {
@@ -502,7 +490,7 @@ func TestGetFileLocal(t *testing.T) {
 		}
 		defer file.Close()
 
-		content, err := os.ReadFile(file.Name())
+		content, err := io.ReadAll(file)
 		if err != nil {
 			t.Fatalf("failed to read file: %v", err)
 		}
@@ -515,7 +503,7 @@ func TestGetFileLocal(t *testing.T) {
 	t.Run("returns error for nonexistent file", func(t *testing.T) {
 		t.Parallel()
 
-		s := &storage.Storage{UseS3: false, BaseDir: t.TempDir()}
+		s := storage.NewLocalBackend(t.TempDir())
 
 		_, err := s.GetFile(context.Background(), "articles/missing.yaml")
 		if err == nil {
@@ -530,7 +518,7 @@ func TestGetImage(t *testing.T) {
 	t.Run("local mode returns web path", func(t *testing.T) {
 		t.Parallel()
 
-		s := &storage.Storage{UseS3: false, BaseDir: t.TempDir()}
+		s := storage.NewLocalBackend(t.TempDir())
 
 		result, err := s.GetImage(context.Background(), "images/photo.jpg")
 		if err != nil {
@@ -546,7 +534,7 @@ func TestGetImage(t *testing.T) {
 	t.Run("returns error for path traversal", func(t *testing.T) {
 		t.Parallel()
 
-		s := &storage.Storage{UseS3: false, BaseDir: t.TempDir()}
+		s := storage.NewLocalBackend(t.TempDir())
 
 		_, err := s.GetImage(context.Background(), "../etc/passwd")
 		if err == nil {
@@ -555,17 +543,6 @@ func TestGetImage(t *testing.T) {
 	})
 }
 
-func TestDownloadS3FileLocalMode(t *testing.T) {
-	t.Parallel()
-
-	s := &storage.Storage{UseS3: false, BaseDir: t.TempDir()}
-
-	err := s.DownloadS3File(context.Background(), "articles/test.yaml")
-	if err != nil {
-		t.Errorf("expected no error in local mode, got %v", err)
-	}
-}
-
 func TestGetPreparedFile(t *testing.T) {
 	t.Parallel()
 
@@ -584,14 +561,14 @@ func TestGetPreparedFile(t *testing.T) {
 		t.Fatalf("failed to write yaml: %v", err)
 	}
 
-	s := &storage.Storage{UseS3: false, BaseDir: baseDir}
+	s := storage.NewLocalBackend(baseDir)
 
 	t.Run("GetPreparedFile decodes yaml", func(t *testing.T) {
 		t.Parallel()
 
 		var doc model.Document
 
-		err := s.GetPreparedFile(context.Background(), "articles/doc.yaml", &doc)
+		err := storage.GetPreparedFile(context.Background(), s, "articles/doc.yaml", &doc)
 		if err != nil {
 			t.Fatalf("GetPreparedFile error: %v", err)
 		}
@@ -620,12 +597,12 @@ func TestGetDocumentBody(t *testing.T) {
 		t.Fatalf("failed to write md file: %v", err)
 	}
 
-	s := &storage.Storage{UseS3: false, BaseDir: baseDir}
+	s := storage.NewLocalBackend(baseDir)
 
 	t.Run("GetDocumentBodyRaw returns raw markdown", func(t *testing.T) {
 		t.Parallel()
 
-		raw, err := s.GetDocumentBodyRaw(context.Background(), "articles/test.yaml")
+		raw, err := storage.GetDocumentBodyRaw(context.Background(), s, "articles/test.yaml")
 		if err != nil {
 			t.Fatalf("GetDocumentBodyRaw error: %v", err)
 		}
@@ -638,7 +615,7 @@ func TestGetDocumentBody(t *testing.T) {
 	t.Run("GetDocumentBody returns HTML", func(t *testing.T) {
 		t.Parallel()
 
-		html, err := s.GetDocumentBody(context.Background(), "articles/test.yaml")
+		html, err := storage.GetDocumentBody(context.Background(), s, "articles/test.yaml")
 		if err != nil {
 			t.Fatalf("GetDocumentBody error: %v", err)
 		}
@@ -655,7 +632,7 @@ func TestGetDocumentBody(t *testing.T) {
 	t.Run("GetDocumentBody returns error for missing file", func(t *testing.T) {
 		t.Parallel()
 
-		_, err := s.GetDocumentBody(context.Background(), "articles/nonexistent.yaml")
+		_, err := storage.GetDocumentBody(context.Background(), s, "articles/nonexistent.yaml")
 		if err == nil {
 			t.Error("expected error for missing markdown file")
 		}
@@ -670,3 +647,37 @@ func getYAMLDocument() *fstest.MapFile {
 		),
 	}
 }
+
+func TestSetDocumentFields(t *testing.T) {
+	ctx := context.Background()
+	b := storage.NewMemoryBackend()
+
+	err := b.WriteFile(ctx, "articles/post.yaml", []byte("title: Post\ndate: 2026-01-01\nstatus: draft\n"))
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	err = storage.SetDocumentFields(ctx, b, "articles/post.yaml", map[string]any{
+		"status":    "published",
+		"publishAt": "2026-02-01",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	file, err := b.GetFile(ctx, "articles/post.yaml")
+	if err != nil {
+		t.Fatalf("failed to read back: %v", err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("failed to read back: %v", err)
+	}
+
+	want := "title: Post\ndate: \"2026-01-01\"\nstatus: published\npublishAt: \"2026-02-01\"\n"
+	if string(content) != want {
+		t.Errorf("expected field order kept and values updated:\nwant %q\ngot  %q", want, content)
+	}
+}
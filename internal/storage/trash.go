@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrashPrefix is where deleted documents wait until they are restored or
+// purged. Every item sits directly under it as trash/<deleted>_<type>_<slug>
+// with the document's files as they were stored, so a single listing finds
+// everything on disk and in S3 alike.
+const TrashPrefix = "trash/"
+
+// DefaultTrashRetention is how long a deleted document is kept when no
+// retention is configured.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// TrashPurgeInterval is how often RunTrashPurge looks for expired items.
+const TrashPurgeInterval = time.Hour
+
+var (
+	// ErrTrashItemNotFound is returned for a trash ID with no stored files.
+	ErrTrashItemNotFound = errors.New("trash item not found")
+	// ErrRestoreConflict is returned when restoring would overwrite a document
+	// that has since been created under the same key.
+	ErrRestoreConflict = errors.New("a document with that name already exists")
+)
+
+// TrashItem is a deleted document waiting in the trash.
+type TrashItem struct {
+	ID      string
+	DocType string
+	Slug    string
+	Deleted time.Time
+	Size    int64
+}
+
+// Key returns the metadata key the item is restored to.
+func (t TrashItem) Key() string {
+	return t.DocType + "/" + t.Slug + ".yaml"
+}
+
+// TrashDocument moves both halves of a document into the trash and returns the
+// trash ID. The originals are only removed once the copy is safely written, and
+// removing them goes through DeleteDocument so the backend's cached copy is
+// dropped too. Like DeleteDocument, a document that is already gone is not an
+// error; there is just nothing to trash and the ID is empty.
+func TrashDocument(ctx context.Context, b Backend, yamlKey string) (string, error) {
+	docType, name, found := strings.Cut(yamlKey, "/")
+	if !found {
+		return "", fmt.Errorf("trashing %s: key has no document type", yamlKey)
+	}
+
+	id := time.Now().UTC().Format(revisionIDLayout) + "_" + docType + "_" + strings.TrimSuffix(name, ".yaml")
+
+	moved, err := copyRaw(ctx, b, yamlKey, TrashPrefix+id+".yaml")
+	if err != nil {
+		return "", fmt.Errorf("moving %s to the trash: %w", yamlKey, err)
+	}
+
+	if !moved {
+		return "", nil
+	}
+
+	err = DeleteDocument(ctx, b, yamlKey)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// ListTrash returns everything in the trash, most recently deleted first.
+func ListTrash(ctx context.Context, b Backend) ([]TrashItem, error) {
+	objects, err := b.ListObjects(ctx, TrashPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("listing trash: %w", err)
+	}
+
+	byID := make(map[string]*TrashItem)
+
+	for _, obj := range objects {
+		name := path.Base(obj.Key)
+
+		item, ok := parseTrashID(strings.TrimSuffix(name, path.Ext(name)))
+		if !ok {
+			continue
+		}
+
+		existing, seen := byID[item.ID]
+		if !seen {
+			existing = &item
+			byID[item.ID] = existing
+		}
+
+		existing.Size += obj.Size
+	}
+
+	items := make([]TrashItem, 0, len(byID))
+	for _, item := range byID {
+		items = append(items, *item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID > items[j].ID
+	})
+
+	return items, nil
+}
+
+// RestoreTrash puts a trashed document back where it was. It refuses to
+// overwrite a document created under the same key in the meantime.
+func RestoreTrash(ctx context.Context, b Backend, id string) (TrashItem, error) {
+	item, ok := parseTrashID(id)
+	if !ok {
+		return TrashItem{}, fmt.Errorf("%w: %q", ErrTrashItemNotFound, id)
+	}
+
+	yamlBytes, mdBytes, err := readRaw(ctx, b, TrashPrefix+id+".yaml")
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	if yamlBytes == nil && mdBytes == nil {
+		return TrashItem{}, fmt.Errorf("%w: %q", ErrTrashItemNotFound, id)
+	}
+
+	curYAML, curMD, err := readRaw(ctx, b, item.Key())
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	if curYAML != nil || curMD != nil {
+		return TrashItem{}, fmt.Errorf("restoring %s: %w", item.Key(), ErrRestoreConflict)
+	}
+
+	err = writeRaw(ctx, b, item.Key(), yamlBytes, mdBytes)
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	return item, PurgeTrash(ctx, b, id)
+}
+
+// PurgeTrash removes a trashed document for good.
+func PurgeTrash(ctx context.Context, b Backend, id string) error {
+	_, ok := parseTrashID(id)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrTrashItemNotFound, id)
+	}
+
+	for _, ext := range []string{".yaml", ".md"} {
+		err := b.DeleteFile(ctx, TrashPrefix+id+ext)
+		if err != nil {
+			return fmt.Errorf("purging %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// PurgeExpiredTrash removes every item deleted more than retention before now
+// and returns how many went.
+func PurgeExpiredTrash(ctx context.Context, b Backend, retention time.Duration, now time.Time) (int, error) {
+	items, err := ListTrash(ctx, b)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+
+	for _, item := range items {
+		if now.Sub(item.Deleted) < retention {
+			continue
+		}
+
+		err = PurgeTrash(ctx, b, item.ID)
+		if err != nil {
+			return purged, err
+		}
+
+		purged++
+	}
+
+	return purged, nil
+}
+
+// RunTrashPurge purges expired trash now and then every TrashPurgeInterval
+// until ctx is done.
+func RunTrashPurge(ctx context.Context, b Backend, retention time.Duration) {
+	ticker := time.NewTicker(TrashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := PurgeExpiredTrash(ctx, b, retention, time.Now())
+		if err != nil {
+			log.Printf("trash: purge failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("trash: purged %d expired item(s)", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// TrashRetention reads TRASH_RETENTION_DAYS, how many days a deleted document
+// is kept before it is purged. Zero keeps the trash until it is emptied by
+// hand; an unset or unusable value falls back to DefaultTrashRetention.
+func TrashRetention() time.Duration {
+	value := os.Getenv("TRASH_RETENTION_DAYS")
+	if value == "" {
+		return DefaultTrashRetention
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		log.Printf("ignoring TRASH_RETENTION_DAYS %q: want a whole number of days", value)
+
+		return DefaultTrashRetention
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// parseTrashID splits an ID of the form <deleted>_<type>_<slug>. Anything else,
+// including a path, is rejected so an ID cannot reach outside the trash.
+func parseTrashID(id string) (TrashItem, bool) {
+	parts := strings.SplitN(id, "_", 3)
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" || strings.ContainsAny(parts[1]+parts[2], `/\`) {
+		return TrashItem{}, false
+	}
+
+	deleted, err := time.Parse(revisionIDLayout, parts[0])
+	if err != nil {
+		return TrashItem{}, false
+	}
+
+	return TrashItem{ID: id, DocType: parts[1], Slug: parts[2], Deleted: deleted}, true
+}
@@ -0,0 +1,176 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"timterests/internal/storage"
+)
+
+func TestTrash(t *testing.T) {
+	ctx := context.Background()
+	key := "articles/post.yaml"
+
+	seed := func(t *testing.T) storage.Backend {
+		t.Helper()
+
+		b := storage.NewMemoryBackend()
+
+		err := storage.WriteDocument(ctx, b, key, []byte("title: Post\n"), []byte("body"))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+
+		return b
+	}
+
+	t.Run("trashing moves both halves out of the content directory", func(t *testing.T) {
+		b := seed(t)
+
+		id, err := storage.TrashDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		yamlBytes, mdBytes, err := storage.ReadDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if yamlBytes != nil || mdBytes != nil {
+			t.Error("expected the document to be gone from articles/")
+		}
+
+		items, err := storage.ListTrash(ctx, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(items) != 1 || items[0].ID != id || items[0].Key() != key {
+			t.Fatalf("expected one trash item for %s, got %+v", key, items)
+		}
+	})
+
+	t.Run("restore puts the document back and empties the trash", func(t *testing.T) {
+		b := seed(t)
+
+		id, err := storage.TrashDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = storage.RestoreTrash(ctx, b, id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, body, err := storage.ReadDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(body) != "body" {
+			t.Errorf("expected the body back, got %q", body)
+		}
+
+		items, err := storage.ListTrash(ctx, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(items) != 0 {
+			t.Errorf("expected an empty trash, got %d items", len(items))
+		}
+	})
+
+	t.Run("restore refuses to overwrite a newer document", func(t *testing.T) {
+		b := seed(t)
+
+		id, err := storage.TrashDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = storage.WriteDocument(ctx, b, key, []byte("title: Replacement\n"), []byte("new"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = storage.RestoreTrash(ctx, b, id)
+		if !errors.Is(err, storage.ErrRestoreConflict) {
+			t.Errorf("expected ErrRestoreConflict, got %v", err)
+		}
+
+		_, body, err := storage.ReadDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(body) != "new" {
+			t.Errorf("expected the newer document to be untouched, got %q", body)
+		}
+	})
+
+	t.Run("expired items are purged and recent ones kept", func(t *testing.T) {
+		b := seed(t)
+
+		_, err := storage.TrashDocument(ctx, b, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		purged, err := storage.PurgeExpiredTrash(ctx, b, time.Hour, time.Now())
+		if err != nil || purged != 0 {
+			t.Fatalf("expected nothing purged yet, got %d (%v)", purged, err)
+		}
+
+		purged, err = storage.PurgeExpiredTrash(ctx, b, time.Hour, time.Now().Add(2*time.Hour))
+		if err != nil || purged != 1 {
+			t.Fatalf("expected one item purged, got %d (%v)", purged, err)
+		}
+
+		items, err := storage.ListTrash(ctx, b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(items) != 0 {
+			t.Errorf("expected an empty trash, got %d items", len(items))
+		}
+	})
+
+	t.Run("IDs that are not trash items are refused", func(t *testing.T) {
+		b := seed(t)
+
+		for _, id := range []string{"", "../articles/post", "20200101T000000.000000000Z_articles_../../x"} {
+			err := storage.PurgeTrash(ctx, b, id)
+			if !errors.Is(err, storage.ErrTrashItemNotFound) {
+				t.Errorf("expected ErrTrashItemNotFound for %q, got %v", id, err)
+			}
+		}
+	})
+}
+
+func TestTrashRetention(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", storage.DefaultTrashRetention},
+		{"7", 7 * 24 * time.Hour},
+		{"0", 0},
+		{"-1", storage.DefaultTrashRetention},
+		{"a week", storage.DefaultTrashRetention},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("TRASH_RETENTION_DAYS", tt.value)
+
+		got := storage.TrashRetention()
+		if got != tt.want {
+			t.Errorf("TRASH_RETENTION_DAYS=%q: expected %v, got %v", tt.value, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"timterests/internal/model"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"gopkg.in/yaml.v2"
+)
+
+// A body links to another document with [[type/slug]], e.g. [[articles/intro]],
+// or with just [[slug]] when the slug is unique enough. A label after a pipe
+// replaces the linked document's title: [[reading-list/dune|the book]].
+//
+// Links resolve when the body is rendered, so they keep working when a
+// document's permalink scheme changes. One that names no published document is
+// rendered as its label in a span, which admins see highlighted. The span says
+// nothing of the target, which may be a draft the public is not to know of.
+
+// builtinWikiLinkTypes are the built-in document types a wiki link can point
+// at. Letters are private, so they cannot be linked.
+var builtinWikiLinkTypes = []string{"articles", "projects", "reading-list"}
+
+// customWikiLinkTypes are the schema-declared types a wiki link can point at,
+// set once the server knows which types it serves.
+var customWikiLinkTypes struct {
+	sync.RWMutex
+
+	names []string
+}
+
+// SetWikiLinkTypes makes the schema-declared types called names linkable, after
+// the built-in ones.
+func SetWikiLinkTypes(names []string) {
+	customWikiLinkTypes.Lock()
+	defer customWikiLinkTypes.Unlock()
+
+	customWikiLinkTypes.names = slices.Clone(names)
+}
+
+// WikiLinkTypes returns the document types a wiki link can point at, in the
+// order a bare slug is looked up: the built-in types, then the schema-declared
+// ones.
+func WikiLinkTypes() []string {
+	customWikiLinkTypes.RLock()
+	defer customWikiLinkTypes.RUnlock()
+
+	return slices.Concat(builtinWikiLinkTypes, customWikiLinkTypes.names)
+}
+
+// WikiResolver resolves the target of a wiki link to the document it names. ok
+// is false for a target that names nothing that can be shown.
+type WikiResolver func(target string) (link model.DocumentLink, ok bool)
+
+// wikiResolverKey carries the resolver for a render to the AST transformer.
+var wikiResolverKey = parser.NewContextKey()
+
+// kindWikiLink is the AST node kind of a wiki link that did not resolve.
+var kindWikiLink = ast.NewNodeKind("WikiLink")
+
+// wikiLink is a [[target|label]] link as parsed. Resolved links are replaced
+// with ordinary links; the ones left are broken.
+type wikiLink struct {
+	ast.BaseInline
+
+	target string
+	// labelled is set when the link gives its own label, which then wins
+	// over the linked document's title.
+	labelled bool
+}
+
+// Kind implements ast.Node.
+func (n *wikiLink) Kind() ast.NodeKind {
+	return kindWikiLink
+}
+
+// Dump implements ast.Node.
+func (n *wikiLink) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Target": n.target}, nil)
+}
+
+// ParseWikiTarget splits a wiki link target into its document type and slug.
+// The type is empty for a bare slug.
+func ParseWikiTarget(target string) (string, string) {
+	docType, slug, found := strings.Cut(strings.TrimSpace(target), "/")
+	if !found {
+		return "", docType
+	}
+
+	return docType, slug
+}
+
+// WikiLinkTargets returns the target of every wiki link in a Markdown body, in
+// order. Links inside code are not links, so they are not counted.
+func WikiLinkTargets(content []byte) []string {
+	doc := siteRenderer().md.Parser().Parse(text.NewReader(content))
+
+	var targets []string
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := n.(*wikiLink)
+		if entering && ok {
+			targets = append(targets, link.target)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return targets
+}
+
+// documentResolver resolves wiki links against the documents in b. A link to a
+// draft or scheduled document counts as broken, since the public would only
+// find a 404 behind it.
+func documentResolver(ctx context.Context, b Backend, now time.Time) WikiResolver {
+	return func(target string) (model.DocumentLink, bool) {
+		docType, slug := ParseWikiTarget(target)
+		if slug == "" || slug == "." || slug == ".." || strings.ContainsAny(slug, `/\`) {
+			return model.DocumentLink{}, false
+		}
+
+		types := WikiLinkTypes()
+		if docType != "" {
+			if !slices.Contains(types, docType) {
+				return model.DocumentLink{}, false
+			}
+
+			types = []string{docType}
+		}
+
+		for _, t := range types {
+			meta, body, err := ReadDocument(ctx, b, t+"/"+slug+".yaml")
+			if err != nil || meta == nil || body == nil {
+				continue
+			}
+
+			var doc model.Document
+
+			err = yaml.Unmarshal(meta, &doc)
+			if err != nil || !doc.IsPublished(now) {
+				continue
+			}
+
+			title := doc.Title
+			if title == "" {
+				title = slug
+			}
+
+			return model.DocumentLink{Title: title, URL: model.DocumentPath(t, slug)}, true
+		}
+
+		return model.DocumentLink{}, false
+	}
+}
+
+// wikiLinkParser parses [[target]] and [[target|label]]. It runs ahead of the
+// standard link parser, which would otherwise take the brackets.
+type wikiLinkParser struct{}
+
+// Trigger implements parser.InlineParser.
+func (wikiLinkParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+// Parse implements parser.InlineParser.
+func (wikiLinkParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	if !bytes.HasPrefix(line, []byte("[[")) {
+		return nil
+	}
+
+	end := bytes.Index(line[2:], []byte("]]"))
+	if end <= 0 {
+		return nil
+	}
+
+	inner := line[2 : 2+end]
+	if bytes.ContainsAny(inner, "[]") {
+		return nil
+	}
+
+	target, label, _ := strings.Cut(string(inner), "|")
+
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil
+	}
+
+	label = strings.TrimSpace(label)
+	labelled := label != ""
+
+	if !labelled {
+		label = target
+	}
+
+	block.Advance(end + 4)
+
+	link := &wikiLink{target: target, labelled: labelled}
+	link.AppendChild(link, ast.NewString([]byte(label)))
+
+	return link
+}
+
+// wikiLinks resolves the wiki links in a document with the render's resolver,
+// turning each one that resolves into an ordinary link.
+type wikiLinks struct{}
+
+// Transform implements parser.ASTTransformer.
+func (wikiLinks) Transform(doc *ast.Document, _ text.Reader, pc parser.Context) {
+	resolve, _ := pc.Get(wikiResolverKey).(WikiResolver)
+	if resolve == nil {
+		return
+	}
+
+	var links []*wikiLink
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := n.(*wikiLink)
+		if entering && ok {
+			links = append(links, link)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for _, wl := range links {
+		resolved, ok := resolve(wl.target)
+		if !ok {
+			continue
+		}
+
+		link := ast.NewLink()
+		link.Destination = []byte(resolved.URL)
+		link.SetAttributeString("class", []byte("wiki-link"))
+
+		// A link written without a label shows the document's title.
+		label := wl.FirstChild()
+		if !wl.labelled {
+			label = ast.NewString([]byte(resolved.Title))
+		}
+
+		link.AppendChild(link, label)
+		wl.Parent().ReplaceChild(wl.Parent(), wl, link)
+	}
+}
+
+// wikiLinkRenderer renders a broken wiki link as its label in a span, so the
+// reader sees plain text and an admin a highlighted gap. The rendered body is
+// the same for everyone, so the span does not name the target.
+type wikiLinkRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (wikiLinkRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindWikiLink, func(w util.BufWriter, _ []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			_, _ = w.WriteString("</span>")
+
+			return ast.WalkContinue, nil
+		}
+
+		_, _ = w.WriteString(`<span class="wiki-link-broken">`)
+
+		return ast.WalkContinue, nil
+	})
+}
@@ -0,0 +1,112 @@
+package storage_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"timterests/internal/storage"
+)
+
+func TestWikiLinks(t *testing.T) {
+	ctx := context.Background()
+	b := storage.NewMemoryBackend()
+
+	docs := map[string]string{
+		"articles/intro.yaml":      "title: Intro Post\n",
+		"articles/draft.yaml":      "title: Draft\nstatus: draft\n",
+		"reading-list/dune.yaml":   "title: Dune\nauthor: Frank Herbert\n",
+		"letters/private.yaml":     "title: Private\n",
+		"projects/timterests.yaml": "title: Timterests\n",
+	}
+
+	for key, meta := range docs {
+		err := storage.WriteDocument(ctx, b, key, []byte(meta), []byte("Body"))
+		if err != nil {
+			t.Fatalf("failed to seed %s: %v", key, err)
+		}
+	}
+
+	render := func(t *testing.T, body string) string {
+		t.Helper()
+
+		err := storage.WriteDocument(ctx, b, "articles/page.yaml", []byte("title: Page\n"), []byte(body))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+
+		html, err := storage.GetDocumentBody(ctx, b, "articles/page.yaml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return html
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"typed link shows the title", "[[articles/intro]]", `<a href="/articles/intro" class="wiki-link">Intro Post</a>`},
+		{"bare slug finds its type", "[[dune]]", `<a href="/books/dune" class="wiki-link">Dune</a>`},
+		{"label replaces the title", "[[timterests|this site]]", `<a href="/projects/timterests" class="wiki-link">this site</a>`},
+		{
+			"missing document is broken", "[[articles/nope|Nope]]",
+			`<span class="wiki-link-broken">Nope</span>`,
+		},
+		{
+			"draft is broken", "[[draft]]",
+			`<span class="wiki-link-broken">draft</span>`,
+		},
+		{
+			"letters cannot be linked", "[[letters/private]]",
+			`<span class="wiki-link-broken">letters/private</span>`,
+		},
+		{"code is left alone", "`[[articles/intro]]`", `<code>[[articles/intro]]</code>`},
+		{"ordinary links still work", "[intro](/articles/intro)", `<a href="/articles/intro">intro</a>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := render(t, tt.body)
+			if !strings.Contains(html, tt.want) {
+				t.Errorf("expected %s in %s", tt.want, html)
+			}
+		})
+	}
+
+	t.Run("a broken link does not name its target", func(t *testing.T) {
+		html := render(t, "[[articles/draft|a post]]")
+		if strings.Contains(html, "articles/draft") {
+			t.Errorf("expected the draft's slug kept out of %s", html)
+		}
+	})
+
+	t.Run("schema-declared types can be linked", func(t *testing.T) {
+		err := storage.WriteDocument(ctx, b, "recipes/pancakes.yaml", []byte("title: Pancakes\n"), []byte("Body"))
+		if err != nil {
+			t.Fatalf("failed to seed: %v", err)
+		}
+
+		storage.SetWikiLinkTypes([]string{"recipes"})
+		t.Cleanup(func() { storage.SetWikiLinkTypes(nil) })
+
+		for _, body := range []string{"[[recipes/pancakes]]", "[[pancakes]]"} {
+			want := `<a href="/recipes/pancakes" class="wiki-link">Pancakes</a>`
+			if html := render(t, body); !strings.Contains(html, want) {
+				t.Errorf("expected %s in %s", want, html)
+			}
+		}
+	})
+}
+
+func TestWikiLinkTargets(t *testing.T) {
+	got := storage.WikiLinkTargets([]byte("See [[articles/intro]], [[dune|the book]] and `[[not/a-link]]`.\n\n    [[indented/code]]\n"))
+
+	want := []string{"articles/intro", "dune"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}